@@ -3,15 +3,36 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+	"udemy-course-notifier/scraper"
 	"udemy-course-notifier/security"
 )
 
 type Config struct {
 	Telegram struct {
-		Token     string `yaml:"token"`
-		ChannelID string `yaml:"channel_id"`
+		Token                    string  `yaml:"token"`
+		TokenFile                string  `yaml:"token_file"`
+		ChannelID                string  `yaml:"channel_id"`
+		AdminIDs                 []int64 `yaml:"admin_ids"`
+		Timezone                 string  `yaml:"timezone"`
+		ParseMode                string  `yaml:"parse_mode"`
+		QuietHoursMode           string  `yaml:"quiet_hours_mode"`
+		DailyLimitMode           string  `yaml:"daily_limit_mode"`
+		DashboardIntervalMinutes int     `yaml:"dashboard_interval_minutes"`
+		CommandsPerMinute        int     `yaml:"commands_per_minute"`
+		CommandBurst             int     `yaml:"command_burst"`
+		RemindAllLeadHours       int     `yaml:"remind_all_lead_hours"`
+		PreviewChannelID         string  `yaml:"preview_channel_id"`
+		PreviewMode              bool    `yaml:"preview_mode"`
+		ReferralCode             string  `yaml:"referral_code"`
+		VersionAdminOnly         bool    `yaml:"version_admin_only"`
+		ChannelFailureLimit      int     `yaml:"channel_failure_limit"`
+		InterPostDelayMs         int     `yaml:"inter_post_delay_ms"`
+		DMIntervalMs             int     `yaml:"dm_interval_ms"`
+		ApprovalMode             bool    `yaml:"approval_mode"`
 	} `yaml:"telegram"`
 	
 	Scraping struct {
@@ -19,22 +40,105 @@ type Config struct {
 		SourceURLs          []string `yaml:"source_urls"`
 		UserAgent           string   `yaml:"user_agent"`
 		RateLimitDelaySeconds int    `yaml:"rate_limit_delay_seconds"`
+		RequestTimeoutSeconds int    `yaml:"request_timeout_seconds"`
+		ExcludedPathPatterns []string `yaml:"excluded_path_patterns"`
+		CircuitBreakerThreshold       int `yaml:"circuit_breaker_threshold"`
+		CircuitBreakerCooldownMinutes int `yaml:"circuit_breaker_cooldown_minutes"`
+		MinPostQualityScore           float64 `yaml:"min_post_quality_score"`
+		CategoryMinPostQualityScore   map[string]float64 `yaml:"category_min_post_quality_score"`
+		EnrichFromUdemy               bool    `yaml:"enrich_from_udemy"`
+		VerifyTrackingLinks           bool    `yaml:"verify_tracking_links"`
+		MaxResponseBytes              int64   `yaml:"max_response_bytes"`
+		AcceptDashboardRedirects      bool    `yaml:"accept_dashboard_redirects"`
+		MaxSourcesPerCycle            int     `yaml:"max_sources_per_cycle"`
+		SourceTrust                   map[string]float64 `yaml:"source_trust"`
+		FollowCoupons                 map[string]bool `yaml:"follow_coupons"`
+		CouponRetryAttempts           int     `yaml:"coupon_retry_attempts"`
+		InterleaveCategories          bool    `yaml:"interleave_categories"`
+		DedupLookbackDays             int     `yaml:"dedup_lookback_days"`
+		CouponFollowConcurrency       int     `yaml:"coupon_follow_concurrency"`
+		CategoryKeywords              map[string]string `yaml:"category_keywords"`
+		UdemyMetaTTLHours             int     `yaml:"udemy_meta_ttl_hours"`
+		MaxExpiryDays                 int     `yaml:"max_expiry_days"`
+		ReenrichPerCycle              int     `yaml:"reenrich_per_cycle"`
+		UnknownExpiry                 string  `yaml:"unknown_expiry"`
+		MinMinutesUntilExpiry         int     `yaml:"min_minutes_until_expiry"`
+		ShortExpiryMode               string  `yaml:"short_expiry_mode"`
 	} `yaml:"scraping"`
 	
 	Database struct {
-		Path string `yaml:"path"`
+		Path     string `yaml:"path"`
+		SeedFile string `yaml:"seed_file"`
 	} `yaml:"database"`
+
+	// Retention ages are in days; 0, the default, keeps rows forever so
+	// upgrading never deletes data without the operator opting in
+	Retention struct {
+		CoursesDays   int `yaml:"courses_days"`
+		DeliveredDays int `yaml:"delivered_days"`
+		FeedbackDays  int `yaml:"feedback_days"`
+	} `yaml:"retention"`
 	
 	Filters struct {
 		DefaultCategories   []string `yaml:"default_categories"`
 		MinRating          float64  `yaml:"min_rating"`
 		MaxCoursesPerHour  int      `yaml:"max_courses_per_hour"`
+		ExchangeRates      map[string]float64 `yaml:"exchange_rates"`
+		UnparseablePricePasses bool `yaml:"unparseable_price_passes"`
+		ExpiryGraceMinutes int `yaml:"expiry_grace_minutes"`
+		GlobalExcludedKeywords []string `yaml:"global_excluded_keywords"`
 	} `yaml:"filters"`
 	
 	Logging struct {
 		Level string `yaml:"level"`
 		File  string `yaml:"file"`
 	} `yaml:"logging"`
+	
+	Scoring struct {
+		Weights scraper.ScoringWeights `yaml:"weights"`
+		ABTest  struct {
+			Enabled bool                   `yaml:"enabled"`
+			Weights scraper.ScoringWeights `yaml:"weights"`
+		} `yaml:"ab_test"`
+		DedupPriority []string `yaml:"dedup_priority"`
+		DedupSynonyms map[string]string `yaml:"dedup_synonyms"`
+		DedupBySlug   bool     `yaml:"dedup_by_slug"`
+		DedupWindow   int      `yaml:"dedup_window"`
+		DedupStopWords []string `yaml:"dedup_stop_words"`
+	} `yaml:"scoring"`
+}
+
+// defaults returns a config pre-populated with values used when a setting
+// is omitted from config.yaml
+func defaults() Config {
+	var config Config
+	config.Telegram.Timezone = "UTC"
+	config.Telegram.ParseMode = "Markdown"
+	config.Telegram.QuietHoursMode = "hold"
+	config.Telegram.DailyLimitMode = "hold"
+	config.Telegram.CommandsPerMinute = 20
+	config.Telegram.CommandBurst = 5
+	config.Telegram.RemindAllLeadHours = 24
+	config.Telegram.ChannelFailureLimit = 3
+	config.Telegram.InterPostDelayMs = 2000
+	config.Telegram.DMIntervalMs = 50
+	config.Scraping.RequestTimeoutSeconds = 20
+	// Aggregator paths that never lead to a course
+	config.Scraping.ExcludedPathPatterns = []string{"/user/", "/category/", "/tag/", "/author/"}
+	config.Scraping.CircuitBreakerCooldownMinutes = 30
+	config.Scraping.MaxResponseBytes = 5 << 20
+	config.Scraping.CouponRetryAttempts = 5
+	config.Scraping.CouponFollowConcurrency = 4
+	config.Scraping.UdemyMetaTTLHours = 168
+	config.Scraping.MaxExpiryDays = 30
+	config.Scraping.ReenrichPerCycle = 10
+	config.Scraping.UnknownExpiry = "guess"
+	config.Scraping.ShortExpiryMode = "skip"
+	config.Filters.UnparseablePricePasses = true
+	config.Filters.ExpiryGraceMinutes = 60
+	config.Scoring.Weights = scraper.DefaultScoringWeights()
+	config.Scoring.ABTest.Weights = scraper.DefaultScoringWeights()
+	return config
 }
 
 func Load(configPath string) (*Config, error) {
@@ -43,7 +147,7 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
+	config := defaults()
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
@@ -57,6 +161,12 @@ func Load(configPath string) (*Config, error) {
 		config.Telegram.ChannelID = channelID
 	}
 
+	token, err := resolveToken(config.Telegram.Token, config.Telegram.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve telegram token: %w", err)
+	}
+	config.Telegram.Token = token
+
 	if err := config.validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -64,6 +174,38 @@ func Load(configPath string) (*Config, error) {
 	return &config, nil
 }
 
+// resolveToken expands "file:<path>" and "env:<name>" token indirections and
+// falls back to reading tokenFile when no token is set directly
+func resolveToken(token, tokenFile string) (string, error) {
+	switch {
+	case strings.HasPrefix(token, "file:"):
+		return readSecretFile(strings.TrimPrefix(token, "file:"))
+	case strings.HasPrefix(token, "env:"):
+		name := strings.TrimPrefix(token, "env:")
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	case token == "" && tokenFile != "":
+		return readSecretFile(tokenFile)
+	}
+	return token, nil
+}
+
+func readSecretFile(path string) (string, error) {
+	if err := security.ValidateFilePath(path); err != nil {
+		return "", fmt.Errorf("invalid token file path: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
 func (c *Config) validate() error {
 	if c.Telegram.Token == "" {
 		return fmt.Errorf("telegram token is required")
@@ -82,18 +224,109 @@ func (c *Config) validate() error {
 		return fmt.Errorf("at least one source URL is required")
 	}
 
+	if _, err := time.LoadLocation(c.Telegram.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %s: %w", c.Telegram.Timezone, err)
+	}
+
+	switch c.Telegram.ParseMode {
+	case "Markdown", "MarkdownV2", "HTML":
+	default:
+		return fmt.Errorf("invalid parse mode %q: use Markdown, MarkdownV2 or HTML", c.Telegram.ParseMode)
+	}
+
+	if c.Telegram.QuietHoursMode != "hold" && c.Telegram.QuietHoursMode != "drop" {
+		return fmt.Errorf("invalid quiet hours mode %q: use hold or drop", c.Telegram.QuietHoursMode)
+	}
+
+	if c.Telegram.DailyLimitMode != "hold" && c.Telegram.DailyLimitMode != "drop" {
+		return fmt.Errorf("invalid daily limit mode %q: use hold or drop", c.Telegram.DailyLimitMode)
+	}
+
+	for _, r := range c.Telegram.ReferralCode {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("invalid referral code %q: use letters, digits, - and _", c.Telegram.ReferralCode)
+		}
+	}
+
+	if c.Telegram.ApprovalMode && len(c.Telegram.AdminIDs) == 0 {
+		return fmt.Errorf("approval mode requires at least one admin ID")
+	}
+
+	if c.Telegram.InterPostDelayMs < 0 {
+		return fmt.Errorf("inter post delay cannot be negative")
+	}
+
+	if c.Telegram.DMIntervalMs < 0 {
+		return fmt.Errorf("DM interval cannot be negative")
+	}
+
+	if c.Telegram.ChannelFailureLimit < 0 {
+		return fmt.Errorf("channel failure limit cannot be negative")
+	}
+
+	if c.Telegram.PreviewMode && c.Telegram.PreviewChannelID == "" {
+		return fmt.Errorf("preview mode requires a preview channel ID")
+	}
+	if c.Telegram.PreviewChannelID != "" {
+		if err := security.ValidateChannelID(c.Telegram.PreviewChannelID); err != nil {
+			return fmt.Errorf("invalid preview channel ID: %w", err)
+		}
+	}
+
+	if c.Telegram.DashboardIntervalMinutes < 0 {
+		return fmt.Errorf("dashboard interval cannot be negative")
+	}
+
 	// Validate all source URLs
 	for _, url := range c.Scraping.SourceURLs {
-		if err := security.ValidateURL(url); err != nil {
+		if err := security.ValidateSourceURL(url); err != nil {
 			return fmt.Errorf("invalid source URL %s: %w", url, err)
 		}
 	}
 
+	if c.Scraping.MaxExpiryDays < 0 {
+		return fmt.Errorf("max expiry days cannot be negative")
+	}
+
+	if c.Scraping.DedupLookbackDays < 0 {
+		return fmt.Errorf("dedup lookback days cannot be negative")
+	}
+
+	if c.Scraping.ReenrichPerCycle < 0 {
+		return fmt.Errorf("reenrich per cycle cannot be negative")
+	}
+
+	if c.Scraping.UnknownExpiry != "guess" && c.Scraping.UnknownExpiry != "unknown" {
+		return fmt.Errorf("invalid unknown expiry mode %q: use guess or unknown", c.Scraping.UnknownExpiry)
+	}
+
+	if c.Scoring.DedupWindow < 0 {
+		return fmt.Errorf("dedup window cannot be negative")
+	}
+
+	if c.Scraping.MinMinutesUntilExpiry < 0 {
+		return fmt.Errorf("min minutes until expiry cannot be negative")
+	}
+
+	if c.Scraping.ShortExpiryMode != "skip" && c.Scraping.ShortExpiryMode != "hurry" {
+		return fmt.Errorf("invalid short expiry mode %q: use skip or hurry", c.Scraping.ShortExpiryMode)
+	}
+
+	if c.Retention.CoursesDays < 0 || c.Retention.DeliveredDays < 0 || c.Retention.FeedbackDays < 0 {
+		return fmt.Errorf("retention days cannot be negative")
+	}
+
 	// Validate file paths
 	if err := security.ValidateFilePath(c.Database.Path); err != nil {
 		return fmt.Errorf("invalid database path: %w", err)
 	}
 
+	if c.Database.SeedFile != "" {
+		if err := security.ValidateFilePath(c.Database.SeedFile); err != nil {
+			return fmt.Errorf("invalid seed file path: %w", err)
+		}
+	}
+
 	if c.Logging.File != "" {
 		if err := security.ValidateFilePath(c.Logging.File); err != nil {
 			return fmt.Errorf("invalid log file path: %w", err)
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name in a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveToken(t *testing.T) {
+	tokenFile := writeFile(t, "token", "  123:from-file\n")
+	t.Setenv("TEST_BOT_TOKEN", "123:from-env")
+	t.Setenv("TEST_EMPTY_TOKEN", "")
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenFile string
+		want      string
+		wantErr   bool
+	}{
+		{name: "plain token", token: "123:plain", want: "123:plain"},
+		{name: "file indirection", token: "file:" + tokenFile, want: "123:from-file"},
+		{name: "env indirection", token: "env:TEST_BOT_TOKEN", want: "123:from-env"},
+		{name: "unset env variable", token: "env:TEST_EMPTY_TOKEN", wantErr: true},
+		{name: "missing file", token: "file:" + tokenFile + ".missing", wantErr: true},
+		{name: "path traversal", token: "file:../token", wantErr: true},
+		{name: "token file fallback", tokenFile: tokenFile, want: "123:from-file"},
+		{name: "token wins over token file", token: "123:plain", tokenFile: tokenFile, want: "123:plain"},
+		{name: "nothing set", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveToken(tt.token, tt.tokenFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("token = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTokenIndirection(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "")
+	t.Setenv("TELEGRAM_CHANNEL_ID", "")
+	t.Setenv("TEST_BOT_TOKEN", "123:from-env")
+
+	path := writeFile(t, "config.yaml", `telegram:
+  token: "env:TEST_BOT_TOKEN"
+  channel_id: "@courses"
+scraping:
+  source_urls: ["https://courson.xyz/"]
+database:
+  path: "courses.db"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Telegram.Token != "123:from-env" {
+		t.Errorf("token = %q, want the value of TEST_BOT_TOKEN", cfg.Telegram.Token)
+	}
+}
+
+func TestValidatePreviewChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    bool
+		channel string
+		wantErr bool
+	}{
+		{name: "off", mode: false, channel: ""},
+		{name: "numeric channel", mode: true, channel: "-1009876543210"},
+		{name: "public channel", mode: true, channel: "@courses_staging"},
+		{name: "no channel", mode: true, channel: "", wantErr: true},
+		{name: "invalid channel", mode: true, channel: "staging channel", wantErr: true},
+		{name: "invalid channel while off", mode: false, channel: "staging channel", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaults()
+			cfg.Telegram.Token = "123:token"
+			cfg.Telegram.ChannelID = "@courses"
+			cfg.Scraping.SourceURLs = []string{"https://courson.xyz/"}
+			cfg.Database.Path = "courses.db"
+			cfg.Telegram.PreviewMode = tt.mode
+			cfg.Telegram.PreviewChannelID = tt.channel
+
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr || (err != nil && !strings.Contains(err.Error(), "preview")) {
+				t.Errorf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadLeavesRetentionOff(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "")
+	t.Setenv("TELEGRAM_CHANNEL_ID", "")
+
+	// A config written before retention existed must not start deleting rows
+	path := writeFile(t, "config.yaml", `telegram:
+  token: "123:plain"
+  channel_id: "@courses"
+scraping:
+  source_urls: ["https://courson.xyz/"]
+database:
+  path: "courses.db"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := cfg.Retention; r.CoursesDays != 0 || r.DeliveredDays != 0 || r.FeedbackDays != 0 {
+		t.Errorf("retention = %+v, want it off unless configured", r)
+	}
+}
+
+func TestLoadDedupsWholeScanByDefault(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "")
+	t.Setenv("TELEGRAM_CHANNEL_ID", "")
+
+	path := writeFile(t, "config.yaml", `telegram:
+  token: "123:plain"
+  channel_id: "@courses"
+scraping:
+  source_urls: ["https://courson.xyz/"]
+database:
+  path: "courses.db"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Scoring.DedupWindow != 0 {
+		t.Errorf("dedup window = %d, want 0 (whole scan) unless configured", cfg.Scoring.DedupWindow)
+	}
+}
@@ -0,0 +1,150 @@
+package currency
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// symbols maps currency symbols found in scraped prices to ISO 4217 codes
+var symbols = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"¥": "JPY",
+	"₹": "INR",
+	"₱": "PHP",
+	"₩": "KRW",
+	"₪": "ILS",
+	"₫": "VND",
+	"₦": "NGN",
+	"₴": "UAH",
+	"₺": "TRY",
+	"₽": "RUB",
+	"₸": "KZT",
+}
+
+// DefaultRates are approximate units of each currency per US dollar
+var DefaultRates = map[string]float64{
+	"USD": 1,
+	"EUR": 0.92,
+	"GBP": 0.79,
+	"JPY": 150,
+	"INR": 83,
+	"PHP": 56,
+	"KRW": 1350,
+	"ILS": 3.7,
+	"VND": 25000,
+	"NGN": 1500,
+	"UAH": 39,
+	"TRY": 32,
+	"RUB": 92,
+	"KZT": 450,
+}
+
+var (
+	amountRegex = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+	codeRegex   = regexp.MustCompile(`\b[A-Z]{3}\b`)
+)
+
+// Parse extracts the amount and ISO currency code from a price string such
+// as "$19.99", "19,99 €" or "USD 20". ok is false when no amount is found.
+// Prices without a recognizable currency are assumed to be in USD.
+func Parse(price string) (amount float64, code string, ok bool) {
+	match := amountRegex.FindString(price)
+	if match == "" {
+		return 0, "", false
+	}
+
+	amount, err := parseAmount(match)
+	if err != nil {
+		return 0, "", false
+	}
+
+	code = "USD"
+	if found := codeRegex.FindString(price); found != "" {
+		code = found
+	} else {
+		for symbol, symbolCode := range symbols {
+			if strings.Contains(price, symbol) {
+				code = symbolCode
+				break
+			}
+		}
+	}
+
+	return amount, code, true
+}
+
+// parseAmount reads a number whose separators may be thousands groups or a
+// decimal point in either convention: "3,499", "1,299.99" and "1.299,99". A
+// group of exactly three digits after "," or "." is a thousands group,
+// unless it is the last group and follows a different separator, as in
+// "1,234.567". Any other group is the decimal part and ends the number.
+func parseAmount(number string) (float64, error) {
+	var digits strings.Builder
+	var lastSep rune
+	for i := 0; i < len(number); {
+		sep := rune(number[i])
+		if sep != '.' && sep != ',' {
+			digits.WriteByte(number[i])
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(number) && number[end] != '.' && number[end] != ',' {
+			end++
+		}
+		group := number[i+1 : end]
+		final := end == len(number)
+
+		if len(group) == 3 && !(final && lastSep != 0 && lastSep != sep) {
+			digits.WriteString(group)
+			lastSep = sep
+			i = end
+			continue
+		}
+
+		digits.WriteByte('.')
+		digits.WriteString(group)
+		break
+	}
+	return strconv.ParseFloat(digits.String(), 64)
+}
+
+// Converter converts amounts between currencies using fixed rates
+type Converter struct {
+	rates map[string]float64 // Units of each currency per US dollar
+}
+
+// NewConverter creates a converter. Missing rates fall back to DefaultRates.
+func NewConverter(rates map[string]float64) *Converter {
+	merged := make(map[string]float64, len(DefaultRates)+len(rates))
+	for code, rate := range DefaultRates {
+		merged[code] = rate
+	}
+	for code, rate := range rates {
+		if rate > 0 {
+			merged[strings.ToUpper(code)] = rate
+		}
+	}
+	return &Converter{rates: merged}
+}
+
+// Convert converts amount from one currency to another. ok is false when
+// either currency is unknown.
+func (c *Converter) Convert(amount float64, from, to string) (float64, bool) {
+	fromRate, okFrom := c.rates[strings.ToUpper(from)]
+	toRate, okTo := c.rates[strings.ToUpper(to)]
+	if !okFrom || !okTo {
+		return 0, false
+	}
+	return amount / fromRate * toRate, true
+}
+
+// Supported reports whether the converter has a rate for the currency code
+func (c *Converter) Supported(code string) bool {
+	_, ok := c.rates[strings.ToUpper(code)]
+	return ok
+}
@@ -0,0 +1,50 @@
+package currency
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		price  string
+		amount float64
+		code   string
+		ok     bool
+	}{
+		{"$19.99", 19.99, "USD", true},
+		{"19,99 €", 19.99, "EUR", true},
+		{"USD 20", 20, "USD", true},
+		{"₹3,499", 3499, "INR", true},
+		{"$1,299.99", 1299.99, "USD", true},
+		{"1.299,99 €", 1299.99, "EUR", true},
+		{"₩1,234,567", 1234567, "KRW", true},
+		{"€1.000", 1000, "EUR", true},
+		{"$1,234.567", 1234.567, "USD", true},
+		{"£12.5", 12.5, "GBP", true},
+		{"¥2,000 JPY", 2000, "JPY", true},
+		{"Free", 0, "", false},
+		{"", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		amount, code, ok := Parse(tt.price)
+		if ok != tt.ok || amount != tt.amount || code != tt.code {
+			t.Errorf("Parse(%q) = %v, %q, %v; want %v, %q, %v", tt.price, amount, code, ok, tt.amount, tt.code, tt.ok)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	converter := NewConverter(map[string]float64{"eur": 0.5})
+
+	got, ok := converter.Convert(10, "USD", "EUR")
+	if !ok || got != 5 {
+		t.Errorf("Convert(10, USD, EUR) = %v, %v; want 5, true", got, ok)
+	}
+
+	if _, ok := converter.Convert(10, "USD", "XYZ"); ok {
+		t.Error("Convert to an unknown currency should fail")
+	}
+
+	if !converter.Supported("inr") {
+		t.Error("Supported should fall back to the default rates")
+	}
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"fmt"
+)
+
+// QueueForApproval marks a stored course as waiting for an admin to approve
+// it for the channel
+func (db *DB) QueueForApproval(courseID int) error {
+	_, err := db.conn.Exec(`INSERT OR IGNORE INTO pending_approvals (course_id) VALUES (?)`, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to queue course for approval: %w", err)
+	}
+	return nil
+}
+
+// TakePendingApproval removes a course from the approval queue and reports
+// whether it was still there, so only the first admin to act on it wins
+func (db *DB) TakePendingApproval(courseID int) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM pending_approvals WHERE course_id = ?`, courseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to take pending approval: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// ApprovalMessage is the copy of a course sent to one admin for review
+type ApprovalMessage struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+}
+
+// RecordApprovalMessage remembers the message a course was sent to an admin
+// in, so it can be updated once any admin reviews the course
+func (db *DB) RecordApprovalMessage(courseID int, chatID int64, messageID int) error {
+	_, err := db.conn.Exec(`INSERT OR REPLACE INTO approval_messages (course_id, chat_id, message_id) VALUES (?, ?, ?)`,
+		courseID, chatID, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to record approval message: %w", err)
+	}
+	return nil
+}
+
+// TakeApprovalMessages returns and forgets the review messages sent for a course
+func (db *DB) TakeApprovalMessages(courseID int) ([]ApprovalMessage, error) {
+	rows, err := db.conn.Query(`SELECT chat_id, message_id FROM approval_messages WHERE course_id = ?`, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query approval messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []ApprovalMessage
+	for rows.Next() {
+		var m ApprovalMessage
+		if err := rows.Scan(&m.ChatID, &m.MessageID); err != nil {
+			return nil, fmt.Errorf("failed to scan approval message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.conn.Exec(`DELETE FROM approval_messages WHERE course_id = ?`, courseID); err != nil {
+		return nil, fmt.Errorf("failed to delete approval messages: %w", err)
+	}
+	return messages, nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Retry budget for statements that hit a locked database: 5 attempts with
+// 25ms, 50ms, 100ms and 200ms pauses in between
+const (
+	busyRetryAttempts = 5
+	busyRetryDelay    = 25 * time.Millisecond
+)
+
+// retryingConn retries statements that fail because another connection holds
+// the SQLite lock. Statements inside a transaction are not retried, since the
+// transaction as a whole would have to be replayed.
+type retryingConn struct {
+	*sql.DB
+}
+
+func (c *retryingConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := retryBusy(func() error {
+		var err error
+		result, err = c.DB.Exec(query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (c *retryingConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := retryBusy(func() error {
+		var err error
+		rows, err = c.DB.Query(query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow retries when the query itself fails; errors raised later by Scan
+// are returned as usual
+func (c *retryingConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	retryBusy(func() error {
+		row = c.DB.QueryRow(query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// retryBusy runs op until it succeeds, fails with an error other than a busy
+// database, or runs out of attempts
+func retryBusy(op func() error) error {
+	delay := busyRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if !isBusy(err) || attempt == busyRetryAttempts {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isBusy reports whether err is SQLite's SQLITE_BUSY or SQLITE_LOCKED
+func isBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") ||
+		strings.Contains(msg, "database table is locked") ||
+		strings.Contains(msg, "SQLITE_BUSY")
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRetryBusy(t *testing.T) {
+	locked := errors.New("database is locked")
+
+	calls := 0
+	err := retryBusy(func() error {
+		calls++
+		if calls < 3 {
+			return locked
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("retryBusy = %v after %d calls, want success on the third", err, calls)
+	}
+
+	calls = 0
+	other := errors.New("no such table: courses")
+	if err := retryBusy(func() error { calls++; return other }); err != other || calls != 1 {
+		t.Errorf("retryBusy = %v after %d calls, want the error without retrying", err, calls)
+	}
+
+	calls = 0
+	if err := retryBusy(func() error { calls++; return locked }); err != locked || calls != busyRetryAttempts {
+		t.Errorf("retryBusy = %v after %d calls, want to give up after %d", err, calls, busyRetryAttempts)
+	}
+}
+
+func TestRetryingConnWaitsForLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+
+	// The driver's own busy wait is off, so only the retry can get past the lock
+	raw, err := sql.Open("sqlite3", path+"?_busy_timeout=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { raw.Close() })
+	conn := &retryingConn{raw}
+	if _, err := conn.Exec(`CREATE TABLE items (name TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+
+	locker, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { locker.Close() })
+	ctx := context.Background()
+	lock, err := locker.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Close()
+	if _, err := lock.ExecContext(ctx, `BEGIN EXCLUSIVE`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := raw.Exec(`INSERT INTO items (name) VALUES ('first')`); !isBusy(err) {
+		t.Fatalf("insert without retry = %v, want a busy error", err)
+	}
+
+	released := make(chan error, 1)
+	go func() {
+		time.Sleep(2 * busyRetryDelay)
+		_, err := lock.ExecContext(ctx, `COMMIT`)
+		released <- err
+	}()
+
+	if _, err := conn.Exec(`INSERT INTO items (name) VALUES ('second')`); err != nil {
+		t.Errorf("insert with retry failed: %v", err)
+	}
+	if err := <-released; err != nil {
+		t.Fatal(err)
+	}
+
+	var count int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM items`).Scan(&count); err != nil || count != 1 {
+		t.Errorf("items = %d, %v; want the retried insert only", count, err)
+	}
+}
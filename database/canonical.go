@@ -0,0 +1,44 @@
+package database
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CanonicalURL reduces a course URL to a stable identity by unwrapping
+// tracking links and dropping query parameters such as coupon codes
+func CanonicalURL(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	// Tracking links carry the real course URL in the murl parameter
+	if murl := parsedURL.Query().Get("murl"); murl != "" {
+		if inner, err := url.Parse(murl); err == nil && inner.Host != "" {
+			parsedURL = inner
+		}
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsedURL.Host), "www.")
+	path := strings.TrimSuffix(parsedURL.Path, "/")
+
+	return host + path
+}
+
+// CouponCode returns the couponCode parameter of a course URL, looking inside
+// tracking links. It returns "" when the URL carries no coupon.
+func CouponCode(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	if murl := parsedURL.Query().Get("murl"); murl != "" {
+		if inner, err := url.Parse(murl); err == nil && inner.Host != "" {
+			parsedURL = inner
+		}
+	}
+
+	return strings.TrimSpace(parsedURL.Query().Get("couponCode"))
+}
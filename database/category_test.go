@@ -0,0 +1,31 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateCourseCategory(t *testing.T) {
+	db := newTestDB(t)
+	course := addTestCourse(t, db, "go-basics", time.Time{})
+	other := addTestCourse(t, db, "rust-basics", time.Time{})
+
+	if found, err := db.UpdateCourseCategory(course.ID, "IT & Software"); err != nil || !found {
+		t.Fatalf("UpdateCourseCategory = %v, %v; want the course updated", found, err)
+	}
+
+	stored, err := db.GetCourse(course.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Category != "IT & Software" || stored.Title != course.Title {
+		t.Errorf("stored course = %+v, want only the category changed", stored)
+	}
+	if stored, _ := db.GetCourse(other.ID); stored.Category != "Development" {
+		t.Errorf("other course category = %q, want it untouched", stored.Category)
+	}
+
+	if found, err := db.UpdateCourseCategory(9999, "Business"); err != nil || found {
+		t.Errorf("UpdateCourseCategory for a missing course = %v, %v; want not found", found, err)
+	}
+}
@@ -0,0 +1,27 @@
+package database
+
+import "testing"
+
+func TestCertificateRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+	yes, no := true, false
+
+	for i, certificate := range []*bool{&yes, &no, nil} {
+		course := Course{
+			URL:         "https://www.udemy.com/course/docker-" + string(rune('a'+i)) + "/",
+			Title:       "Docker",
+			Certificate: certificate,
+		}
+		if err := db.AddCourse(&course); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := db.GetCourse(course.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (got.Certificate == nil) != (certificate == nil) || (got.Certificate != nil && *got.Certificate != *certificate) {
+			t.Errorf("certificate %v stored as %v", certificate, got.Certificate)
+		}
+	}
+}
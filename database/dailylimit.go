@@ -0,0 +1,34 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// DailySentCount returns how many courses were sent to a user on day
+// (YYYY-MM-DD in the user's zone). The count starts over each day.
+func (db *DB) DailySentCount(userID int64, day string) (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT CASE WHEN daily_sent_on = ? THEN COALESCE(daily_sent_count, 0) ELSE 0 END
+			  FROM user_preferences WHERE user_id = ?`, day, userID).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get daily sent count: %w", err)
+	}
+	return count, nil
+}
+
+// IncrementDailySent counts one more course sent to a user on day, resetting
+// the count when the day has changed, and returns the new count
+func (db *DB) IncrementDailySent(userID int64, day string) (int, error) {
+	_, err := db.conn.Exec(`UPDATE user_preferences
+			  SET daily_sent_count = CASE WHEN daily_sent_on = ? THEN COALESCE(daily_sent_count, 0) + 1 ELSE 1 END,
+			      daily_sent_on = ?
+			  WHERE user_id = ?`, day, day, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update daily sent count: %w", err)
+	}
+	return db.DailySentCount(userID, day)
+}
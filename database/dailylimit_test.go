@@ -0,0 +1,32 @@
+package database
+
+import "testing"
+
+func TestDailySentCountResetsEachDay(t *testing.T) {
+	db := newTestDB(t)
+	const userID = 42
+	if _, err := db.AddSubscriber(userID); err != nil {
+		t.Fatal(err)
+	}
+
+	for want := 1; want <= 3; want++ {
+		if got, err := db.IncrementDailySent(userID, "2024-07-01"); err != nil || got != want {
+			t.Fatalf("IncrementDailySent on July 1 = %d, %v; want %d", got, err, want)
+		}
+	}
+
+	// The next day starts from zero without touching the counter first
+	if got, err := db.DailySentCount(userID, "2024-07-02"); err != nil || got != 0 {
+		t.Errorf("DailySentCount on July 2 = %d, %v; want 0", got, err)
+	}
+	if got, err := db.IncrementDailySent(userID, "2024-07-02"); err != nil || got != 1 {
+		t.Errorf("IncrementDailySent on July 2 = %d, %v; want 1", got, err)
+	}
+	if got, err := db.DailySentCount(userID, "2024-07-01"); err != nil || got != 0 {
+		t.Errorf("DailySentCount on July 1 after the reset = %d, %v; want 0", got, err)
+	}
+
+	if got, err := db.DailySentCount(7, "2024-07-01"); err != nil || got != 0 {
+		t.Errorf("DailySentCount for an unknown user = %d, %v; want 0", got, err)
+	}
+}
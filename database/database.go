@@ -2,14 +2,17 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 type DB struct {
-	conn *sql.DB
+	conn        *retryingConn
+	expiryGrace time.Duration
 }
 
 type Course struct {
@@ -25,6 +28,89 @@ type Course struct {
 	PostedAt     time.Time `json:"posted_at"`
 	QualityScore float64   `json:"quality_score"`
 	StudentCount int       `json:"student_count"`
+
+	// QualityScoreAlt is set only when A/B scoring is enabled
+	QualityScoreAlt *float64 `json:"quality_score_alt,omitempty"`
+
+	// CaptionLanguages holds ISO 639-1 codes of available captions, when known
+	CaptionLanguages []string `json:"caption_languages,omitempty"`
+
+	// Language is the ISO 639-1 code of the course's spoken language, when known
+	Language string `json:"language,omitempty"`
+
+	// OriginalPrice is the struck-through pre-discount price, when listed
+	OriginalPrice string `json:"original_price,omitempty"`
+
+	// Certificate reports whether the course offers a certificate of
+	// completion; nil when unknown
+	Certificate *bool `json:"certificate,omitempty"`
+
+	// SourceURL is the aggregator the course was scraped from; empty for
+	// imported courses and ones stored before sources were recorded
+	SourceURL string `json:"source_url,omitempty"`
+}
+
+// courseColumns lists the course columns read by ScanCourse, in scan order
+var courseColumns = []string{
+	"id", "url", "title", "description", "category", "rating", "price", "discount",
+	"expires_at", "posted_at", "quality_score", "student_count", "caption_languages", "language",
+	"original_price", "certificate", "source_url",
+}
+
+// CourseColumns returns the column list for selecting a full course,
+// qualified with the given table alias when it is non-empty
+func CourseColumns(alias string) string {
+	if alias == "" {
+		return strings.Join(courseColumns, ", ")
+	}
+	qualified := make([]string, len(courseColumns))
+	for i, column := range courseColumns {
+		qualified[i] = alias + "." + column
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// RowScanner is implemented by *sql.Row and *sql.Rows
+type RowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// ScanCourse scans a row selected with CourseColumns into course. Any leading
+// destinations are scanned first, for columns selected before the course.
+func ScanCourse(row RowScanner, course *Course, leading ...interface{}) error {
+	var captionsJSON, courseLanguage, originalPrice, sourceURL sql.NullString
+	var certificate sql.NullBool
+	dest := append(leading,
+		&course.ID, &course.URL, &course.Title, &course.Description,
+		&course.Category, &course.Rating, &course.Price, &course.Discount,
+		&course.ExpiresAt, &course.PostedAt, &course.QualityScore, &course.StudentCount,
+		&captionsJSON, &courseLanguage, &originalPrice, &certificate, &sourceURL)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	course.Language = courseLanguage.String
+	course.OriginalPrice = originalPrice.String
+	course.SourceURL = sourceURL.String
+
+	course.Certificate = nil
+	if certificate.Valid {
+		course.Certificate = &certificate.Bool
+	}
+
+	course.CaptionLanguages = nil
+	if captionsJSON.Valid && captionsJSON.String != "" {
+		json.Unmarshal([]byte(captionsJSON.String), &course.CaptionLanguages)
+	}
+	return nil
+}
+
+// nullableJSON encodes a list as JSON, or NULL when empty
+func nullableJSON(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(values)
+	return string(data)
 }
 
 type UserPreference struct {
@@ -49,7 +135,7 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	db := &DB{conn: conn}
+	db := &DB{conn: &retryingConn{conn}}
 	if err := db.createTables(); err != nil {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
@@ -71,7 +157,15 @@ func (db *DB) createTables() error {
 			expires_at DATETIME,
 			posted_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			quality_score REAL DEFAULT 0,
-			student_count INTEGER DEFAULT 0
+			student_count INTEGER DEFAULT 0,
+			quality_score_alt REAL,
+			caption_languages TEXT,
+			language TEXT,
+			original_price TEXT,
+			certificate INTEGER,
+			source_url TEXT,
+			enrich_attempts INTEGER DEFAULT 0,
+			enriched_at DATETIME
 		)`,
 		
 		`CREATE TABLE IF NOT EXISTS user_preferences (
@@ -80,7 +174,24 @@ func (db *DB) createTables() error {
 			keywords TEXT,
 			excluded_keywords TEXT,
 			min_rating REAL DEFAULT 0.0,
-			language TEXT DEFAULT 'en'
+			language TEXT DEFAULT 'en',
+			caption_language TEXT,
+			max_price REAL DEFAULT 0,
+			currency TEXT,
+			timezone TEXT,
+			quiet_start TEXT,
+			quiet_end TEXT,
+			remind_all INTEGER DEFAULT 0,
+			remind_all_sent_on TEXT,
+			max_per_day INTEGER DEFAULT 0,
+			daily_sent_count INTEGER DEFAULT 0,
+			daily_sent_on TEXT,
+			show_expired INTEGER DEFAULT 0,
+			digest_sort TEXT,
+			required_keywords TEXT,
+			require_certificate INTEGER DEFAULT 0,
+			page_size INTEGER DEFAULT 0,
+			subscribed INTEGER DEFAULT 0
 		)`,
 		
 		`CREATE TABLE IF NOT EXISTS wishlist (
@@ -88,6 +199,11 @@ func (db *DB) createTables() error {
 			user_id INTEGER NOT NULL,
 			course_id INTEGER NOT NULL,
 			added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_known_price TEXT,
+			last_known_discount TEXT,
+			expiry_reminded INTEGER DEFAULT 0,
+			expiry_reminded_for DATETIME,
+			alert_attempts INTEGER DEFAULT 0,
 			FOREIGN KEY (course_id) REFERENCES courses(id),
 			UNIQUE(user_id, course_id)
 		)`,
@@ -99,6 +215,89 @@ func (db *DB) createTables() error {
 			FOREIGN KEY (course_id) REFERENCES courses(id),
 			PRIMARY KEY (user_id, course_id)
 		)`,
+		
+		`CREATE TABLE IF NOT EXISTS reminders (
+			user_id INTEGER NOT NULL,
+			course_id INTEGER NOT NULL,
+			remind_at DATETIME NOT NULL,
+			attempts INTEGER DEFAULT 0,
+			FOREIGN KEY (course_id) REFERENCES courses(id),
+			PRIMARY KEY (user_id, course_id)
+		)`,
+		
+		`CREATE INDEX IF NOT EXISTS idx_reminders_remind_at ON reminders(remind_at)`,
+		
+		`CREATE TABLE IF NOT EXISTS pending_coupons (
+			url TEXT PRIMARY KEY,
+			source_url TEXT NOT NULL,
+			course_json TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_try DATETIME NOT NULL,
+			gave_up_at DATETIME
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS bot_state (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS course_feedback (
+			user_id INTEGER NOT NULL,
+			course_id INTEGER NOT NULL,
+			vote INTEGER NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id),
+			PRIMARY KEY (user_id, course_id)
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS delivered (
+			user_id INTEGER NOT NULL,
+			course_id INTEGER NOT NULL,
+			delivered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			read INTEGER NOT NULL DEFAULT 0,
+			FOREIGN KEY (course_id) REFERENCES courses(id),
+			PRIMARY KEY (user_id, course_id)
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS udemy_meta (
+			url TEXT PRIMARY KEY,
+			title TEXT,
+			image TEXT,
+			level TEXT,
+			caption_languages TEXT,
+			language TEXT,
+			certificate INTEGER,
+			updated_at DATETIME NOT NULL
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS held_notifications (
+			user_id INTEGER NOT NULL,
+			course_id INTEGER NOT NULL,
+			held_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			attempts INTEGER DEFAULT 0,
+			FOREIGN KEY (course_id) REFERENCES courses(id),
+			PRIMARY KEY (user_id, course_id)
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS pending_approvals (
+			course_id INTEGER PRIMARY KEY,
+			queued_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id)
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS source_stats (
+			source_url TEXT PRIMARY KEY,
+			coupon_attempts INTEGER DEFAULT 0,
+			coupon_resolved INTEGER DEFAULT 0
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS approval_messages (
+			course_id INTEGER NOT NULL,
+			chat_id INTEGER NOT NULL,
+			message_id INTEGER NOT NULL,
+			FOREIGN KEY (course_id) REFERENCES courses(id),
+			PRIMARY KEY (course_id, chat_id)
+		)`,
 	}
 
 	for _, query := range queries {
@@ -107,16 +306,138 @@ func (db *DB) createTables() error {
 		}
 	}
 
+	return db.migrate()
+}
+
+// migrate adds columns introduced after a table was first created
+func (db *DB) migrate() error {
+	// Rows made by preference commands alone predate the subscribed flag
+	hadSubscribed, err := db.hasColumn("user_preferences", "subscribed")
+	if err != nil {
+		return err
+	}
+	hadRemindedFor, err := db.hasColumn("wishlist", "expiry_reminded_for")
+	if err != nil {
+		return err
+	}
+
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"courses", "quality_score_alt", "REAL"},
+		{"wishlist", "last_known_price", "TEXT"},
+		{"wishlist", "last_known_discount", "TEXT"},
+		{"courses", "caption_languages", "TEXT"},
+		{"user_preferences", "caption_language", "TEXT"},
+		{"user_preferences", "max_price", "REAL DEFAULT 0"},
+		{"user_preferences", "currency", "TEXT"},
+		{"user_preferences", "timezone", "TEXT"},
+		{"user_preferences", "quiet_start", "TEXT"},
+		{"user_preferences", "quiet_end", "TEXT"},
+		{"user_preferences", "remind_all", "INTEGER DEFAULT 0"},
+		{"user_preferences", "remind_all_sent_on", "TEXT"},
+		{"wishlist", "expiry_reminded", "INTEGER DEFAULT 0"},
+		{"courses", "language", "TEXT"},
+		{"udemy_meta", "language", "TEXT"},
+		{"delivered", "read", "INTEGER NOT NULL DEFAULT 0"},
+		{"user_preferences", "max_per_day", "INTEGER DEFAULT 0"},
+		{"user_preferences", "daily_sent_count", "INTEGER DEFAULT 0"},
+		{"user_preferences", "daily_sent_on", "TEXT"},
+		{"user_preferences", "show_expired", "INTEGER DEFAULT 0"},
+		{"user_preferences", "digest_sort", "TEXT"},
+		{"user_preferences", "required_keywords", "TEXT"},
+		{"user_preferences", "require_certificate", "INTEGER DEFAULT 0"},
+		{"user_preferences", "page_size", "INTEGER DEFAULT 0"},
+		{"courses", "enrich_attempts", "INTEGER DEFAULT 0"},
+		{"courses", "enriched_at", "DATETIME"},
+		{"courses", "original_price", "TEXT"},
+		{"courses", "certificate", "INTEGER"},
+		{"udemy_meta", "certificate", "INTEGER"},
+		{"user_preferences", "subscribed", "INTEGER DEFAULT 0"},
+		{"pending_coupons", "gave_up_at", "DATETIME"},
+		{"held_notifications", "attempts", "INTEGER DEFAULT 0"},
+		{"reminders", "attempts", "INTEGER DEFAULT 0"},
+		{"wishlist", "expiry_reminded_for", "DATETIME"},
+		{"courses", "source_url", "TEXT"},
+		{"wishlist", "alert_attempts", "INTEGER DEFAULT 0"},
+	}
+
+	for _, c := range columns {
+		if err := db.addColumnIfMissing(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	if !hadSubscribed {
+		// Keep groups and users who saved a filter subscribed. Only saving a
+		// filter writes required_keywords or non-empty filter lists.
+		query := `UPDATE user_preferences SET subscribed = 1
+				  WHERE user_id < 0 OR required_keywords IS NOT NULL
+				     OR categories != 'null' OR keywords != 'null' OR excluded_keywords != 'null'`
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to backfill subscriptions: %w", err)
+		}
+	}
+
+	if !hadRemindedFor {
+		// Courses already listed in a digest count as reminded for their
+		// current expiry; a later renewal re-arms them.
+		query := `UPDATE wishlist SET expiry_reminded_for =
+				  (SELECT expires_at FROM courses WHERE courses.id = wishlist.course_id)
+				  WHERE expiry_reminded = 1`
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to backfill wishlist reminders: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// hasColumn reports whether table has a column named column
+func (db *DB) hasColumn(table, column string) (bool, error) {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+func (db *DB) addColumnIfMissing(table, column, definition string) error {
+	exists, err := db.hasColumn(table, column)
+	if err != nil || exists {
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
 	return nil
 }
 
 func (db *DB) AddCourse(course *Course) error {
-	query := `INSERT INTO courses (url, title, description, category, rating, price, discount, expires_at, quality_score, student_count) 
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO courses (url, title, description, category, rating, price, discount, expires_at, quality_score, student_count, quality_score_alt, caption_languages, language, original_price, certificate, source_url) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	result, err := db.conn.Exec(query, course.URL, course.Title, course.Description, 
 		course.Category, course.Rating, course.Price, course.Discount, course.ExpiresAt,
-		course.QualityScore, course.StudentCount)
+		course.QualityScore, course.StudentCount, course.QualityScoreAlt, nullableJSON(course.CaptionLanguages),
+		course.Language, course.OriginalPrice, course.Certificate, course.SourceURL)
 	if err != nil {
 		return fmt.Errorf("failed to insert course: %w", err)
 	}
@@ -130,6 +451,38 @@ func (db *DB) AddCourse(course *Course) error {
 	return nil
 }
 
+// AddCourses inserts courses in a single transaction, skipping any whose URL
+// is already stored, and returns how many were inserted
+func (db *DB) AddCourses(courses []Course) (int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT OR IGNORE INTO courses (url, title, description, category, rating, price, discount, expires_at, quality_score, student_count, quality_score_alt, caption_languages, language, original_price, certificate, source_url) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	inserted := 0
+	for _, course := range courses {
+		result, err := tx.Exec(query, course.URL, course.Title, course.Description,
+			course.Category, course.Rating, course.Price, course.Discount, course.ExpiresAt,
+			course.QualityScore, course.StudentCount, course.QualityScoreAlt, nullableJSON(course.CaptionLanguages),
+		course.Language, course.OriginalPrice, course.Certificate, course.SourceURL)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert course %s: %w", course.URL, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			inserted++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit courses: %w", err)
+	}
+	return inserted, nil
+}
+
 func (db *DB) CourseExists(url string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM courses WHERE url = ?)`
@@ -137,46 +490,133 @@ func (db *DB) CourseExists(url string) (bool, error) {
 	return exists, err
 }
 
-func (db *DB) CleanupOldCourses(daysOld int) error {
-	query := `DELETE FROM courses WHERE posted_at < datetime('now', '-' || ? || ' days')`
-	_, err := db.conn.Exec(query, daysOld)
+// CoursePostedAt returns when the course with this URL was stored, and
+// whether it exists at all
+func (db *DB) CoursePostedAt(url string) (time.Time, bool, error) {
+	var postedAt time.Time
+	err := db.conn.QueryRow(`SELECT posted_at FROM courses WHERE url = ?`, url).Scan(&postedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to cleanup old courses: %w", err)
+		return time.Time{}, false, fmt.Errorf("failed to get course posted time: %w", err)
+	}
+	return postedAt, true, nil
+}
+
+// RefreshCourse replaces the stored details of a course that has come round
+// again with its freshly scraped ones, and marks it as posted now. The
+// course's ID is set to the stored row's.
+func (db *DB) RefreshCourse(course *Course) error {
+	query := `UPDATE courses
+			  SET title = ?, description = ?, category = ?, rating = ?, price = ?, discount = ?, expires_at = ?,
+			      quality_score = ?, student_count = ?, quality_score_alt = ?, caption_languages = ?, language = ?,
+			      original_price = ?, certificate = ?, source_url = ?, posted_at = CURRENT_TIMESTAMP
+			  WHERE url = ?`
+
+	_, err := db.conn.Exec(query, course.Title, course.Description, course.Category, course.Rating,
+		course.Price, course.Discount, course.ExpiresAt, course.QualityScore, course.StudentCount,
+		course.QualityScoreAlt, nullableJSON(course.CaptionLanguages), course.Language, course.OriginalPrice,
+		course.Certificate, course.SourceURL, course.URL)
+	if err != nil {
+		return fmt.Errorf("failed to refresh course: %w", err)
+	}
+
+	var id int
+	if err := db.conn.QueryRow(`SELECT id FROM courses WHERE url = ?`, course.URL).Scan(&id); err != nil {
+		return fmt.Errorf("failed to get refreshed course ID: %w", err)
 	}
+	course.ID = id
 	return nil
 }
 
 func (db *DB) GetRecentCourses(limit int) ([]Course, error) {
-	query := `SELECT id, url, title, description, category, rating, price, discount, expires_at, posted_at, quality_score, student_count 
+	var courses []Course
+	err := db.IterateRecentCourses(limit, func(course Course) error {
+		courses = append(courses, course)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+// IterateRecentCourses calls fn for up to limit courses, newest first, one
+// row at a time so large limits don't hold every course in memory. It stops
+// at the first error fn returns and passes that error back unchanged.
+func (db *DB) IterateRecentCourses(limit int, fn func(Course) error) error {
+	query := `SELECT ` + CourseColumns("") + ` 
 			  FROM courses ORDER BY posted_at DESC LIMIT ?`
 	
 	rows, err := db.conn.Query(query, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query courses: %w", err)
+		return fmt.Errorf("failed to query courses: %w", err)
 	}
 	defer rows.Close()
 
-	var courses []Course
 	for rows.Next() {
 		var course Course
-		err := rows.Scan(&course.ID, &course.URL, &course.Title, &course.Description,
-			&course.Category, &course.Rating, &course.Price, &course.Discount,
-			&course.ExpiresAt, &course.PostedAt, &course.QualityScore, &course.StudentCount)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan course: %w", err)
+		if err := ScanCourse(rows, &course); err != nil {
+			return fmt.Errorf("failed to scan course: %w", err)
+		}
+		if err := fn(course); err != nil {
+			return err
 		}
-		courses = append(courses, course)
 	}
 
-	return courses, nil
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read courses: %w", err)
+	}
+	return nil
+}
+
+func (db *DB) GetCourse(courseID int) (*Course, error) {
+	query := `SELECT ` + CourseColumns("") + ` 
+			  FROM courses WHERE id = ?`
+	
+	var course Course
+	err := ScanCourse(db.conn.QueryRow(query, courseID), &course)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	
+	return &course, nil
 }
 
+// UpdateCourseCategory corrects a course's category. It reports whether the
+// course exists.
+func (db *DB) UpdateCourseCategory(courseID int, category string) (bool, error) {
+	result, err := db.conn.Exec(`UPDATE courses SET category = ? WHERE id = ?`, category, courseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update course category: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// AddToWishlist saves a course for a user, noting its current price for
+// wishlist alerts. It fails with sql.ErrNoRows if the course isn't stored.
 func (db *DB) AddToWishlist(userID int64, courseID int) error {
-	query := `INSERT INTO wishlist (user_id, course_id) VALUES (?, ?)`
-	_, err := db.conn.Exec(query, userID, courseID)
+	query := `INSERT OR IGNORE INTO wishlist (user_id, course_id, last_known_price, last_known_discount)
+			  SELECT ?, id, price, discount FROM courses WHERE id = ?`
+	result, err := db.conn.Exec(query, userID, courseID)
 	if err != nil {
 		return fmt.Errorf("failed to add to wishlist: %w", err)
 	}
+	if added, err := result.RowsAffected(); err != nil || added > 0 {
+		return err
+	}
+
+	// Nothing inserted: either it was already saved or the course is gone
+	var exists bool
+	if err := db.conn.QueryRow(`SELECT EXISTS(SELECT 1 FROM courses WHERE id = ?)`, courseID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check course: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("failed to add course %d to wishlist: %w", courseID, sql.ErrNoRows)
+	}
 	return nil
 }
 
@@ -190,7 +630,7 @@ func (db *DB) RemoveFromWishlist(userID int64, courseID int) error {
 }
 
 func (db *DB) IgnoreCourse(userID int64, courseID int) error {
-	query := `INSERT INTO ignored_courses (user_id, course_id) VALUES (?, ?)`
+	query := `INSERT OR IGNORE INTO ignored_courses (user_id, course_id) VALUES (?, ?)`
 	_, err := db.conn.Exec(query, userID, courseID)
 	if err != nil {
 		return fmt.Errorf("failed to ignore course: %w", err)
@@ -198,6 +638,55 @@ func (db *DB) IgnoreCourse(userID int64, courseID int) error {
 	return nil
 }
 
+// ClearIgnored removes every course a user marked "Not Interested", so they
+// can be sent again, and returns how many were removed
+func (db *DB) ClearIgnored(userID int64) (int, error) {
+	result, err := db.conn.Exec(`DELETE FROM ignored_courses WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear ignored courses: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return int(affected), nil
+}
+
+// GetIgnoredCourses returns a page of the courses a user marked "Not
+// Interested", most recently ignored first
+func (db *DB) GetIgnoredCourses(userID int64, limit, offset int) ([]Course, error) {
+	query := `SELECT ` + CourseColumns("c") + `
+			  FROM ignored_courses i
+			  INNER JOIN courses c ON c.id = i.course_id
+			  WHERE i.user_id = ?
+			  ORDER BY i.ignored_at DESC, c.id DESC
+			  LIMIT ? OFFSET ?`
+
+	rows, err := db.conn.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ignored courses: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []Course
+	for rows.Next() {
+		var course Course
+		if err := ScanCourse(rows, &course); err != nil {
+			return nil, fmt.Errorf("failed to scan course: %w", err)
+		}
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
+// UnignoreCourse removes one course from a user's "Not Interested" list and
+// reports whether it was there
+func (db *DB) UnignoreCourse(userID int64, courseID int) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM ignored_courses WHERE user_id = ? AND course_id = ?`, userID, courseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unignore course: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
 func (db *DB) IsIgnored(userID int64, courseID int) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM ignored_courses WHERE user_id = ? AND course_id = ?)`
@@ -219,4 +708,110 @@ func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
 
 func (db *DB) Close() error {
 	return db.conn.Close()
-}
\ No newline at end of file
+}
+
+// RescoreCourses recomputes the quality score of every stored course with the
+// given scoring function and returns how many rows changed. All updates are
+// applied in a single transaction.
+func (db *DB) RescoreCourses(score func(course *Course) float64) (int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id, title, COALESCE(description, ''), COALESCE(rating, 0), quality_score, student_count,
+			COALESCE(source_url, '') FROM courses`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query courses: %w", err)
+	}
+
+	var courses []Course
+	for rows.Next() {
+		var course Course
+		if err := rows.Scan(&course.ID, &course.Title, &course.Description, &course.Rating,
+			&course.QualityScore, &course.StudentCount, &course.SourceURL); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan course: %w", err)
+		}
+		courses = append(courses, course)
+	}
+	rows.Close()
+
+	changed := 0
+	for i := range courses {
+		newScore := score(&courses[i])
+		if newScore == courses[i].QualityScore {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE courses SET quality_score = ? WHERE id = ?`, newScore, courses[i].ID); err != nil {
+			return 0, fmt.Errorf("failed to update quality score: %w", err)
+		}
+		changed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit rescore: %w", err)
+	}
+
+	return changed, nil
+}
+
+// GetTopCourses returns stored courses best quality first and newest among
+// equals, for paging through results. A non-empty category keeps only that
+// category (case-insensitive) and days > 0 keeps only courses posted in the
+// last days days. Expired courses are left out unless includeExpired is set.
+func (db *DB) GetTopCourses(category string, days, limit, offset int, includeExpired bool) ([]Course, error) {
+	query := `SELECT ` + CourseColumns("") + ` 
+			  FROM courses
+			  WHERE (? = '' OR LOWER(category) = LOWER(?))
+			    AND (? <= 0 OR posted_at >= datetime('now', '-' || ? || ' days'))
+			    AND (? OR ` + notExpiredCondition("expires_at") + `)
+			  ORDER BY quality_score DESC, posted_at DESC, id DESC
+			  LIMIT ? OFFSET ?`
+
+	rows, err := db.conn.Query(query, category, category, days, days,
+		includeExpired, db.expiryCutoff(time.Now()), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top courses: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []Course
+	for rows.Next() {
+		var course Course
+		if err := ScanCourse(rows, &course); err != nil {
+			return nil, fmt.Errorf("failed to scan course: %w", err)
+		}
+		courses = append(courses, course)
+	}
+
+	return courses, rows.Err()
+}
+
+// DistinctCategories returns up to limit categories of stored courses, most
+// common first, for offering as choices
+func (db *DB) DistinctCategories(limit int) ([]string, error) {
+	query := `SELECT category FROM courses
+			  WHERE category IS NOT NULL AND category != ''
+			  GROUP BY category
+			  ORDER BY COUNT(*) DESC, category
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []string
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestDB opens a fresh database in a temporary directory
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// addTestCourse stores a course with the given slug and expiry and returns it
+func addTestCourse(t *testing.T, db *DB, slug string, expiresAt time.Time) Course {
+	t.Helper()
+	course := Course{
+		URL:          "https://www.udemy.com/course/" + slug + "/",
+		Title:        "Course " + slug,
+		Category:     "Development",
+		Price:        "Free",
+		ExpiresAt:    expiresAt,
+		QualityScore: 50,
+	}
+	if err := db.AddCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	return course
+}
+
+// setPostedAt backdates a stored course by the given number of days
+func setPostedAt(t *testing.T, db *DB, courseID, daysAgo int) {
+	t.Helper()
+	_, err := db.conn.Exec(`UPDATE courses SET posted_at = datetime('now', ?) WHERE id = ?`,
+		fmt.Sprintf("-%d days", daysAgo), courseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,132 @@
+package database
+
+import "fmt"
+
+// GetSubscriberIDs returns the users and groups that asked for courses, by
+// saving a filter or subscribing a group. Setting other preferences, such as
+// a timezone, doesn't subscribe anyone.
+func (db *DB) GetSubscriberIDs() ([]int64, error) {
+	rows, err := db.conn.Query(`SELECT user_id FROM user_preferences WHERE subscribed = 1 ORDER BY user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// MarkDelivered records that a course was sent to a user
+func (db *DB) MarkDelivered(userID int64, courseID int) error {
+	query := `INSERT OR IGNORE INTO delivered (user_id, course_id) VALUES (?, ?)`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to mark course delivered: %w", err)
+	}
+	return nil
+}
+
+// WasDelivered reports whether a course has already been sent to a user
+func (db *DB) WasDelivered(userID int64, courseID int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM delivered WHERE user_id = ? AND course_id = ?)`
+	err := db.conn.QueryRow(query, userID, courseID).Scan(&exists)
+	return exists, err
+}
+
+// PruneDelivered removes delivery records older than daysOld
+func (db *DB) PruneDelivered(daysOld int) error {
+	query := `DELETE FROM delivered WHERE delivered_at < datetime('now', '-' || ? || ' days')`
+	_, err := db.conn.Exec(query, daysOld)
+	if err != nil {
+		return fmt.Errorf("failed to prune delivered courses: %w", err)
+	}
+	return nil
+}
+
+// MarkRead marks a delivered course as read, e.g. once the user acts on it
+func (db *DB) MarkRead(userID int64, courseID int) error {
+	query := `UPDATE delivered SET read = 1 WHERE user_id = ? AND course_id = ?`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to mark course read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every course delivered to a user as read
+func (db *DB) MarkAllRead(userID int64) (int, error) {
+	result, err := db.conn.Exec(`UPDATE delivered SET read = 1 WHERE user_id = ? AND read = 0`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark courses read: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return int(affected), nil
+}
+
+// UnreadCount returns how many delivered courses a user has not acted on yet
+func (db *DB) UnreadCount(userID int64) (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM delivered WHERE user_id = ? AND read = 0`, userID).Scan(&count)
+	return count, err
+}
+
+// AddSubscriber registers a chat, such as a group, to receive matching
+// courses, with an empty filter if it has none. It reports false if it was
+// already subscribed; its filter is left unchanged either way.
+func (db *DB) AddSubscriber(chatID int64) (bool, error) {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, subscribed)
+			  VALUES (?, 'null', 'null', 'null', 1)
+			  ON CONFLICT(user_id) DO UPDATE SET subscribed = 1 WHERE subscribed IS NOT 1`
+	result, err := db.conn.Exec(query, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add subscriber: %w", err)
+	}
+	added, err := result.RowsAffected()
+	return added > 0, err
+}
+
+// Unsubscribe stops sending courses to a user, keeping their preferences for
+// when they subscribe again. It reports false if they weren't subscribed.
+func (db *DB) Unsubscribe(userID int64) (bool, error) {
+	result, err := db.conn.Exec(`UPDATE user_preferences SET subscribed = 0 WHERE user_id = ? AND subscribed = 1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return changed > 0, db.dropHeldNotifications(userID)
+}
+
+// RemoveSubscriber stops sending courses to a chat and forgets its filter.
+// It reports false if the chat was not registered.
+func (db *DB) RemoveSubscriber(chatID int64) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM user_preferences WHERE user_id = ?`, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove subscriber: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, db.dropHeldNotifications(chatID)
+}
+
+// dropHeldNotifications forgets the notifications held for a chat that no
+// longer wants courses, so they aren't sent if it subscribes again
+func (db *DB) dropHeldNotifications(chatID int64) error {
+	if _, err := db.conn.Exec(`DELETE FROM held_notifications WHERE user_id = ?`, chatID); err != nil {
+		return fmt.Errorf("failed to drop held notifications: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelivered(t *testing.T) {
+	db := newTestDB(t)
+	course := addTestCourse(t, db, "go-basics", time.Time{})
+
+	if delivered, err := db.WasDelivered(1, course.ID); err != nil || delivered {
+		t.Fatalf("WasDelivered before delivery = %v, %v", delivered, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := db.MarkDelivered(1, course.ID); err != nil {
+			t.Fatalf("MarkDelivered attempt %d: %v", i+1, err)
+		}
+	}
+	if delivered, err := db.WasDelivered(1, course.ID); err != nil || !delivered {
+		t.Errorf("WasDelivered after delivery = %v, %v", delivered, err)
+	}
+	if delivered, _ := db.WasDelivered(2, course.ID); delivered {
+		t.Error("delivery to one user counted for another")
+	}
+
+	// Only records older than the cutoff are pruned
+	if err := db.PruneDelivered(30); err != nil {
+		t.Fatal(err)
+	}
+	if delivered, _ := db.WasDelivered(1, course.ID); !delivered {
+		t.Error("a fresh delivery record was pruned")
+	}
+	if _, err := db.conn.Exec(`UPDATE delivered SET delivered_at = datetime('now', '-31 days')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PruneDelivered(30); err != nil {
+		t.Fatal(err)
+	}
+	if delivered, _ := db.WasDelivered(1, course.ID); delivered {
+		t.Error("a delivery record past the cutoff was kept")
+	}
+}
+
+func TestUnreadCount(t *testing.T) {
+	db := newTestDB(t)
+	var courses []Course
+	for _, slug := range []string{"one", "two", "three"} {
+		course := addTestCourse(t, db, slug, time.Time{})
+		courses = append(courses, course)
+		if err := db.MarkDelivered(1, course.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.MarkDelivered(2, courses[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	unread := func(userID int64) int {
+		t.Helper()
+		count, err := db.UnreadCount(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	if got := unread(1); got != 3 {
+		t.Errorf("unread = %d, want 3", got)
+	}
+
+	// Acting on a course reads it; a course never delivered changes nothing
+	if err := db.MarkRead(1, courses[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.MarkRead(1, 999); err != nil {
+		t.Fatal(err)
+	}
+	if got := unread(1); got != 2 {
+		t.Errorf("unread after reading one = %d, want 2", got)
+	}
+
+	cleared, err := db.MarkAllRead(1)
+	if err != nil || cleared != 2 {
+		t.Errorf("MarkAllRead = %d, %v; want 2", cleared, err)
+	}
+	if got := unread(1); got != 0 {
+		t.Errorf("unread after marking all read = %d, want 0", got)
+	}
+	if got := unread(2); got != 1 {
+		t.Errorf("another user's unread = %d, want 1", got)
+	}
+}
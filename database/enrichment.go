@@ -0,0 +1,60 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// Re-enrichment limits: a course with missing details is retried at most
+// this many times, no more than once a day
+const (
+	maxEnrichAttempts   = 3
+	enrichRetryInterval = "-1 day"
+)
+
+// GetUnderEnrichedCourses returns unexpired courses stored without a
+// category, rating or student count that are due for another enrichment
+// attempt, newest first
+func (db *DB) GetUnderEnrichedCourses(limit int) ([]Course, error) {
+	query := `SELECT ` + CourseColumns("") + `
+			  FROM courses
+			  WHERE (COALESCE(category, '') IN ('', 'General') OR COALESCE(rating, 0) = 0 OR COALESCE(student_count, 0) = 0)
+			  AND COALESCE(enrich_attempts, 0) < ?
+			  AND (enriched_at IS NULL OR enriched_at < datetime('now', ?))
+			  AND ` + notExpiredCondition("expires_at") + `
+			  ORDER BY posted_at DESC, id DESC
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, maxEnrichAttempts, enrichRetryInterval, db.expiryCutoff(time.Now()), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query under-enriched courses: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []Course
+	for rows.Next() {
+		var course Course
+		if err := ScanCourse(rows, &course); err != nil {
+			return nil, fmt.Errorf("failed to scan course: %w", err)
+		}
+		courses = append(courses, course)
+	}
+
+	return courses, rows.Err()
+}
+
+// SaveEnrichment stores the details filled in by a re-enrichment attempt and
+// counts the attempt, whether or not anything was found
+func (db *DB) SaveEnrichment(course *Course) error {
+	query := `UPDATE courses
+			  SET category = ?, rating = ?, student_count = ?, quality_score = ?, caption_languages = ?, language = ?,
+			      certificate = ?, enrich_attempts = COALESCE(enrich_attempts, 0) + 1, enriched_at = CURRENT_TIMESTAMP
+			  WHERE id = ?`
+
+	_, err := db.conn.Exec(query, course.Category, course.Rating, course.StudentCount, course.QualityScore,
+		nullableJSON(course.CaptionLanguages), course.Language, course.Certificate, course.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save enrichment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetUnderEnrichedCourses(t *testing.T) {
+	db := newTestDB(t)
+
+	complete := Course{
+		URL:          "https://www.udemy.com/course/complete/",
+		Title:        "Course complete",
+		Category:     "Development",
+		Rating:       4.6,
+		StudentCount: 1200,
+	}
+	if err := db.AddCourse(&complete); err != nil {
+		t.Fatal(err)
+	}
+	noRating := addTestCourse(t, db, "no-rating", time.Time{})
+	setPostedAt(t, db, noRating.ID, 2)
+	general := Course{
+		URL:          "https://www.udemy.com/course/general/",
+		Title:        "Course general",
+		Category:     "General",
+		Rating:       4.2,
+		StudentCount: 300,
+	}
+	if err := db.AddCourse(&general); err != nil {
+		t.Fatal(err)
+	}
+	setPostedAt(t, db, general.ID, 1)
+	addTestCourse(t, db, "expired", time.Now().Add(-time.Hour))
+
+	ids := func(limit int) []int {
+		t.Helper()
+		courses, err := db.GetUnderEnrichedCourses(limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int
+		for _, course := range courses {
+			ids = append(ids, course.ID)
+		}
+		return ids
+	}
+
+	// Newest first, skipping complete and expired courses
+	if got := ids(10); !equalInts(got, []int{general.ID, noRating.ID}) {
+		t.Errorf("under-enriched = %v, want [%d %d]", got, general.ID, noRating.ID)
+	}
+	if got := ids(1); !equalInts(got, []int{general.ID}) {
+		t.Errorf("under-enriched with limit 1 = %v, want [%d]", got, general.ID)
+	}
+
+	// An attempt, successful or not, rests the course for a day
+	if err := db.SaveEnrichment(&noRating); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(10); !equalInts(got, []int{general.ID}) {
+		t.Errorf("under-enriched after an attempt = %v, want [%d]", got, general.ID)
+	}
+	if _, err := db.conn.Exec(`UPDATE courses SET enriched_at = datetime('now', '-2 days') WHERE id = ?`, noRating.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(10); !equalInts(got, []int{general.ID, noRating.ID}) {
+		t.Errorf("under-enriched a day after an attempt = %v, want [%d %d]", got, general.ID, noRating.ID)
+	}
+
+	// Courses are given up on after maxEnrichAttempts
+	if _, err := db.conn.Exec(`UPDATE courses SET enrich_attempts = ? WHERE id = ?`, maxEnrichAttempts, noRating.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(10); !equalInts(got, []int{general.ID}) {
+		t.Errorf("under-enriched after %d attempts = %v, want [%d]", maxEnrichAttempts, got, general.ID)
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SetExpiryGrace sets how long past its estimated expiry a course is still
+// treated as available. Expiry times are guesses, so a small grace keeps
+// still-valid coupons from disappearing the moment the estimate passes.
+func (db *DB) SetExpiryGrace(grace time.Duration) {
+	db.expiryGrace = grace
+}
+
+// ExpiryGrace returns the configured expiry grace period
+func (db *DB) ExpiryGrace() time.Duration {
+	return db.expiryGrace
+}
+
+// IsExpired reports whether a course expiring at expiresAt should be treated
+// as expired at now, allowing for grace. An unknown expiry never expires.
+func IsExpired(expiresAt, now time.Time, grace time.Duration) bool {
+	if expiresAt.IsZero() {
+		return false
+	}
+	return !expiresAt.Add(grace).After(now)
+}
+
+// ExpiresWithin reports whether a course's coupon runs out less than window
+// after now. Courses with an unknown expiry, and always-free courses that
+// need no coupon, never do.
+func ExpiresWithin(course *Course, window time.Duration, now time.Time) bool {
+	if window <= 0 || course.ExpiresAt.IsZero() || IsAlwaysFree(course) {
+		return false
+	}
+	return course.ExpiresAt.Before(now.Add(window))
+}
+
+// IsAlwaysFree reports whether a course is free without a coupon, so its
+// listed expiry doesn't limit who can enroll
+func IsAlwaysFree(course *Course) bool {
+	return IsFreePrice(course.Price, course.Discount) &&
+		!strings.Contains(strings.ToLower(course.URL), "couponcode")
+}
+
+// notExpiredCondition returns a WHERE condition on an expires_at column that
+// matches courses not yet expired at the time bound to its placeholder (see
+// expiryCutoff). Unknown expiries, stored as NULL or the zero time, match.
+// The column has numeric affinity, so it is compared as text to spot year 1.
+func notExpiredCondition(column string) string {
+	return fmt.Sprintf("(%[1]s IS NULL OR CAST(%[1]s AS TEXT) < '1000' OR julianday(%[1]s) > julianday(?))", column)
+}
+
+// expiryCutoff is the value for notExpiredCondition's placeholder: courses
+// expiring after it are still available at now, allowing for the grace period
+func (db *DB) expiryCutoff(now time.Time) string {
+	return now.Add(-db.expiryGrace).UTC().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsExpiredGraceBoundary(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	grace := time.Hour
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{"unknown expiry", time.Time{}, false},
+		{"not yet expired", now.Add(time.Minute), false},
+		{"within grace", now.Add(-59 * time.Minute), false},
+		{"grace just ended", now.Add(-time.Hour), true},
+		{"past grace", now.Add(-2 * time.Hour), true},
+	}
+	for _, tt := range tests {
+		if got := IsExpired(tt.expiresAt, now, grace); got != tt.want {
+			t.Errorf("%s: IsExpired = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if !IsExpired(now.Add(-time.Second), now, 0) {
+		t.Error("without grace a course is expired as soon as its expiry passes")
+	}
+}
+
+func TestReadQueriesHonorExpiryGrace(t *testing.T) {
+	db := newTestDB(t)
+	db.SetExpiryGrace(time.Hour)
+	inGrace := addTestCourse(t, db, "in-grace", time.Now().Add(-30*time.Minute))
+	addTestCourse(t, db, "past-grace", time.Now().Add(-90*time.Minute))
+
+	courses, err := db.GetTopCourses("", 0, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 1 || courses[0].ID != inGrace.ID {
+		t.Errorf("got %d courses, want only the one within grace", len(courses))
+	}
+}
+
+func TestReadQueriesKeepUnknownExpiry(t *testing.T) {
+	db := newTestDB(t)
+	unknown := addTestCourse(t, db, "unknown-expiry", time.Time{})
+	addTestCourse(t, db, "expired", time.Now().Add(-48*time.Hour))
+
+	courses, err := db.GetTopCourses("", 0, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 1 || courses[0].ID != unknown.ID {
+		t.Errorf("got %d courses, want only the one with no known expiry", len(courses))
+	}
+}
+
+func TestExpiresWithinBoundary(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	window := time.Hour
+	coupon := func(expiresAt time.Time) *Course {
+		return &Course{
+			URL:       "https://www.udemy.com/course/go-basics/?couponCode=FREE",
+			Price:     "Free",
+			Discount:  "100% off",
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		course *Course
+		want   bool
+	}{
+		{"just inside the window", coupon(now.Add(59 * time.Minute)), true},
+		{"exactly the window away", coupon(now.Add(time.Hour)), false},
+		{"past the window", coupon(now.Add(61 * time.Minute)), false},
+		{"already expired", coupon(now.Add(-time.Minute)), true},
+		{"unknown expiry", coupon(time.Time{}), false},
+		{"always free", &Course{URL: "https://www.udemy.com/course/go-basics/", Price: "Free", ExpiresAt: now.Add(time.Minute)}, false},
+		{"discounted without a coupon code", &Course{URL: "https://www.udemy.com/course/go-basics/", Price: "$9.99", Discount: "85% off", ExpiresAt: now.Add(time.Minute)}, true},
+	}
+	for _, tt := range tests {
+		if got := ExpiresWithin(tt.course, window, now); got != tt.want {
+			t.Errorf("%s: ExpiresWithin = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if ExpiresWithin(coupon(now.Add(time.Minute)), 0, now) {
+		t.Error("a zero window flags courses")
+	}
+}
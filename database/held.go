@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+)
+
+// HeldNotification is a course notification kept back during a user's quiet hours
+type HeldNotification struct {
+	UserID   int64  `json:"user_id"`
+	Course   Course `json:"course"`
+	Attempts int    `json:"attempts"` // Failed delivery attempts so far
+}
+
+// HoldNotification queues a course for a user until their quiet hours end
+func (db *DB) HoldNotification(userID int64, courseID int) error {
+	query := `INSERT OR IGNORE INTO held_notifications (user_id, course_id) VALUES (?, ?)`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to hold notification: %w", err)
+	}
+	return nil
+}
+
+// GetHeldUserIDs returns the subscribed users who have held notifications
+func (db *DB) GetHeldUserIDs() ([]int64, error) {
+	rows, err := db.conn.Query(`SELECT DISTINCT h.user_id FROM held_notifications h
+			  INNER JOIN user_preferences p ON p.user_id = h.user_id
+			  WHERE p.subscribed = 1 ORDER BY h.user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query held users: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan held user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// GetHeldNotifications returns a user's held notifications with their
+// course, oldest first
+func (db *DB) GetHeldNotifications(userID int64) ([]HeldNotification, error) {
+	query := `SELECT h.user_id, COALESCE(h.attempts, 0), ` + CourseColumns("c") + `
+			  FROM held_notifications h
+			  INNER JOIN courses c ON c.id = h.course_id
+			  WHERE h.user_id = ?
+			  ORDER BY h.held_at ASC`
+
+	rows, err := db.conn.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query held notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var held []HeldNotification
+	for rows.Next() {
+		var h HeldNotification
+		if err := ScanCourse(rows, &h.Course, &h.UserID, &h.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan held notification: %w", err)
+		}
+		held = append(held, h)
+	}
+
+	return held, rows.Err()
+}
+
+// RecordHeldFailure counts a failed attempt to deliver a held notification
+func (db *DB) RecordHeldFailure(userID int64, courseID int) error {
+	query := `UPDATE held_notifications SET attempts = attempts + 1 WHERE user_id = ? AND course_id = ?`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to record held notification failure: %w", err)
+	}
+	return nil
+}
+
+// DeleteHeldNotification removes a held notification once it is sent or dropped
+func (db *DB) DeleteHeldNotification(userID int64, courseID int) error {
+	query := `DELETE FROM held_notifications WHERE user_id = ? AND course_id = ?`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to delete held notification: %w", err)
+	}
+	return nil
+}
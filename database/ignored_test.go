@@ -0,0 +1,58 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGetIgnoredCourses(t *testing.T) {
+	db := newTestDB(t)
+	const userID, otherID = 42, 7
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		course := addTestCourse(t, db, fmt.Sprintf("course-%d", i), time.Time{})
+		if err := db.IgnoreCourse(userID, course.ID); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, course.ID)
+	}
+	notIgnored := addTestCourse(t, db, "kept", time.Time{})
+	if err := db.IgnoreCourse(otherID, notIgnored.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	page := func(limit, offset int) []int {
+		courses, err := db.GetIgnoredCourses(userID, limit, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int
+		for _, course := range courses {
+			got = append(got, course.ID)
+		}
+		return got
+	}
+
+	// Ignored in the same second, so the newest course ID comes first
+	if got, want := page(2, 0), []int{ids[2], ids[1]}; !equalInts(got, want) {
+		t.Errorf("first page = %v, want %v", got, want)
+	}
+	if got, want := page(2, 2), []int{ids[0]}; !equalInts(got, want) {
+		t.Errorf("second page = %v, want %v", got, want)
+	}
+	if got := page(2, 4); len(got) != 0 {
+		t.Errorf("page past the end = %v, want none", got)
+	}
+
+	if restored, err := db.UnignoreCourse(userID, ids[1]); err != nil || !restored {
+		t.Fatalf("UnignoreCourse = %v, %v; want restored", restored, err)
+	}
+	if restored, err := db.UnignoreCourse(userID, ids[1]); err != nil || restored {
+		t.Errorf("second UnignoreCourse = %v, %v; want nothing to restore", restored, err)
+	}
+	if got, want := page(10, 0), []int{ids[2], ids[0]}; !equalInts(got, want) {
+		t.Errorf("after un-ignoring = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIterateRecentCourses(t *testing.T) {
+	db := newTestDB(t)
+	var newestFirst []int
+	for daysAgo := 4; daysAgo >= 1; daysAgo-- {
+		course := addTestCourse(t, db, fmt.Sprintf("course-%d", daysAgo), time.Time{})
+		setPostedAt(t, db, course.ID, daysAgo)
+		newestFirst = append([]int{course.ID}, newestFirst...)
+	}
+
+	var visited []int
+	err := db.IterateRecentCourses(3, func(course Course) error {
+		visited = append(visited, course.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalInts(visited, newestFirst[:3]) {
+		t.Errorf("visited %v, want the newest three %v", visited, newestFirst[:3])
+	}
+
+	recent, err := db.GetRecentCourses(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recentIDs []int
+	for _, course := range recent {
+		recentIDs = append(recentIDs, course.ID)
+	}
+	if !equalInts(visited, recentIDs) {
+		t.Errorf("iterator visited %v, GetRecentCourses returned %v", visited, recentIDs)
+	}
+}
+
+func TestIterateRecentCoursesStopsOnError(t *testing.T) {
+	db := newTestDB(t)
+	for i := 0; i < 4; i++ {
+		addTestCourse(t, db, fmt.Sprintf("course-%d", i), time.Time{})
+	}
+
+	stop := errors.New("stop")
+	visits := 0
+	err := db.IterateRecentCourses(10, func(course Course) error {
+		visits++
+		if visits == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("IterateRecentCourses = %v, want the callback's error unchanged", err)
+	}
+	if visits != 2 {
+		t.Errorf("callback ran %d times, want it to stop after the error on the 2nd", visits)
+	}
+}
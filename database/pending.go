@@ -0,0 +1,111 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PendingCoupon is a coupon link that could not be followed, kept with the
+// listing details so it can be retried in a later scan
+type PendingCoupon struct {
+	URL       string    `json:"url"`        // Coupon page URL on the aggregator
+	SourceURL string    `json:"source_url"` // Page the listing was found on
+	Course    Course    `json:"course"`     // Listing details; URL is filled in on success
+	Attempts  int       `json:"attempts"`
+	NextTry   time.Time `json:"next_try"`
+}
+
+// AddPendingCoupon queues a coupon link for retry. A link already queued
+// keeps its existing attempt count and schedule, and a link given up on
+// stays given up.
+func (db *DB) AddPendingCoupon(p PendingCoupon) error {
+	courseJSON, err := json.Marshal(p.Course)
+	if err != nil {
+		return fmt.Errorf("failed to encode pending coupon: %w", err)
+	}
+
+	query := `INSERT OR IGNORE INTO pending_coupons (url, source_url, course_json, attempts, next_try)
+			  VALUES (?, ?, ?, ?, ?)`
+	_, err = db.conn.Exec(query, p.URL, p.SourceURL, string(courseJSON), p.Attempts, p.NextTry.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to add pending coupon: %w", err)
+	}
+	return nil
+}
+
+// GetDuePendingCoupons returns up to limit queued coupons whose retry time has come
+func (db *DB) GetDuePendingCoupons(now time.Time, limit int) ([]PendingCoupon, error) {
+	query := `SELECT url, source_url, course_json, attempts, next_try
+			  FROM pending_coupons
+			  WHERE gave_up_at IS NULL AND next_try <= ?
+			  ORDER BY next_try ASC
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending coupons: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []PendingCoupon
+	for rows.Next() {
+		var p PendingCoupon
+		var courseJSON string
+		if err := rows.Scan(&p.URL, &p.SourceURL, &courseJSON, &p.Attempts, &p.NextTry); err != nil {
+			return nil, fmt.Errorf("failed to scan pending coupon: %w", err)
+		}
+		json.Unmarshal([]byte(courseJSON), &p.Course)
+		pending = append(pending, p)
+	}
+
+	return pending, rows.Err()
+}
+
+// ReschedulePendingCoupon records a failed retry and when to try next
+func (db *DB) ReschedulePendingCoupon(url string, attempts int, nextTry time.Time) error {
+	query := `UPDATE pending_coupons SET attempts = ?, next_try = ? WHERE url = ?`
+	_, err := db.conn.Exec(query, attempts, nextTry.UTC(), url)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule pending coupon: %w", err)
+	}
+	return nil
+}
+
+// GiveUpPendingCoupon stops retrying a coupon link. The row is kept as a
+// tombstone so the link is neither queued nor followed again.
+func (db *DB) GiveUpPendingCoupon(url string) error {
+	_, err := db.conn.Exec(`UPDATE pending_coupons SET gave_up_at = CURRENT_TIMESTAMP WHERE url = ?`, url)
+	if err != nil {
+		return fmt.Errorf("failed to give up pending coupon: %w", err)
+	}
+	return nil
+}
+
+// IsCouponGivenUp reports whether retries of a coupon link were exhausted
+func (db *DB) IsCouponGivenUp(url string) (bool, error) {
+	var givenUp bool
+	query := `SELECT EXISTS(SELECT 1 FROM pending_coupons WHERE url = ? AND gave_up_at IS NOT NULL)`
+	err := db.conn.QueryRow(query, url).Scan(&givenUp)
+	return givenUp, err
+}
+
+// PruneGivenUpCoupons removes tombstones older than daysOld, letting those
+// links be tried again should they still be listed
+func (db *DB) PruneGivenUpCoupons(daysOld int) error {
+	query := `DELETE FROM pending_coupons WHERE gave_up_at < datetime('now', '-' || ? || ' days')`
+	_, err := db.conn.Exec(query, daysOld)
+	if err != nil {
+		return fmt.Errorf("failed to prune given-up coupons: %w", err)
+	}
+	return nil
+}
+
+// DeletePendingCoupon removes a coupon from the retry queue
+func (db *DB) DeletePendingCoupon(url string) error {
+	_, err := db.conn.Exec(`DELETE FROM pending_coupons WHERE url = ?`, url)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending coupon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// PopularCourse is a course with its recent feedback counts
+type PopularCourse struct {
+	Course
+	Saves      int `json:"saves"`      // Users who saved it to their wishlist
+	Dismissals int `json:"dismissals"` // Users who marked it not interested
+}
+
+// AddFeedback records a user's feedback on a course: 1 when they saved it to
+// their wishlist, -1 when they marked it not interested. It replaces any
+// earlier feedback by the same user.
+func (db *DB) AddFeedback(userID int64, courseID int, vote int) error {
+	query := `INSERT OR REPLACE INTO course_feedback (user_id, course_id, vote) VALUES (?, ?, ?)`
+	_, err := db.conn.Exec(query, userID, courseID, vote)
+	if err != nil {
+		return fmt.Errorf("failed to add feedback: %w", err)
+	}
+	return nil
+}
+
+// GetPopularCourses ranks courses posted in the last `days` days that got
+// feedback in the same window by their net feedback, saves minus dismissals.
+// It returns nothing before anyone has reacted; see GetTopCourses for a
+// fallback.
+// Expired courses are left out unless includeExpired is set.
+func (db *DB) GetPopularCourses(days, limit int, includeExpired bool) ([]PopularCourse, error) {
+	query := `SELECT
+			  (SELECT COUNT(*) FROM course_feedback f
+			   WHERE f.course_id = c.id AND f.vote > 0 AND f.created_at >= datetime('now', '-' || ? || ' days')) AS saves,
+			  (SELECT COUNT(*) FROM course_feedback f
+			   WHERE f.course_id = c.id AND f.vote < 0 AND f.created_at >= datetime('now', '-' || ? || ' days')) AS dismissals,
+			  ` + CourseColumns("c") + `
+			  FROM courses c
+			  WHERE c.posted_at >= datetime('now', '-' || ? || ' days') AND (? OR ` + notExpiredCondition("c.expires_at") + `)
+			    AND EXISTS (SELECT 1 FROM course_feedback f
+			                WHERE f.course_id = c.id AND f.created_at >= datetime('now', '-' || ? || ' days'))
+			  ORDER BY (saves - dismissals) DESC, c.quality_score DESC, c.id DESC
+			  LIMIT ?`
+
+	rows, err := db.conn.Query(query, days, days, days, includeExpired, db.expiryCutoff(time.Now()), days, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query popular courses: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []PopularCourse
+	for rows.Next() {
+		var p PopularCourse
+		if err := ScanCourse(rows, &p.Course, &p.Saves, &p.Dismissals); err != nil {
+			return nil, fmt.Errorf("failed to scan popular course: %w", err)
+		}
+		courses = append(courses, p)
+	}
+
+	return courses, rows.Err()
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetPopularCourses(t *testing.T) {
+	db := newTestDB(t)
+	loved := addTestCourse(t, db, "loved", time.Time{})
+	mixed := addTestCourse(t, db, "mixed", time.Time{})
+	disliked := addTestCourse(t, db, "disliked", time.Time{})
+	addTestCourse(t, db, "unseen", time.Time{})
+	old := addTestCourse(t, db, "old", time.Time{})
+	setPostedAt(t, db, old.ID, 10)
+
+	if courses, err := db.GetPopularCourses(7, 10, false); err != nil || len(courses) != 0 {
+		t.Fatalf("before any feedback got %d courses, %v; want none", len(courses), err)
+	}
+
+	feedback := []struct {
+		userID int64
+		course Course
+		vote   int
+	}{
+		{1, loved, 1}, {2, loved, 1}, {3, loved, 1},
+		{1, mixed, 1}, {2, mixed, 1}, {3, mixed, -1},
+		{1, disliked, -1},
+		{1, old, 1}, {2, old, 1}, {3, old, 1}, {4, old, 1},
+		// A user changing their mind replaces their earlier feedback
+		{4, disliked, 1}, {4, disliked, -1},
+	}
+	for _, f := range feedback {
+		if err := db.AddFeedback(f.userID, f.course.ID, f.vote); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	courses, err := db.GetPopularCourses(7, 10, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		id                int
+		saves, dismissals int
+	}{
+		{loved.ID, 3, 0},
+		{mixed.ID, 2, 1},
+		{disliked.ID, 0, 2},
+	}
+	if len(courses) != len(want) {
+		t.Fatalf("got %d courses, want %d", len(courses), len(want))
+	}
+	for i, w := range want {
+		got := courses[i]
+		if got.ID != w.id || got.Saves != w.saves || got.Dismissals != w.dismissals {
+			t.Errorf("rank %d = #%d (%d saves, %d dismissals), want #%d (%d, %d)",
+				i+1, got.ID, got.Saves, got.Dismissals, w.id, w.saves, w.dismissals)
+		}
+	}
+}
+
+func TestGetPopularCoursesIncludeExpired(t *testing.T) {
+	db := newTestDB(t)
+	current := addTestCourse(t, db, "current", time.Now().Add(time.Hour))
+	expired := addTestCourse(t, db, "expired", time.Now().Add(-time.Hour))
+	for _, course := range []Course{current, expired} {
+		if err := db.AddFeedback(1, course.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, includeExpired := range []bool{false, true} {
+		courses, err := db.GetPopularCourses(7, 10, includeExpired)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int
+		for _, course := range courses {
+			ids = append(ids, course.ID)
+		}
+		want := []int{current.ID}
+		if includeExpired {
+			want = []int{current.ID, expired.ID}
+		}
+		if !equalInts(ids, want) && !(includeExpired && equalInts(ids, []int{expired.ID, current.ID})) {
+			t.Errorf("includeExpired %v: got courses %v, want %v", includeExpired, ids, want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"fmt"
+)
+
+// UserItemCounts summarizes the per-user rows kept outside user_preferences
+type UserItemCounts struct {
+	Wishlist  int `json:"wishlist"`
+	Ignored   int `json:"ignored"`
+	Reminders int `json:"reminders"`
+	Held      int `json:"held"`
+	Unread    int `json:"unread"`
+}
+
+// GetUserItemCounts counts a user's wishlist entries, ignored courses,
+// pending reminders, held notifications and unread deliveries
+func (db *DB) GetUserItemCounts(userID int64) (*UserItemCounts, error) {
+	query := `SELECT
+			  (SELECT COUNT(*) FROM wishlist WHERE user_id = ?),
+			  (SELECT COUNT(*) FROM ignored_courses WHERE user_id = ?),
+			  (SELECT COUNT(*) FROM reminders WHERE user_id = ?),
+			  (SELECT COUNT(*) FROM held_notifications WHERE user_id = ?),
+			  (SELECT COUNT(*) FROM delivered WHERE user_id = ? AND read = 0)`
+
+	var counts UserItemCounts
+	err := db.conn.QueryRow(query, userID, userID, userID, userID, userID).Scan(
+		&counts.Wishlist, &counts.Ignored, &counts.Reminders, &counts.Held, &counts.Unread)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count user items: %w", err)
+	}
+	return &counts, nil
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetUserItemCounts(t *testing.T) {
+	db := newTestDB(t)
+	const userID, otherID = 42, 7
+	first := addTestCourse(t, db, "first", time.Time{})
+	second := addTestCourse(t, db, "second", time.Time{})
+
+	for _, add := range []func() error{
+		func() error { return db.AddToWishlist(userID, first.ID) },
+		func() error { return db.AddToWishlist(userID, second.ID) },
+		func() error { return db.IgnoreCourse(userID, second.ID) },
+		func() error { return db.AddReminder(userID, first.ID, time.Now().Add(time.Hour)) },
+		func() error { return db.HoldNotification(userID, first.ID) },
+		func() error { return db.MarkDelivered(userID, first.ID) },
+		func() error { return db.MarkDelivered(userID, second.ID) },
+		func() error { return db.AddToWishlist(otherID, first.ID) },
+	} {
+		if err := add(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := db.GetUserItemCounts(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := UserItemCounts{Wishlist: 2, Ignored: 1, Reminders: 1, Held: 1, Unread: 2}
+	if *got != want {
+		t.Errorf("GetUserItemCounts = %+v, want %+v", *got, want)
+	}
+
+	if got, err := db.GetUserItemCounts(99); err != nil || *got != (UserItemCounts{}) {
+		t.Errorf("GetUserItemCounts(new user) = %+v, %v; want all zero", got, err)
+	}
+}
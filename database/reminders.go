@@ -0,0 +1,111 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+type Reminder struct {
+	UserID   int64     `json:"user_id"`
+	CourseID int       `json:"course_id"`
+	RemindAt time.Time `json:"remind_at"`
+	Course   Course    `json:"course"`
+	Attempts int       `json:"attempts"` // Failed sends so far
+}
+
+// AddReminder schedules (or reschedules) a reminder for a user about a course
+func (db *DB) AddReminder(userID int64, courseID int, remindAt time.Time) error {
+	query := `INSERT OR REPLACE INTO reminders (user_id, course_id, remind_at, attempts) VALUES (?, ?, ?, 0)`
+	_, err := db.conn.Exec(query, userID, courseID, remindAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to add reminder: %w", err)
+	}
+	return nil
+}
+
+// GetDueReminders returns reminders whose time has come, together with their
+// course. Reminders for courses past their expiry grace are not returned.
+func (db *DB) GetDueReminders(now time.Time) ([]Reminder, error) {
+	query := `SELECT r.user_id, r.course_id, r.remind_at, COALESCE(r.attempts, 0), ` + CourseColumns("c") + `
+			  FROM reminders r
+			  INNER JOIN courses c ON c.id = r.course_id
+			  WHERE r.remind_at <= ?
+			  ORDER BY r.remind_at ASC`
+
+	rows, err := db.conn.Query(query, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		var r Reminder
+		c := &r.Course
+		err := ScanCourse(rows, c, &r.UserID, &r.CourseID, &r.RemindAt, &r.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+
+		// Skip courses that expired before the reminder could be delivered
+		if IsExpired(c.ExpiresAt, now, db.expiryGrace) {
+			continue
+		}
+		reminders = append(reminders, r)
+	}
+
+	return reminders, rows.Err()
+}
+
+// DeleteReminder removes a reminder once it has been delivered
+func (db *DB) DeleteReminder(userID int64, courseID int) error {
+	query := `DELETE FROM reminders WHERE user_id = ? AND course_id = ?`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+	return nil
+}
+
+// RecordReminderFailure counts a failed attempt to send a reminder
+func (db *DB) RecordReminderFailure(userID int64, courseID int) error {
+	query := `UPDATE reminders SET attempts = attempts + 1 WHERE user_id = ? AND course_id = ?`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to record reminder failure: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredReminders drops reminders for courses that have already expired
+func (db *DB) DeleteExpiredReminders(now time.Time) error {
+	query := `SELECT r.user_id, r.course_id, c.expires_at
+			  FROM reminders r
+			  INNER JOIN courses c ON c.id = r.course_id`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query reminders: %w", err)
+	}
+
+	var expired []Reminder
+	for rows.Next() {
+		var r Reminder
+		if err := rows.Scan(&r.UserID, &r.CourseID, &r.Course.ExpiresAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		if IsExpired(r.Course.ExpiresAt, now, db.expiryGrace) {
+			expired = append(expired, r)
+		}
+	}
+	rows.Close()
+
+	for _, r := range expired {
+		if err := db.DeleteReminder(r.UserID, r.CourseID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
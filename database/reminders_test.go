@@ -0,0 +1,95 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetDueReminders(t *testing.T) {
+	db := newTestDB(t)
+	db.SetExpiryGrace(time.Hour)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	due := addTestCourse(t, db, "due", now.Add(48*time.Hour))
+	later := addTestCourse(t, db, "later", now.Add(48*time.Hour))
+	expired := addTestCourse(t, db, "expired", now.Add(-2*time.Hour))
+	inGrace := addTestCourse(t, db, "in-grace", now.Add(-30*time.Minute))
+	noExpiry := addTestCourse(t, db, "no-expiry", time.Time{})
+
+	reminders := []struct {
+		userID   int64
+		course   Course
+		remindAt time.Time
+	}{
+		{1, due, now.Add(-time.Minute)},
+		{1, later, now.Add(time.Hour)},
+		{1, expired, now.Add(-time.Minute)},
+		{2, inGrace, now.Add(-time.Minute)},
+		{2, noExpiry, now},
+	}
+	for _, r := range reminders {
+		if err := db.AddReminder(r.userID, r.course.ID, r.remindAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := db.GetDueReminders(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]bool{due.ID: true, inGrace.ID: true, noExpiry.ID: true}
+	if len(got) != len(want) {
+		t.Fatalf("got %d due reminders, want %d: %+v", len(got), len(want), got)
+	}
+	for _, r := range got {
+		if !want[r.CourseID] {
+			t.Errorf("unexpected due reminder for course %d", r.CourseID)
+		}
+		if r.Course.ID != r.CourseID || r.Course.URL == "" {
+			t.Errorf("reminder for course %d came without its course: %+v", r.CourseID, r.Course)
+		}
+	}
+}
+
+func TestReminderAttempts(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Now().UTC()
+	course := addTestCourse(t, db, "retry", now.Add(48*time.Hour))
+
+	if err := db.AddReminder(1, course.ID, now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := db.RecordReminderFailure(1, course.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := db.GetDueReminders(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Attempts != 2 {
+		t.Fatalf("got %+v, want one reminder with 2 attempts", got)
+	}
+
+	// Snoozing again starts the count over
+	if err := db.AddReminder(1, course.ID, now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.GetDueReminders(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Attempts != 0 {
+		t.Errorf("got %+v after rescheduling, want attempts reset to 0", got)
+	}
+
+	if err := db.DeleteReminder(1, course.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := db.GetDueReminders(now); len(got) != 0 {
+		t.Errorf("deleted reminder is still due: %+v", got)
+	}
+}
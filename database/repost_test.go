@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoursePostedAt(t *testing.T) {
+	db := newTestDB(t)
+	course := addTestCourse(t, db, "go-basics", time.Time{})
+	setPostedAt(t, db, course.ID, 40)
+
+	postedAt, exists, err := db.CoursePostedAt(course.URL)
+	if err != nil || !exists {
+		t.Fatalf("CoursePostedAt = %v, %v, %v; want the stored course", postedAt, exists, err)
+	}
+	if age := time.Since(postedAt); age < 39*24*time.Hour || age > 41*24*time.Hour {
+		t.Errorf("course posted %v ago, want about 40 days", age)
+	}
+
+	if _, exists, err := db.CoursePostedAt("https://www.udemy.com/course/missing/"); err != nil || exists {
+		t.Errorf("CoursePostedAt(missing) = %v, %v; want not found", exists, err)
+	}
+}
+
+func TestRefreshCourse(t *testing.T) {
+	db := newTestDB(t)
+	stored := addTestCourse(t, db, "go-basics", time.Time{})
+	setPostedAt(t, db, stored.ID, 40)
+
+	fresh := Course{
+		URL:          stored.URL,
+		Title:        "Go Basics (2026 Edition)",
+		Category:     "Development",
+		Price:        "Free",
+		Discount:     "100% off",
+		QualityScore: 80,
+	}
+	if err := db.RefreshCourse(&fresh); err != nil {
+		t.Fatal(err)
+	}
+	if fresh.ID != stored.ID {
+		t.Errorf("refreshed course ID = %d, want the stored row's %d", fresh.ID, stored.ID)
+	}
+
+	got, err := db.GetCourse(stored.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != fresh.Title || got.QualityScore != 80 {
+		t.Errorf("stored course = %q scored %v, want the fresh details", got.Title, got.QualityScore)
+	}
+	if age := time.Since(got.PostedAt); age > time.Hour {
+		t.Errorf("refreshed course posted %v ago, want now", age)
+	}
+}
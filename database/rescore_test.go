@@ -0,0 +1,88 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRescoreCourses(t *testing.T) {
+	db := newTestDB(t)
+	low := addTestCourse(t, db, "low", time.Time{})
+	high := addTestCourse(t, db, "high", time.Time{})
+	if _, err := db.conn.Exec(`UPDATE courses SET rating = CASE id WHEN ? THEN 3.0 ELSE 4.5 END`, low.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A formula that only weighs the rating, unlike the stored scores of 50
+	score := func(course *Course) float64 { return course.Rating * 10 }
+
+	changed, err := db.RescoreCourses(score)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 2 {
+		t.Errorf("changed = %d, want 2", changed)
+	}
+
+	for _, tt := range []struct {
+		id   int
+		want float64
+	}{{low.ID, 30}, {high.ID, 45}} {
+		course, err := db.GetCourse(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if course.QualityScore != tt.want {
+			t.Errorf("course %d score = %v, want %v", tt.id, course.QualityScore, tt.want)
+		}
+	}
+
+	// Running the same formula again changes nothing
+	changed, err = db.RescoreCourses(score)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 0 {
+		t.Errorf("second rescore changed %d courses, want 0", changed)
+	}
+}
+
+func TestCourseSourceURL(t *testing.T) {
+	db := newTestDB(t)
+	course := Course{
+		URL:       "https://www.udemy.com/course/go-basics/",
+		Title:     "Go Basics",
+		Price:     "Free",
+		SourceURL: "https://a.example/",
+	}
+	if err := db.AddCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	imported := addTestCourse(t, db, "imported", time.Time{})
+
+	if got, err := db.GetCourse(course.ID); err != nil || got.SourceURL != "https://a.example/" {
+		t.Errorf("stored source = %+v, %v; want https://a.example/", got, err)
+	}
+
+	// A course that comes round again from another source records the new one
+	course.SourceURL = "https://b.example/"
+	if err := db.RefreshCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := db.GetCourse(course.ID); err != nil || got.SourceURL != "https://b.example/" {
+		t.Errorf("refreshed source = %+v, %v; want https://b.example/", got, err)
+	}
+
+	// Rescoring sees each course's source, and an empty one for imports
+	sources := make(map[int]string)
+	_, err := db.RescoreCourses(func(c *Course) float64 {
+		sources[c.ID] = c.SourceURL
+		return c.QualityScore
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sources[course.ID] != "https://b.example/" || sources[imported.ID] != "" {
+		t.Errorf("rescore saw sources %v", sources)
+	}
+}
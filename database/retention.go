@@ -0,0 +1,52 @@
+package database
+
+import (
+	"fmt"
+)
+
+// staleCourseIDs selects courses posted more than ? days ago. Courses on
+// someone's wishlist are kept however old they are.
+const staleCourseIDs = `SELECT id FROM courses
+	WHERE posted_at < datetime('now', '-' || ? || ' days')
+	AND id NOT IN (SELECT course_id FROM wishlist)`
+
+// courseDependents are the tables whose rows reference a course and go with it
+var courseDependents = []string{"reminders", "ignored_courses", "delivered", "course_feedback", "held_notifications", "pending_approvals", "approval_messages"}
+
+// CleanupOldCourses deletes courses posted more than daysOld days ago, along
+// with the rows that reference them, and returns how many were removed
+func (db *DB) CleanupOldCourses(daysOld int) (int64, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin cleanup: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range courseDependents {
+		query := `DELETE FROM ` + table + ` WHERE course_id IN (` + staleCourseIDs + `)`
+		if _, err := tx.Exec(query, daysOld); err != nil {
+			return 0, fmt.Errorf("failed to cleanup %s for old courses: %w", table, err)
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM courses WHERE id IN (`+staleCourseIDs+`)`, daysOld)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup old courses: %w", err)
+	}
+	deleted, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit cleanup: %w", err)
+	}
+	return deleted, nil
+}
+
+// PruneFeedback removes course feedback older than daysOld
+func (db *DB) PruneFeedback(daysOld int) error {
+	query := `DELETE FROM course_feedback WHERE created_at < datetime('now', '-' || ? || ' days')`
+	_, err := db.conn.Exec(query, daysOld)
+	if err != nil {
+		return fmt.Errorf("failed to prune feedback: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+// attachDependents gives a course a row in every table that references courses
+func attachDependents(t *testing.T, db *DB, courseID int) {
+	t.Helper()
+	steps := []func() error{
+		func() error { return db.AddReminder(1, courseID, time.Now().Add(time.Hour)) },
+		func() error { return db.IgnoreCourse(1, courseID) },
+		func() error { return db.MarkDelivered(1, courseID) },
+		func() error { return db.AddFeedback(1, courseID, 1) },
+		func() error { return db.HoldNotification(1, courseID) },
+		func() error { return db.QueueForApproval(courseID) },
+		func() error { return db.RecordApprovalMessage(courseID, 1, 10) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// dependentRows counts the rows referencing courseID in each dependent table
+func dependentRows(t *testing.T, db *DB, courseID int) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for _, table := range courseDependents {
+		var count int
+		if err := db.conn.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE course_id = ?`, courseID).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		counts[table] = count
+	}
+	return counts
+}
+
+func TestCleanupOldCourses(t *testing.T) {
+	db := newTestDB(t)
+	stale := addTestCourse(t, db, "stale", time.Time{})
+	saved := addTestCourse(t, db, "saved", time.Time{})
+	fresh := addTestCourse(t, db, "fresh", time.Time{})
+	for _, course := range []Course{stale, saved, fresh} {
+		attachDependents(t, db, course.ID)
+	}
+	setPostedAt(t, db, stale.ID, 100)
+	setPostedAt(t, db, saved.ID, 100)
+	if err := db.AddToWishlist(2, saved.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := db.CleanupOldCourses(90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted %d courses, want 1", deleted)
+	}
+
+	if course, err := db.GetCourse(stale.ID); err == nil {
+		t.Errorf("stale course %q is still stored", course.Title)
+	}
+	for table, count := range dependentRows(t, db, stale.ID) {
+		if count != 0 {
+			t.Errorf("%s keeps %d rows of the deleted course", table, count)
+		}
+	}
+
+	// Wishlisted and recent courses stay, along with their rows
+	for _, course := range []Course{saved, fresh} {
+		if _, err := db.GetCourse(course.ID); err != nil {
+			t.Errorf("course %s was removed: %v", course.Title, err)
+		}
+		for table, count := range dependentRows(t, db, course.ID) {
+			if count != 1 {
+				t.Errorf("%s has %d rows for kept course %s, want 1", table, count, course.Title)
+			}
+		}
+	}
+	if inWishlist, err := db.IsInWishlist(2, saved.ID); err != nil || !inWishlist {
+		t.Error("a wishlist entry was deleted")
+	}
+}
+
+func TestPruneFeedback(t *testing.T) {
+	db := newTestDB(t)
+	oldVote := addTestCourse(t, db, "old-vote", time.Time{})
+	newVote := addTestCourse(t, db, "new-vote", time.Time{})
+	for _, course := range []Course{oldVote, newVote} {
+		if err := db.AddFeedback(1, course.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.conn.Exec(`UPDATE course_feedback SET created_at = datetime('now', '-200 days') WHERE course_id = ?`, oldVote.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.PruneFeedback(180); err != nil {
+		t.Fatal(err)
+	}
+
+	var remaining []int
+	rows, err := db.conn.Query(`SELECT course_id FROM course_feedback`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		remaining = append(remaining, id)
+	}
+	if len(remaining) != 1 || remaining[0] != newVote.ID {
+		t.Errorf("feedback left for courses %v, want only #%d", remaining, newVote.ID)
+	}
+}
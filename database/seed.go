@@ -0,0 +1,124 @@
+package database
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"udemy-course-notifier/security"
+)
+
+// LoadSeedFile reads courses from a .json file (an array of courses) or a
+// .csv file with a header row. CSV columns are matched by name: url and title
+// are required; description, category, rating, price, original_price,
+// discount, expires_at (RFC 3339), quality_score and student_count are
+// optional. Every course URL must pass security.ValidateCourseURL.
+func LoadSeedFile(path string) ([]Course, error) {
+	if err := security.ValidateFilePath(path); err != nil {
+		return nil, fmt.Errorf("invalid seed file path: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open seed file: %w", err)
+	}
+	defer file.Close()
+
+	var courses []Course
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(file).Decode(&courses); err != nil {
+			return nil, fmt.Errorf("failed to parse seed file: %w", err)
+		}
+	case ".csv":
+		courses, err = parseSeedCSV(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse seed file: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("seed file must be .json or .csv")
+	}
+
+	for i, course := range courses {
+		if strings.TrimSpace(course.Title) == "" {
+			return nil, fmt.Errorf("seed course %d has no title", i+1)
+		}
+		if err := security.ValidateCourseURL(course.URL); err != nil {
+			return nil, fmt.Errorf("seed course %d: %w", i+1, err)
+		}
+	}
+
+	return courses, nil
+}
+
+func parseSeedCSV(r io.Reader) ([]Course, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"url", "title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var courses []Course
+	for line, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		course := Course{
+			URL:           field("url"),
+			Title:         field("title"),
+			Description:   field("description"),
+			Category:      field("category"),
+			Price:         field("price"),
+			Discount:      field("discount"),
+			OriginalPrice: field("original_price"),
+		}
+
+		var err error
+		if value := field("rating"); value != "" {
+			if course.Rating, err = strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid rating %q", line+2, value)
+			}
+		}
+		if value := field("quality_score"); value != "" {
+			if course.QualityScore, err = strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid quality_score %q", line+2, value)
+			}
+		}
+		if value := field("student_count"); value != "" {
+			if course.StudentCount, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid student_count %q", line+2, value)
+			}
+		}
+		if value := field("expires_at"); value != "" {
+			if course.ExpiresAt, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid expires_at %q", line+2, value)
+			}
+		}
+
+		courses = append(courses, course)
+	}
+
+	return courses, nil
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSeedFile writes contents to a file with the given name in a temporary directory
+func writeSeedFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportSeedFile(t *testing.T) {
+	jsonSeed := writeSeedFile(t, "seed.json", `[
+		{"url": "https://www.udemy.com/course/go-basics/", "title": "Go Basics", "category": "Development", "quality_score": 72},
+		{"url": "https://www.udemy.com/course/rust-basics/", "title": "Rust Basics", "expires_at": "2030-01-02T15:04:05Z"}
+	]`)
+	csvSeed := writeSeedFile(t, "seed.csv", "Title,URL,rating,student_count,expires_at\n"+
+		"Go Basics,https://www.udemy.com/course/go-basics/,4.5,1200,\n"+
+		"SQL Basics,https://www.udemy.com/course/sql-basics/,4.2,300,2030-01-02T15:04:05Z\n")
+
+	db := newTestDB(t)
+
+	courses, err := LoadSeedFile(jsonSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 2 || courses[0].QualityScore != 72 || !courses[1].ExpiresAt.Equal(time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("JSON seed parsed as %+v", courses)
+	}
+	if imported, err := db.AddCourses(courses); err != nil || imported != 2 {
+		t.Errorf("AddCourses(JSON seed) = %d, %v; want 2 imported", imported, err)
+	}
+
+	courses, err = LoadSeedFile(csvSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 2 || courses[0].Rating != 4.5 || courses[0].StudentCount != 1200 || !courses[0].ExpiresAt.IsZero() {
+		t.Errorf("CSV seed parsed as %+v", courses)
+	}
+	// Go Basics is already stored from the JSON seed
+	if imported, err := db.AddCourses(courses); err != nil || imported != 1 {
+		t.Errorf("AddCourses(CSV seed) = %d, %v; want 1 imported", imported, err)
+	}
+
+	for _, slug := range []string{"go-basics", "rust-basics", "sql-basics"} {
+		if exists, err := db.CourseExists("https://www.udemy.com/course/" + slug + "/"); err != nil || !exists {
+			t.Errorf("CourseExists(%s) = %v, %v; want true", slug, exists, err)
+		}
+	}
+	if count, err := db.CountCourses(); err != nil || count != 3 {
+		t.Errorf("CountCourses = %d, %v; want 3", count, err)
+	}
+}
+
+func TestLoadSeedFileRejectsInvalidSeeds(t *testing.T) {
+	tests := []struct {
+		name, file, contents string
+		wantErr              string
+	}{
+		{"foreign domain", "seed.json", `[{"url": "https://evil.example/course/x/", "title": "X"}]`, "seed course 1"},
+		{"missing title", "seed.json", `[{"url": "https://www.udemy.com/course/x/"}]`, "has no title"},
+		{"missing column", "seed.csv", "title\nGo Basics\n", "missing url column"},
+		{"bad number", "seed.csv", "url,title,rating\nhttps://www.udemy.com/course/x/,X,great\n", "invalid rating"},
+		{"bad date", "seed.csv", "url,title,expires_at\nhttps://www.udemy.com/course/x/,X,tomorrow\n", "invalid expires_at"},
+		{"unknown format", "seed.txt", "anything", "must be .json or .csv"},
+	}
+	for _, tt := range tests {
+		_, err := LoadSeedFile(writeSeedFile(t, tt.file, tt.contents))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: LoadSeedFile = %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	if _, err := LoadSeedFile("seeds/../../etc/passwd.json"); err == nil {
+		t.Error("LoadSeedFile accepted a path that escapes its directory")
+	}
+}
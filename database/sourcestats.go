@@ -0,0 +1,55 @@
+package database
+
+import (
+	"fmt"
+)
+
+// CouponStats counts the coupon page links followed for one source
+type CouponStats struct {
+	Attempts int `json:"attempts"` // Coupon page links followed, including retries
+	Resolved int `json:"resolved"` // Of those, links that led to a Udemy course page
+}
+
+// ResolveRate returns the share of followed coupon links that resolved to a
+// course, or false if none have been followed yet. A falling rate usually
+// means the source changed its page layout.
+func (s CouponStats) ResolveRate() (float64, bool) {
+	if s.Attempts == 0 {
+		return 0, false
+	}
+	return float64(s.Resolved) / float64(s.Attempts), true
+}
+
+// RecordCouponFollows adds followed and resolved coupon link counts to a
+// source's totals
+func (db *DB) RecordCouponFollows(sourceURL string, attempts, resolved int) error {
+	query := `INSERT INTO source_stats (source_url, coupon_attempts, coupon_resolved) VALUES (?, ?, ?)
+			  ON CONFLICT(source_url) DO UPDATE SET
+			      coupon_attempts = coupon_attempts + excluded.coupon_attempts,
+			      coupon_resolved = coupon_resolved + excluded.coupon_resolved`
+	if _, err := db.conn.Exec(query, sourceURL, attempts, resolved); err != nil {
+		return fmt.Errorf("failed to record coupon follows: %w", err)
+	}
+	return nil
+}
+
+// GetCouponStats returns the coupon follow totals of every source, keyed by
+// source URL
+func (db *DB) GetCouponStats() (map[string]CouponStats, error) {
+	rows, err := db.conn.Query(`SELECT source_url, coupon_attempts, coupon_resolved FROM source_stats`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]CouponStats)
+	for rows.Next() {
+		var sourceURL string
+		var s CouponStats
+		if err := rows.Scan(&sourceURL, &s.Attempts, &s.Resolved); err != nil {
+			return nil, fmt.Errorf("failed to scan source stats: %w", err)
+		}
+		stats[sourceURL] = s
+	}
+	return stats, rows.Err()
+}
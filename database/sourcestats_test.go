@@ -0,0 +1,47 @@
+package database
+
+import "testing"
+
+func TestCouponStatsResolveRate(t *testing.T) {
+	if _, ok := (CouponStats{}).ResolveRate(); ok {
+		t.Error("a source with no coupon follows has a resolve rate")
+	}
+	if rate, ok := (CouponStats{Attempts: 8, Resolved: 6}).ResolveRate(); !ok || rate != 0.75 {
+		t.Errorf("ResolveRate = %v, %v; want 0.75", rate, ok)
+	}
+}
+
+func TestRecordCouponFollows(t *testing.T) {
+	db := newTestDB(t)
+	const (
+		sourceA = "https://a.example/"
+		sourceB = "https://b.example/"
+	)
+
+	for _, r := range []struct {
+		source             string
+		attempts, resolved int
+	}{
+		{sourceA, 5, 4},
+		{sourceA, 3, 0},
+		{sourceB, 2, 2},
+	} {
+		if err := db.RecordCouponFollows(r.source, r.attempts, r.resolved); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := db.GetCouponStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats[sourceA] != (CouponStats{Attempts: 8, Resolved: 4}) {
+		t.Errorf("%s stats = %+v, want 8 attempts and 4 resolved", sourceA, stats[sourceA])
+	}
+	if rate, _ := stats[sourceA].ResolveRate(); rate != 0.5 {
+		t.Errorf("%s resolve rate = %v, want 0.5", sourceA, rate)
+	}
+	if stats[sourceB] != (CouponStats{Attempts: 2, Resolved: 2}) {
+		t.Errorf("%s stats = %+v, want 2 attempts and 2 resolved", sourceB, stats[sourceB])
+	}
+}
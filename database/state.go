@@ -0,0 +1,72 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+const (
+	sourceCursorKey     = "source_cursor"
+	dashboardMessageKey = "dashboard_message_id"
+)
+
+// getState returns the stored value for key, or "" when it is unset
+func (db *DB) getState(key string) (string, error) {
+	var value string
+	err := db.conn.QueryRow(`SELECT value FROM bot_state WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read state %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (db *DB) setState(key, value string) error {
+	query := `INSERT INTO bot_state (key, value) VALUES (?, ?)
+			  ON CONFLICT(key) DO UPDATE SET value = excluded.value`
+	if _, err := db.conn.Exec(query, key, value); err != nil {
+		return fmt.Errorf("failed to write state %s: %w", key, err)
+	}
+	return nil
+}
+
+// SourceCursor returns the index of the next source to scrape when sources
+// are rotated across scan cycles
+func (db *DB) SourceCursor() (int, error) {
+	value, err := db.getState(sourceCursorKey)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	cursor, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, nil // Corrupt cursor; start over
+	}
+	return cursor, nil
+}
+
+// SetSourceCursor stores the index of the next source to scrape
+func (db *DB) SetSourceCursor(cursor int) error {
+	return db.setState(sourceCursorKey, strconv.Itoa(cursor))
+}
+
+// DashboardMessageID returns the ID of the pinned dashboard message in the
+// channel, or 0 if none has been posted
+func (db *DB) DashboardMessageID() (int, error) {
+	value, err := db.getState(dashboardMessageKey)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, nil // Corrupt ID; a new dashboard is posted
+	}
+	return id, nil
+}
+
+// SetDashboardMessageID stores the ID of the pinned dashboard message
+func (db *DB) SetDashboardMessageID(id int) error {
+	return db.setState(dashboardMessageKey, strconv.Itoa(id))
+}
@@ -0,0 +1,20 @@
+package database
+
+import "testing"
+
+func TestSourceCursorPersists(t *testing.T) {
+	db := newTestDB(t)
+
+	if cursor, err := db.SourceCursor(); err != nil || cursor != 0 {
+		t.Fatalf("initial cursor = %d, %v; want 0", cursor, err)
+	}
+	if err := db.SetSourceCursor(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetSourceCursor(4); err != nil {
+		t.Fatal(err)
+	}
+	if cursor, err := db.SourceCursor(); err != nil || cursor != 4 {
+		t.Errorf("cursor = %d, %v; want 4", cursor, err)
+	}
+}
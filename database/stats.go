@@ -0,0 +1,74 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+type CategoryTrend struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`     // Courses in the requested window
+	ThisWeek int    `json:"this_week"` // Courses in the last 7 days
+	LastWeek int    `json:"last_week"` // Courses 8-14 days ago
+}
+
+// CategoryTrends counts courses per category over the last `days` days,
+// along with week-over-week counts, ranked by the window count
+func (db *DB) CategoryTrends(days int) ([]CategoryTrend, error) {
+	query := `SELECT COALESCE(NULLIF(category, ''), 'General') AS cat,
+			  SUM(CASE WHEN posted_at >= datetime('now', '-' || ? || ' days') THEN 1 ELSE 0 END) AS window_count,
+			  SUM(CASE WHEN posted_at >= datetime('now', '-7 days') THEN 1 ELSE 0 END) AS this_week,
+			  SUM(CASE WHEN posted_at >= datetime('now', '-14 days') AND posted_at < datetime('now', '-7 days') THEN 1 ELSE 0 END) AS last_week
+			  FROM courses
+			  WHERE posted_at >= datetime('now', '-' || ? || ' days') OR posted_at >= datetime('now', '-14 days')
+			  GROUP BY cat
+			  HAVING window_count > 0 OR this_week > 0 OR last_week > 0
+			  ORDER BY window_count DESC, cat ASC`
+
+	rows, err := db.conn.Query(query, days, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category trends: %w", err)
+	}
+	defer rows.Close()
+
+	var trends []CategoryTrend
+	for rows.Next() {
+		var t CategoryTrend
+		if err := rows.Scan(&t.Category, &t.Count, &t.ThisWeek, &t.LastWeek); err != nil {
+			return nil, fmt.Errorf("failed to scan category trend: %w", err)
+		}
+		trends = append(trends, t)
+	}
+
+	return trends, rows.Err()
+}
+
+// CourseHistoryDays returns how many days of course history are stored
+func (db *DB) CourseHistoryDays() (float64, error) {
+	var days float64
+	query := `SELECT COALESCE(julianday('now') - julianday(MIN(posted_at)), 0) FROM courses`
+	err := db.conn.QueryRow(query).Scan(&days)
+	return days, err
+}
+
+// CountCourses returns the number of stored courses
+func (db *DB) CountCourses() (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM courses`).Scan(&count)
+	return count, err
+}
+
+// CountCoursesSince returns the number of courses stored at or after since
+func (db *DB) CountCoursesSince(since time.Time) (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM courses WHERE posted_at >= ?`,
+		since.UTC().Format("2006-01-02 15:04:05")).Scan(&count)
+	return count, err
+}
+
+// CountSubscribers returns the number of subscribed users and groups
+func (db *DB) CountSubscribers() (int, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM user_preferences WHERE subscribed = 1`).Scan(&count)
+	return count, err
+}
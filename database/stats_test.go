@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCategoryTrends(t *testing.T) {
+	db := newTestDB(t)
+
+	seed := []struct {
+		category string
+		daysAgo  []int
+	}{
+		{"Development", []int{1, 2, 3, 9}},
+		{"Design", []int{1, 10, 11, 12}},
+		{"", []int{2}},
+		{"Music", []int{40}},
+	}
+	n := 0
+	for _, s := range seed {
+		for _, daysAgo := range s.daysAgo {
+			n++
+			course := addTestCourse(t, db, fmt.Sprintf("course-%d", n), time.Time{})
+			if _, err := db.conn.Exec(`UPDATE courses SET category = ? WHERE id = ?`, s.category, course.ID); err != nil {
+				t.Fatal(err)
+			}
+			setPostedAt(t, db, course.ID, daysAgo)
+		}
+	}
+
+	trends, err := db.CategoryTrends(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CategoryTrend{
+		{Category: "Development", Count: 3, ThisWeek: 3, LastWeek: 1},
+		{Category: "Design", Count: 1, ThisWeek: 1, LastWeek: 3},
+		{Category: "General", Count: 1, ThisWeek: 1, LastWeek: 0},
+	}
+	if len(trends) != len(want) {
+		t.Fatalf("got %+v, want %+v", trends, want)
+	}
+	for i := range want {
+		if trends[i] != want[i] {
+			t.Errorf("trend %d = %+v, want %+v", i, trends[i], want[i])
+		}
+	}
+
+	// A wider window reaches the older course
+	trends, err = db.CategoryTrends(60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, trend := range trends {
+		if trend.Category == "Music" && trend.Count == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("60-day trends %+v are missing Music", trends)
+	}
+}
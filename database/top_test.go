@@ -0,0 +1,86 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTopCourses(t *testing.T) {
+	db := newTestDB(t)
+	add := func(slug, category string, quality float64, daysAgo int) Course {
+		t.Helper()
+		course := Course{
+			URL:          "https://www.udemy.com/course/" + slug + "/",
+			Title:        "Course " + slug,
+			Category:     category,
+			QualityScore: quality,
+		}
+		if err := db.AddCourse(&course); err != nil {
+			t.Fatal(err)
+		}
+		if daysAgo > 0 {
+			setPostedAt(t, db, course.ID, daysAgo)
+		}
+		return course
+	}
+
+	goCourse := add("go", "Development", 90, 0)
+	rust := add("rust", "development", 70, 1)
+	oldJava := add("java", "Development", 95, 30)
+	design := add("figma", "Design", 80, 0)
+
+	ids := func(courses []Course) []int {
+		var ids []int
+		for _, course := range courses {
+			ids = append(ids, course.ID)
+		}
+		return ids
+	}
+	tests := []struct {
+		name     string
+		category string
+		days     int
+		limit    int
+		offset   int
+		want     []int
+	}{
+		{"every course", "", 0, 10, 0, []int{oldJava.ID, goCourse.ID, design.ID, rust.ID}},
+		{"category ignores case", "DEVELOPMENT", 0, 10, 0, []int{oldJava.ID, goCourse.ID, rust.ID}},
+		{"recent only", "Development", 7, 10, 0, []int{goCourse.ID, rust.ID}},
+		{"first page", "", 0, 2, 0, []int{oldJava.ID, goCourse.ID}},
+		{"second page", "", 0, 2, 2, []int{design.ID, rust.ID}},
+		{"unknown category", "Cooking", 0, 10, 0, nil},
+	}
+	for _, tt := range tests {
+		courses, err := db.GetTopCourses(tt.category, tt.days, tt.limit, tt.offset, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(courses); !equalInts(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	expired := add("expired", "Development", 99, 0)
+	if _, err := db.conn.Exec(`UPDATE courses SET expires_at = ? WHERE id = ?`, time.Now().Add(-48*time.Hour), expired.ID); err != nil {
+		t.Fatal(err)
+	}
+	if courses, _ := db.GetTopCourses("", 0, 1, 0, false); len(courses) != 1 || courses[0].ID == expired.ID {
+		t.Error("expired course listed without includeExpired")
+	}
+	if courses, _ := db.GetTopCourses("", 0, 1, 0, true); len(courses) != 1 || courses[0].ID != expired.ID {
+		t.Error("expired course missing with includeExpired")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UdemyMeta holds details read from a course's Udemy page, cached so the page
+// isn't fetched again when several sources list the same course
+type UdemyMeta struct {
+	URL              string    `json:"url"` // Canonical course URL
+	Title            string    `json:"title"`
+	Image            string    `json:"image"`
+	Level            string    `json:"level"`
+	CaptionLanguages []string  `json:"caption_languages"`
+	Language         string    `json:"language"`    // ISO 639-1 code from the page's structured data
+	Certificate      *bool     `json:"certificate"` // Certificate of completion offered; nil when unknown
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// GetUdemyMeta returns the cached page details for a course URL, or nil when
+// there is no entry or it is older than maxAge
+func (db *DB) GetUdemyMeta(courseURL string, maxAge time.Duration) (*UdemyMeta, error) {
+	query := `SELECT url, title, image, level, caption_languages, COALESCE(language, ''), certificate, updated_at
+			  FROM udemy_meta WHERE url = ?`
+
+	var meta UdemyMeta
+	var captionsJSON sql.NullString
+	var certificate sql.NullBool
+	err := db.conn.QueryRow(query, CanonicalURL(courseURL)).Scan(&meta.URL, &meta.Title,
+		&meta.Image, &meta.Level, &captionsJSON, &meta.Language, &certificate, &meta.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Udemy meta: %w", err)
+	}
+
+	if time.Since(meta.UpdatedAt) > maxAge {
+		return nil, nil // Expired; the caller refetches and overwrites it
+	}
+
+	if captionsJSON.Valid && captionsJSON.String != "" {
+		json.Unmarshal([]byte(captionsJSON.String), &meta.CaptionLanguages)
+	}
+	if certificate.Valid {
+		meta.Certificate = &certificate.Bool
+	}
+	return &meta, nil
+}
+
+// PutUdemyMeta stores page details for meta.URL, replacing any older entry
+func (db *DB) PutUdemyMeta(meta UdemyMeta) error {
+	query := `INSERT OR REPLACE INTO udemy_meta (url, title, image, level, caption_languages, language, certificate, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.conn.Exec(query, CanonicalURL(meta.URL), meta.Title, meta.Image, meta.Level,
+		nullableJSON(meta.CaptionLanguages), meta.Language, meta.Certificate, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to store Udemy meta: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUdemyMetaCache(t *testing.T) {
+	db := newTestDB(t)
+	certificate := true
+	meta := UdemyMeta{
+		URL:              "https://www.udemy.com/course/go-basics/?couponCode=FREE",
+		Title:            "Go Basics",
+		Image:            "https://img-c.udemycdn.com/course/go-basics.jpg",
+		Level:            "Beginner",
+		CaptionLanguages: []string{"en", "es"},
+		Language:         "en",
+		Certificate:      &certificate,
+	}
+	if err := db.PutUdemyMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	// Any link to the same course finds the entry
+	got, err := db.GetUdemyMeta("https://udemy.com/course/go-basics?couponCode=OTHER", time.Hour)
+	if err != nil || got == nil {
+		t.Fatalf("GetUdemyMeta = %v, %v; want the cached entry", got, err)
+	}
+	if got.Title != meta.Title || got.Image != meta.Image || got.Level != meta.Level || got.Language != meta.Language {
+		t.Errorf("GetUdemyMeta = %+v, want %+v", got, meta)
+	}
+	if !reflect.DeepEqual(got.CaptionLanguages, meta.CaptionLanguages) {
+		t.Errorf("caption languages = %v, want %v", got.CaptionLanguages, meta.CaptionLanguages)
+	}
+	if got.Certificate == nil || !*got.Certificate {
+		t.Errorf("certificate = %v, want true", got.Certificate)
+	}
+
+	if got, err := db.GetUdemyMeta(meta.URL, 0); err != nil || got != nil {
+		t.Errorf("GetUdemyMeta past TTL = %v, %v; want no entry", got, err)
+	}
+	if got, err := db.GetUdemyMeta("https://www.udemy.com/course/rust-basics/", time.Hour); err != nil || got != nil {
+		t.Errorf("GetUdemyMeta for an uncached course = %v, %v; want no entry", got, err)
+	}
+
+	// A refetch replaces the entry
+	meta.Title = "Go Basics (2024 Edition)"
+	meta.Certificate = nil
+	if err := db.PutUdemyMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.GetUdemyMeta(meta.URL, time.Hour)
+	if err != nil || got == nil || got.Title != meta.Title || got.Certificate != nil {
+		t.Errorf("GetUdemyMeta after refetch = %+v, %v; want the new title and unknown certificate", got, err)
+	}
+}
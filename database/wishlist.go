@@ -0,0 +1,186 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WishlistWatch is a wishlist entry with the price last seen for its course
+type WishlistWatch struct {
+	ID                int    `json:"id"`
+	UserID            int64  `json:"user_id"`
+	CourseID          int    `json:"course_id"`
+	CourseURL         string `json:"course_url"`
+	CourseTitle       string `json:"course_title"`
+	LastKnownPrice    string `json:"last_known_price"`
+	LastKnownDiscount string `json:"last_known_discount"`
+	AlertAttempts     int    `json:"alert_attempts"` // Failed sends of the alert for the current price
+}
+
+// GetWishlistWatches returns every wishlist entry with its last known price
+func (db *DB) GetWishlistWatches() ([]WishlistWatch, error) {
+	query := `SELECT w.id, w.user_id, w.course_id, c.url, c.title,
+			  COALESCE(w.last_known_price, c.price, ''), COALESCE(w.last_known_discount, c.discount, ''),
+			  COALESCE(w.alert_attempts, 0)
+			  FROM wishlist w
+			  INNER JOIN courses c ON c.id = w.course_id`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wishlist: %w", err)
+	}
+	defer rows.Close()
+
+	var watches []WishlistWatch
+	for rows.Next() {
+		var w WishlistWatch
+		if err := rows.Scan(&w.ID, &w.UserID, &w.CourseID, &w.CourseURL, &w.CourseTitle,
+			&w.LastKnownPrice, &w.LastKnownDiscount, &w.AlertAttempts); err != nil {
+			return nil, fmt.Errorf("failed to scan wishlist entry: %w", err)
+		}
+		watches = append(watches, w)
+	}
+
+	return watches, rows.Err()
+}
+
+// UpdateWishlistPrice records the latest price seen for a wishlist entry
+// and clears its failed alert attempts
+func (db *DB) UpdateWishlistPrice(wishlistID int, price, discount string) error {
+	query := `UPDATE wishlist SET last_known_price = ?, last_known_discount = ?, alert_attempts = 0 WHERE id = ?`
+	_, err := db.conn.Exec(query, price, discount, wishlistID)
+	if err != nil {
+		return fmt.Errorf("failed to update wishlist price: %w", err)
+	}
+	return nil
+}
+
+// RecordWishlistAlertFailure counts a failed attempt to send a price alert
+// for a wishlist entry
+func (db *DB) RecordWishlistAlertFailure(wishlistID int) error {
+	_, err := db.conn.Exec(`UPDATE wishlist SET alert_attempts = alert_attempts + 1 WHERE id = ?`, wishlistID)
+	if err != nil {
+		return fmt.Errorf("failed to record wishlist alert failure: %w", err)
+	}
+	return nil
+}
+
+var discountPercentRegex = regexp.MustCompile(`(\d+)\s*%`)
+
+// IsFreePrice reports whether a price/discount pair means the course is free
+func IsFreePrice(price, discount string) bool {
+	return strings.Contains(strings.ToLower(price), "free") || discountPercent(discount) >= 100
+}
+
+// PriceImproved reports whether the new price/discount is better than the old one
+func PriceImproved(oldPrice, oldDiscount, newPrice, newDiscount string) bool {
+	if IsFreePrice(newPrice, newDiscount) && !IsFreePrice(oldPrice, oldDiscount) {
+		return true
+	}
+	return discountPercent(newDiscount) > discountPercent(oldDiscount)
+}
+
+// DiscountLevel ranks a price/discount pair by how good a deal it is: 100 for
+// free courses, otherwise the discount percentage
+func DiscountLevel(price, discount string) int {
+	if IsFreePrice(price, discount) {
+		return 100
+	}
+	return discountPercent(discount)
+}
+
+func discountPercent(discount string) int {
+	matches := discountPercentRegex.FindStringSubmatch(discount)
+	if len(matches) < 2 {
+		return 0
+	}
+	percent, _ := strconv.Atoi(matches[1])
+	return percent
+}
+
+// IsInWishlist reports whether the course is in the user's wishlist
+func (db *DB) IsInWishlist(userID int64, courseID int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM wishlist WHERE user_id = ? AND course_id = ?)`
+	err := db.conn.QueryRow(query, userID, courseID).Scan(&exists)
+	return exists, err
+}
+
+// RemindAllUser is a user who wants a digest of expiring wishlist courses
+type RemindAllUser struct {
+	UserID     int64  `json:"user_id"`
+	LastSentOn string `json:"last_sent_on"` // Date of the last digest (YYYY-MM-DD in the user's zone)
+	DigestSort string `json:"digest_sort"`  // How the digest orders courses; empty for the default
+}
+
+// GetRemindAllUsers returns users who turned on /remindall
+func (db *DB) GetRemindAllUsers() ([]RemindAllUser, error) {
+	rows, err := db.conn.Query(`SELECT user_id, COALESCE(remind_all_sent_on, ''), COALESCE(digest_sort, '')
+			  FROM user_preferences WHERE remind_all = 1 ORDER BY user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query remind-all users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []RemindAllUser
+	for rows.Next() {
+		var u RemindAllUser
+		if err := rows.Scan(&u.UserID, &u.LastSentOn, &u.DigestSort); err != nil {
+			return nil, fmt.Errorf("failed to scan remind-all user: %w", err)
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// SetRemindAllSentOn records the date a user's last expiry digest was sent
+func (db *DB) SetRemindAllSentOn(userID int64, day string) error {
+	_, err := db.conn.Exec(`UPDATE user_preferences SET remind_all_sent_on = ? WHERE user_id = ?`, day, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update remind-all date: %w", err)
+	}
+	return nil
+}
+
+// GetUnremindedWishlist returns a user's wishlist courses that have an expiry
+// date and have not been included in an expiry digest for that date yet. A
+// course whose expiry moved since its last digest is returned again.
+func (db *DB) GetUnremindedWishlist(userID int64) ([]Course, error) {
+	query := `SELECT ` + CourseColumns("c") + `
+			  FROM wishlist w
+			  INNER JOIN courses c ON c.id = w.course_id
+			  WHERE w.user_id = ? AND c.expires_at IS NOT NULL
+			    AND (w.expiry_reminded_for IS NULL OR w.expiry_reminded_for != c.expires_at)
+			  ORDER BY c.expires_at ASC`
+
+	rows, err := db.conn.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wishlist: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []Course
+	for rows.Next() {
+		var course Course
+		if err := ScanCourse(rows, &course); err != nil {
+			return nil, fmt.Errorf("failed to scan course: %w", err)
+		}
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
+// MarkWishlistReminded records that a wishlist course was included in an
+// expiry digest for its current expiry date
+func (db *DB) MarkWishlistReminded(userID int64, courseID int) error {
+	query := `UPDATE wishlist SET expiry_reminded = 1,
+			  expiry_reminded_for = (SELECT expires_at FROM courses WHERE courses.id = wishlist.course_id)
+			  WHERE user_id = ? AND course_id = ?`
+	_, err := db.conn.Exec(query, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to mark wishlist course reminded: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPriceImproved(t *testing.T) {
+	tests := []struct {
+		oldPrice, oldDiscount, newPrice, newDiscount string
+		want                                         bool
+	}{
+		{"$19.99", "80% off", "Free", "100% off", true},
+		{"$19.99", "50% off", "$9.99", "80% off", true},
+		{"$9.99", "80% off", "$19.99", "50% off", false},
+		{"Free", "100% off", "Free", "100% off", false},
+		{"Free", "100% off", "$19.99", "80% off", false},
+		{"$19.99", "", "$19.99", "", false},
+	}
+	for _, tt := range tests {
+		got := PriceImproved(tt.oldPrice, tt.oldDiscount, tt.newPrice, tt.newDiscount)
+		if got != tt.want {
+			t.Errorf("PriceImproved(%q %q -> %q %q) = %v, want %v",
+				tt.oldPrice, tt.oldDiscount, tt.newPrice, tt.newDiscount, got, tt.want)
+		}
+	}
+}
+
+func TestRepeatedWishlistAndIgnoreAreIdempotent(t *testing.T) {
+	db := newTestDB(t)
+	course := addTestCourse(t, db, "go-basics", time.Time{})
+	const userID = 42
+
+	for i := 0; i < 2; i++ {
+		if err := db.AddToWishlist(userID, course.ID); err != nil {
+			t.Fatalf("AddToWishlist attempt %d: %v", i+1, err)
+		}
+		if err := db.IgnoreCourse(userID, course.ID); err != nil {
+			t.Fatalf("IgnoreCourse attempt %d: %v", i+1, err)
+		}
+	}
+
+	var saved int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM wishlist WHERE user_id = ?`, userID).Scan(&saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved != 1 {
+		t.Errorf("wishlist has %d rows, want 1", saved)
+	}
+	if ignored, err := db.IsIgnored(userID, course.ID); err != nil || !ignored {
+		t.Errorf("IsIgnored = %v, %v; want true", ignored, err)
+	}
+}
+
+func TestAddToWishlistMissingCourse(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AddToWishlist(42, 999); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("AddToWishlist(missing) = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,44 @@
+package filters
+
+import (
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestRequireCertificate(t *testing.T) {
+	f := newTestEngine(t)
+	const userID = 42
+	yes, no := true, false
+
+	courses := []struct {
+		name        string
+		certificate *bool
+	}{
+		{"offers one", &yes},
+		{"lacks one", &no},
+		{"unknown", nil},
+	}
+
+	tests := []struct {
+		required bool
+		want     map[string]bool
+	}{
+		{true, map[string]bool{"offers one": true, "unknown": true}},
+		{false, map[string]bool{"offers one": true, "lacks one": true, "unknown": true}},
+	}
+	for _, tt := range tests {
+		if err := f.SetRequireCertificate(userID, tt.required); err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range courses {
+			got, err := f.ShouldNotifyCourse(&database.Course{Title: "Docker", Certificate: c.certificate}, userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want[c.name] {
+				t.Errorf("required %v, course %s: ShouldNotifyCourse = %v, want %v", tt.required, c.name, got, tt.want[c.name])
+			}
+		}
+	}
+}
@@ -4,24 +4,57 @@ import (
 	"encoding/json"
 	"strings"
 
+	"udemy-course-notifier/currency"
 	"udemy-course-notifier/database"
+	"udemy-course-notifier/language"
 )
 
 type UserFilter struct {
 	UserID           int64    `json:"user_id"`
 	Categories       []string `json:"categories"`
-	Keywords         []string `json:"keywords"`
+	Keywords         []string `json:"keywords"`          // Any one must match
+	RequiredKeywords []string `json:"required_keywords"` // All must match; "+" prefixed in /filter
 	ExcludedKeywords []string `json:"excluded_keywords"`
 	MinRating        float64  `json:"min_rating"`
 	Language         string   `json:"language"`
+	CaptionLanguage  string   `json:"caption_language"` // Require captions in this language (ISO 639-1)
+	MaxPrice         float64  `json:"max_price"`        // Price ceiling in Currency; 0 means no ceiling
+	Currency         string   `json:"currency"`
+	Timezone         string   `json:"timezone"` // IANA zone for times in direct messages
+	QuietStart       string   `json:"quiet_start"` // HH:MM in Timezone; empty when quiet hours are off
+	QuietEnd         string   `json:"quiet_end"`
+	RemindAll        bool     `json:"remind_all"` // Daily digest of expiring wishlist courses
+	MaxPerDay        int      `json:"max_per_day"` // Direct messages per day; 0 means no limit
+	ShowExpired      bool     `json:"show_expired"` // Include expired courses in /browse and /popular
+	DigestSort       string   `json:"digest_sort"`  // Order of courses in digests; empty for the default
+	RequireCertificate bool   `json:"require_certificate"` // Skip courses known to lack a certificate
+	PageSize         int      `json:"page_size"` // Items per page in paginated lists; 0 for the default
+	Subscribed       bool     `json:"-"`         // Set by saving a filter; cleared by /stop
 }
 
 type FilterEngine struct {
-	db *database.DB
+	db                *database.DB
+	converter         *currency.Converter
+	unparseablePasses bool // Whether courses with an unreadable price pass a price ceiling
 }
 
 func New(db *database.DB) *FilterEngine {
-	return &FilterEngine{db: db}
+	return &FilterEngine{
+		db:                db,
+		converter:         currency.NewConverter(nil),
+		unparseablePasses: true,
+	}
+}
+
+// SetPriceOptions configures currency conversion for price ceilings
+func (f *FilterEngine) SetPriceOptions(converter *currency.Converter, unparseablePasses bool) {
+	f.converter = converter
+	f.unparseablePasses = unparseablePasses
+}
+
+// SupportsCurrency reports whether prices can be converted to the currency
+func (f *FilterEngine) SupportsCurrency(code string) bool {
+	return f.converter.Supported(code)
 }
 
 func (f *FilterEngine) ShouldNotifyCourse(course *database.Course, userID int64) (bool, error) {
@@ -49,6 +82,10 @@ func (f *FilterEngine) ShouldNotifyCourse(course *database.Course, userID int64)
 		return false, nil
 	}
 
+	if !f.matchesRequiredKeywords(course, userFilter.RequiredKeywords) {
+		return false, nil
+	}
+
 	if f.containsExcludedKeywords(course, userFilter.ExcludedKeywords) {
 		return false, nil
 	}
@@ -57,6 +94,18 @@ func (f *FilterEngine) ShouldNotifyCourse(course *database.Course, userID int64)
 		return false, nil
 	}
 
+	if !f.matchesCaptionLanguage(course, userFilter.CaptionLanguage) {
+		return false, nil
+	}
+
+	if !f.withinPriceCeiling(course, userFilter.MaxPrice, userFilter.Currency) {
+		return false, nil
+	}
+
+	if !f.matchesCertificate(course, userFilter.RequireCertificate) {
+		return false, nil
+	}
+
 	return true, nil
 }
 
@@ -64,44 +113,175 @@ func (f *FilterEngine) SaveUserFilter(userFilter *UserFilter) error {
 	categoriesJSON, _ := json.Marshal(userFilter.Categories)
 	keywordsJSON, _ := json.Marshal(userFilter.Keywords)
 	excludedJSON, _ := json.Marshal(userFilter.ExcludedKeywords)
-
-	query := `INSERT OR REPLACE INTO user_preferences 
-			  (user_id, categories, keywords, excluded_keywords, min_rating, language) 
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	requiredJSON, _ := json.Marshal(userFilter.RequiredKeywords)
+
+	// Upsert so preferences set by other commands (e.g. /maxprice) are kept;
+	// saving a filter is what subscribes a user
+	query := `INSERT INTO user_preferences 
+			  (user_id, categories, keywords, excluded_keywords, min_rating, language, caption_language, required_keywords, subscribed) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1)
+			  ON CONFLICT(user_id) DO UPDATE SET subscribed = 1,
+			  categories = excluded.categories, keywords = excluded.keywords,
+			  excluded_keywords = excluded.excluded_keywords, min_rating = excluded.min_rating,
+			  language = excluded.language, caption_language = excluded.caption_language,
+			  required_keywords = excluded.required_keywords`
 
 	_, err := f.db.Exec(query, userFilter.UserID, string(categoriesJSON), 
-		string(keywordsJSON), string(excludedJSON), userFilter.MinRating, userFilter.Language)
+		string(keywordsJSON), string(excludedJSON), userFilter.MinRating, userFilter.Language,
+		userFilter.CaptionLanguage, string(requiredJSON))
 	
 	return err
 }
 
+// SetMaxPrice stores a user's price ceiling; an amount of 0 removes it
+func (f *FilterEngine) SetMaxPrice(userID int64, amount float64, currencyCode string) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, max_price, currency)
+			  VALUES (?, 'null', 'null', 'null', ?, ?)
+			  ON CONFLICT(user_id) DO UPDATE SET max_price = excluded.max_price, currency = excluded.currency`
+	_, err := f.db.Exec(query, userID, amount, strings.ToUpper(currencyCode))
+	return err
+}
+
+// SetMinRating stores a user's minimum course rating, leaving other filters as they are
+func (f *FilterEngine) SetMinRating(userID int64, rating float64) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, min_rating)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET min_rating = excluded.min_rating`
+	_, err := f.db.Exec(query, userID, rating)
+	return err
+}
+
+// SetTimezone stores a user's timezone; an empty name restores the default
+func (f *FilterEngine) SetTimezone(userID int64, timezone string) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, timezone)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET timezone = excluded.timezone`
+	_, err := f.db.Exec(query, userID, timezone)
+	return err
+}
+
+// SetQuietHours stores a user's quiet hours as HH:MM times in their
+// timezone; empty times turn quiet hours off
+func (f *FilterEngine) SetQuietHours(userID int64, start, end string) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, quiet_start, quiet_end)
+			  VALUES (?, 'null', 'null', 'null', ?, ?)
+			  ON CONFLICT(user_id) DO UPDATE SET quiet_start = excluded.quiet_start, quiet_end = excluded.quiet_end`
+	_, err := f.db.Exec(query, userID, start, end)
+	return err
+}
+
+// SetMaxPerDay stores how many courses a user receives per day; 0 removes the limit
+func (f *FilterEngine) SetMaxPerDay(userID int64, limit int) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, max_per_day)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET max_per_day = excluded.max_per_day`
+	_, err := f.db.Exec(query, userID, limit)
+	return err
+}
+
+// SetPageSize stores how many items a user's paginated lists show; 0
+// restores the default
+func (f *FilterEngine) SetPageSize(userID int64, size int) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, page_size)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET page_size = excluded.page_size`
+	_, err := f.db.Exec(query, userID, size)
+	return err
+}
+
+// SetShowExpired sets whether /browse and /popular include expired courses
+func (f *FilterEngine) SetShowExpired(userID int64, show bool) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, show_expired)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET show_expired = excluded.show_expired`
+	_, err := f.db.Exec(query, userID, show)
+	return err
+}
+
+// SetRequireCertificate sets whether only courses offering a certificate of
+// completion are sent to the user
+func (f *FilterEngine) SetRequireCertificate(userID int64, required bool) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, require_certificate)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET require_certificate = excluded.require_certificate`
+	_, err := f.db.Exec(query, userID, required)
+	return err
+}
+
+// SetDigestSort stores how a user's digests order courses; an empty mode
+// restores the default
+func (f *FilterEngine) SetDigestSort(userID int64, mode string) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, digest_sort)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET digest_sort = excluded.digest_sort`
+	_, err := f.db.Exec(query, userID, mode)
+	return err
+}
+
+// SetRemindAll turns the daily digest of expiring wishlist courses on or off
+func (f *FilterEngine) SetRemindAll(userID int64, enabled bool) error {
+	query := `INSERT INTO user_preferences (user_id, categories, keywords, excluded_keywords, remind_all)
+			  VALUES (?, 'null', 'null', 'null', ?)
+			  ON CONFLICT(user_id) DO UPDATE SET remind_all = excluded.remind_all`
+	_, err := f.db.Exec(query, userID, enabled)
+	return err
+}
+
 func (f *FilterEngine) GetUserFilter(userID int64) (*UserFilter, error) {
 	return f.getUserFilter(userID)
 }
 
 func (f *FilterEngine) getUserFilter(userID int64) (*UserFilter, error) {
-	query := `SELECT categories, keywords, excluded_keywords, min_rating, language 
+	query := `SELECT categories, keywords, excluded_keywords, min_rating, language, COALESCE(caption_language, ''),
+			  COALESCE(max_price, 0), COALESCE(currency, ''), COALESCE(timezone, ''),
+			  COALESCE(quiet_start, ''), COALESCE(quiet_end, ''), COALESCE(remind_all, 0),
+			  COALESCE(max_per_day, 0), COALESCE(show_expired, 0), COALESCE(digest_sort, ''),
+			  COALESCE(required_keywords, 'null'), COALESCE(require_certificate, 0),
+			  COALESCE(page_size, 0), COALESCE(subscribed, 0)
 			  FROM user_preferences WHERE user_id = ?`
 
-	var categoriesJSON, keywordsJSON, excludedJSON string
-	var minRating float64
-	var language string
+	var categoriesJSON, keywordsJSON, excludedJSON, requiredJSON string
+	var minRating, maxPrice float64
+	var language, captionLanguage, currencyCode, timezone string
+	var quietStart, quietEnd string
+	var remindAll bool
+	var maxPerDay int
+	var showExpired bool
+	var digestSort string
+	var requireCertificate bool
+	var pageSize int
+	var subscribed bool
 
 	err := f.db.QueryRow(query, userID).Scan(&categoriesJSON, &keywordsJSON, 
-		&excludedJSON, &minRating, &language)
+		&excludedJSON, &minRating, &language, &captionLanguage, &maxPrice, &currencyCode, &timezone,
+		&quietStart, &quietEnd, &remindAll, &maxPerDay, &showExpired, &digestSort, &requiredJSON, &requireCertificate, &pageSize, &subscribed)
 	if err != nil {
 		return nil, err
 	}
 
 	userFilter := &UserFilter{
-		UserID:    userID,
-		MinRating: minRating,
-		Language:  language,
+		UserID:          userID,
+		MinRating:       minRating,
+		Language:        language,
+		CaptionLanguage: captionLanguage,
+		MaxPrice:        maxPrice,
+		Currency:        currencyCode,
+		Timezone:        timezone,
+		QuietStart:      quietStart,
+		QuietEnd:        quietEnd,
+		RemindAll:       remindAll,
+		MaxPerDay:       maxPerDay,
+		ShowExpired:     showExpired,
+		DigestSort:      digestSort,
+		RequireCertificate: requireCertificate,
+		PageSize:        pageSize,
+		Subscribed:      subscribed,
 	}
 
 	json.Unmarshal([]byte(categoriesJSON), &userFilter.Categories)
 	json.Unmarshal([]byte(keywordsJSON), &userFilter.Keywords)
 	json.Unmarshal([]byte(excludedJSON), &userFilter.ExcludedKeywords)
+	json.Unmarshal([]byte(requiredJSON), &userFilter.RequiredKeywords)
 
 	return userFilter, nil
 }
@@ -137,15 +317,58 @@ func (f *FilterEngine) matchesKeywords(course *database.Course, keywords []strin
 	return false
 }
 
+// matchesRequiredKeywords passes a course only if every required keyword
+// appears in its title or description
+func (f *FilterEngine) matchesRequiredKeywords(course *database.Course, requiredKeywords []string) bool {
+	searchText := strings.ToLower(course.Title + " " + course.Description)
+
+	for _, keyword := range requiredKeywords {
+		if keyword != "" && !strings.Contains(searchText, strings.ToLower(keyword)) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (f *FilterEngine) containsExcludedKeywords(course *database.Course, excludedKeywords []string) bool {
+	_, excluded := MatchExcludedKeyword(course, excludedKeywords)
+	return excluded
+}
+
+// MatchExcludedKeyword returns the first keyword found, case-insensitively,
+// in the course title or description
+func MatchExcludedKeyword(course *database.Course, excludedKeywords []string) (string, bool) {
 	if len(excludedKeywords) == 0 {
-		return false // No exclusions
+		return "", false // No exclusions
 	}
 
 	searchText := strings.ToLower(course.Title + " " + course.Description)
 	
 	for _, keyword := range excludedKeywords {
-		if strings.Contains(searchText, strings.ToLower(keyword)) {
+		if keyword != "" && strings.Contains(searchText, strings.ToLower(keyword)) {
+			return keyword, true
+		}
+	}
+
+	return "", false
+}
+
+// matchesCertificate requires a certificate of completion when asked to.
+// Certificate data is best-effort, so courses where it is unknown pass.
+func (f *FilterEngine) matchesCertificate(course *database.Course, required bool) bool {
+	return !required || course.Certificate == nil || *course.Certificate
+}
+
+// matchesCaptionLanguage requires captions in the given language. Caption data
+// is best-effort, so courses with unknown captions are not filtered out.
+func (f *FilterEngine) matchesCaptionLanguage(course *database.Course, captionLanguage string) bool {
+	if captionLanguage == "" || len(course.CaptionLanguages) == 0 {
+		return true
+	}
+
+	for _, code := range course.CaptionLanguages {
+		if code == captionLanguage {
 			return true
 		}
 	}
@@ -153,8 +376,31 @@ func (f *FilterEngine) containsExcludedKeywords(course *database.Course, exclude
 	return false
 }
 
+// withinPriceCeiling drops courses priced above the user's ceiling once
+// converted to the user's currency. Free courses always pass.
+func (f *FilterEngine) withinPriceCeiling(course *database.Course, maxPrice float64, currencyCode string) bool {
+	if maxPrice <= 0 || database.IsFreePrice(course.Price, course.Discount) {
+		return true
+	}
+
+	amount, courseCurrency, ok := currency.Parse(course.Price)
+	if !ok {
+		return f.unparseablePasses
+	}
+	if currencyCode == "" {
+		currencyCode = "USD"
+	}
+
+	converted, ok := f.converter.Convert(amount, courseCurrency, currencyCode)
+	if !ok {
+		return f.unparseablePasses
+	}
+
+	return converted <= maxPrice
+}
+
 func ParseFilterString(userID int64, filterStr string) *UserFilter {
-	// Parse filter string like: "Development, Business | 4.0 | programming, web | crypto"
+	// Parse filter string like: "Development, Business | 4.0 | programming, web | crypto | es"
 	parts := strings.Split(filterStr, "|")
 	
 	filter := &UserFilter{
@@ -177,25 +423,48 @@ func ParseFilterString(userID int64, filterStr string) *UserFilter {
 		}
 	}
 
-	if len(parts) > 2 && strings.TrimSpace(parts[2]) != "" {
-		keywords := strings.Split(parts[2], ",")
-		for i, kw := range keywords {
-			keywords[i] = strings.TrimSpace(kw)
-		}
-		filter.Keywords = keywords
+	if len(parts) > 2 {
+		filter.Keywords, filter.RequiredKeywords = SplitKeywords(parts[2])
 	}
 
-	if len(parts) > 3 && strings.TrimSpace(parts[3]) != "" {
-		excluded := strings.Split(parts[3], ",")
-		for i, ex := range excluded {
-			excluded[i] = strings.TrimSpace(ex)
-		}
-		filter.ExcludedKeywords = excluded
+	if len(parts) > 3 {
+		filter.ExcludedKeywords = SplitList(parts[3])
+	}
+
+	if len(parts) > 4 && strings.TrimSpace(parts[4]) != "" {
+		filter.CaptionLanguage = language.Code(parts[4])
 	}
 
 	return filter
 }
 
+// SplitKeywords reads comma-separated keywords. Those prefixed with "+" must
+// all match; the rest need only one match.
+func SplitKeywords(text string) (keywords, required []string) {
+	for _, kw := range SplitList(text) {
+		if strings.HasPrefix(kw, "+") {
+			if kw = strings.TrimSpace(strings.TrimPrefix(kw, "+")); kw != "" {
+				required = append(required, kw)
+			}
+			continue
+		}
+		keywords = append(keywords, kw)
+	}
+	return keywords, required
+}
+
+// SplitList splits a comma-separated list, trimming items and dropping
+// empty ones, which would otherwise match every course
+func SplitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func parseFloat(s string) float64 {
 	// Simple float parsing
 	if f := 0.0; len(s) > 0 {
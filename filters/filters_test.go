@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"path/filepath"
+	"testing"
+
+	"udemy-course-notifier/currency"
+	"udemy-course-notifier/database"
+)
+
+// newTestEngine returns a filter engine backed by a fresh database
+func newTestEngine(t *testing.T) *FilterEngine {
+	t.Helper()
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return New(db)
+}
+
+func TestMatchesCaptionLanguage(t *testing.T) {
+	f := &FilterEngine{}
+	tests := []struct {
+		captions []string
+		want     string
+		matches  bool
+	}{
+		{[]string{"en", "es"}, "es", true},
+		{[]string{"en"}, "es", false},
+		{nil, "es", true}, // Unknown captions are not filtered out
+		{[]string{"en"}, "", true},
+	}
+	for _, tt := range tests {
+		course := &database.Course{CaptionLanguages: tt.captions}
+		if got := f.matchesCaptionLanguage(course, tt.want); got != tt.matches {
+			t.Errorf("matchesCaptionLanguage(%v, %q) = %v, want %v", tt.captions, tt.want, got, tt.matches)
+		}
+	}
+}
+
+func TestPriceCeilingMixedCurrencies(t *testing.T) {
+	f := newTestEngine(t)
+	f.SetPriceOptions(currency.NewConverter(map[string]float64{"EUR": 0.5, "INR": 80}), true)
+	const userID = 42
+	if err := f.SetMaxPrice(userID, 15, "USD"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		price, discount string
+		want            bool
+	}{
+		{"$12.99", "80% off", true},
+		{"$19.99", "50% off", false},
+		{"€9.99", "", false}, // 19.98 USD
+		{"₹799", "", true},   // 9.99 USD
+		{"Free", "100% off", true},
+		{"Contact us", "", true}, // Unparseable prices pass by default
+	}
+	for _, tt := range tests {
+		course := &database.Course{Title: "Course", Price: tt.price, Discount: tt.discount}
+		got, err := f.ShouldNotifyCourse(course, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("ShouldNotifyCourse(%s) = %v, want %v", tt.price, got, tt.want)
+		}
+	}
+
+	// The same ceiling in another currency is converted the other way
+	if err := f.SetMaxPrice(userID, 10, "EUR"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := f.ShouldNotifyCourse(&database.Course{Price: "$19.99"}, userID); !got {
+		t.Error("$19.99 is 9.99 EUR and should pass a 10 EUR ceiling")
+	}
+
+	f.SetPriceOptions(currency.NewConverter(nil), false)
+	if got, _ := f.ShouldNotifyCourse(&database.Course{Price: "Contact us"}, userID); got {
+		t.Error("unparseable price passed with unparseablePasses off")
+	}
+}
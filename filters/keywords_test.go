@@ -0,0 +1,58 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestSplitKeywords(t *testing.T) {
+	keywords, required := SplitKeywords(" python, +data ,web, + , +SQL")
+	if want := []string{"python", "web"}; !reflect.DeepEqual(keywords, want) {
+		t.Errorf("keywords = %q, want %q", keywords, want)
+	}
+	if want := []string{"data", "SQL"}; !reflect.DeepEqual(required, want) {
+		t.Errorf("required = %q, want %q", required, want)
+	}
+}
+
+func TestAnyAndAllKeywords(t *testing.T) {
+	f := newTestEngine(t)
+	const userID = 42
+
+	courses := map[string]*database.Course{
+		"python only":  {Title: "Python Basics for Beginners"},
+		"data only":    {Title: "Data Literacy Essentials"},
+		"both":         {Title: "Python for Data Analysis"},
+		"in the blurb": {Title: "Pandas Crash Course", Description: "Wrangle DATA with PYTHON"},
+		"neither":      {Title: "Watercolor Painting"},
+	}
+
+	tests := []struct {
+		filter string
+		want   map[string]bool
+	}{
+		{"| | python, data", map[string]bool{"python only": true, "data only": true, "both": true, "in the blurb": true}},
+		{"| | +python, +data", map[string]bool{"both": true, "in the blurb": true}},
+		{"| | python, +data", map[string]bool{"both": true, "in the blurb": true}},
+		{"| | pandas, analysis, +python", map[string]bool{"both": true, "in the blurb": true}},
+		{"| | +python", map[string]bool{"python only": true, "both": true, "in the blurb": true}},
+	}
+	for _, tt := range tests {
+		userFilter := ParseFilterString(userID, tt.filter)
+		userFilter.Language = ""
+		if err := f.SaveUserFilter(userFilter); err != nil {
+			t.Fatal(err)
+		}
+		for name, course := range courses {
+			got, err := f.ShouldNotifyCourse(course, userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want[name] {
+				t.Errorf("filter %q on %q = %v, want %v", tt.filter, name, got, tt.want[name])
+			}
+		}
+	}
+}
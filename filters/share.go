@@ -0,0 +1,129 @@
+package filters
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"udemy-course-notifier/security"
+)
+
+// maxFilterCodeLength bounds share codes before they are decoded. Filters are
+// limited in characters of up to 4 bytes each, and base64 adds a third.
+const maxFilterCodeLength = 8 * security.MaxFilterStringLength
+
+var (
+	languageCodePattern = regexp.MustCompile(`^[a-z]{2}$`)
+	currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// sharedFilter is the portable part of a UserFilter; short keys keep codes compact
+type sharedFilter struct {
+	Categories       []string `json:"c,omitempty"`
+	MinRating        float64  `json:"r,omitempty"`
+	Keywords         []string `json:"k,omitempty"`
+	RequiredKeywords []string `json:"rk,omitempty"`
+	ExcludedKeywords []string `json:"x,omitempty"`
+	Language         string   `json:"l,omitempty"`
+	CaptionLanguage  string   `json:"cc,omitempty"`
+	MaxPrice         float64  `json:"p,omitempty"`
+	Currency         string   `json:"cur,omitempty"`
+	Certificate      bool     `json:"cert,omitempty"`
+}
+
+// EncodeFilter produces a shareable code for a filter. The user ID is not included.
+func EncodeFilter(userFilter *UserFilter) (string, error) {
+	data, err := json.Marshal(sharedFilter{
+		Categories:       userFilter.Categories,
+		MinRating:        userFilter.MinRating,
+		Keywords:         userFilter.Keywords,
+		RequiredKeywords: userFilter.RequiredKeywords,
+		ExcludedKeywords: userFilter.ExcludedKeywords,
+		Language:         userFilter.Language,
+		CaptionLanguage:  userFilter.CaptionLanguage,
+		MaxPrice:         userFilter.MaxPrice,
+		Currency:         userFilter.Currency,
+		Certificate:      userFilter.RequireCertificate,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode filter: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeFilter turns a share code back into a filter for userID. The decoded
+// filter is held to the same limits as filters typed into /filter.
+func DecodeFilter(userID int64, code string) (*UserFilter, error) {
+	code = strings.TrimSpace(code)
+	if code == "" || len(code) > maxFilterCodeLength {
+		return nil, fmt.Errorf("invalid filter code length")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter code: %w", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+
+	var shared sharedFilter
+	if err := decoder.Decode(&shared); err != nil {
+		return nil, fmt.Errorf("invalid filter code: %w", err)
+	}
+
+	if shared.MinRating < 0 || shared.MinRating > 5 {
+		return nil, fmt.Errorf("min rating out of range")
+	}
+	if shared.MaxPrice < 0 || shared.MaxPrice > 100000 {
+		return nil, fmt.Errorf("max price out of range")
+	}
+	if shared.MaxPrice > 0 && !currencyCodePattern.MatchString(shared.Currency) {
+		return nil, fmt.Errorf("invalid currency %q", shared.Currency)
+	}
+	if shared.Language != "" && !languageCodePattern.MatchString(shared.Language) {
+		return nil, fmt.Errorf("invalid language %q", shared.Language)
+	}
+	if shared.CaptionLanguage != "" && !languageCodePattern.MatchString(shared.CaptionLanguage) {
+		return nil, fmt.Errorf("invalid caption language %q", shared.CaptionLanguage)
+	}
+
+	for _, list := range [][]string{shared.Categories, shared.Keywords, shared.RequiredKeywords, shared.ExcludedKeywords} {
+		for _, item := range list {
+			if item == "" || strings.ContainsAny(item, "|,") || strings.HasPrefix(item, "+") || security.SanitizeString(item) != item {
+				return nil, fmt.Errorf("invalid filter entry %q", item)
+			}
+		}
+	}
+
+	// Round-trip through the /filter text format so the same parsing and
+	// length limits apply as for typed input
+	keywords := append([]string{}, shared.Keywords...)
+	for _, required := range shared.RequiredKeywords {
+		keywords = append(keywords, "+"+required)
+	}
+	filterStr := strings.Join([]string{
+		strings.Join(shared.Categories, ", "),
+		fmt.Sprintf("%.1f", shared.MinRating),
+		strings.Join(keywords, ", "),
+		strings.Join(shared.ExcludedKeywords, ", "),
+		shared.CaptionLanguage,
+	}, " | ")
+	if err := security.ValidateFilterString(filterStr); err != nil {
+		return nil, err
+	}
+
+	userFilter := ParseFilterString(userID, filterStr)
+	if shared.Language != "" {
+		userFilter.Language = shared.Language
+	}
+	if shared.MaxPrice > 0 {
+		userFilter.MaxPrice = shared.MaxPrice
+		userFilter.Currency = shared.Currency
+	}
+	userFilter.RequireCertificate = shared.Certificate
+
+	return userFilter, nil
+}
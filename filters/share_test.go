@@ -0,0 +1,67 @@
+package filters
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilterCodeRoundTrip(t *testing.T) {
+	original := &UserFilter{
+		UserID:             1,
+		Categories:         []string{"Development", "Design"},
+		Keywords:           []string{"golang", "rust"},
+		RequiredKeywords:   []string{"backend"},
+		ExcludedKeywords:   []string{"beginner"},
+		MinRating:          4.5,
+		Language:           "en",
+		CaptionLanguage:    "es",
+		MaxPrice:           15,
+		Currency:           "USD",
+		RequireCertificate: true,
+		// Personal settings are not part of a shared filter
+		Timezone:  "Europe/Berlin",
+		MaxPerDay: 3,
+	}
+
+	code, err := EncodeFilter(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeFilter(2, code)
+	if err != nil {
+		t.Fatalf("DecodeFilter: %v", err)
+	}
+
+	want := *original
+	want.UserID = 2
+	want.Timezone = ""
+	want.MaxPerDay = 0
+	if !reflect.DeepEqual(got, &want) {
+		t.Errorf("round trip = %+v\nwant %+v", got, &want)
+	}
+}
+
+func TestDecodeFilterRejectsMalformedCodes(t *testing.T) {
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := map[string]string{
+		"empty":              "",
+		"not base64":         "not a code!",
+		"not json":           encode("filter"),
+		"unknown field":      encode(`{"c":["Development"],"admin":true}`),
+		"rating too high":    encode(`{"r":7}`),
+		"price without code": encode(`{"p":10}`),
+		"bad language":       encode(`{"l":"english"}`),
+		"separator in entry": encode(`{"k":["go|rust"]}`),
+		"too long":           strings.Repeat("A", maxFilterCodeLength+1),
+	}
+	for name, code := range tests {
+		if _, err := DecodeFilter(1, code); err == nil {
+			t.Errorf("%s: DecodeFilter(%q) succeeded, want an error", name, code)
+		}
+	}
+}
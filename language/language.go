@@ -0,0 +1,71 @@
+package language
+
+import (
+	"regexp"
+	"strings"
+)
+
+// names maps lowercase language names (English and native) to ISO 639-1 codes
+var names = map[string]string{
+	"english":    "en",
+	"spanish":    "es",
+	"español":    "es",
+	"portuguese": "pt",
+	"português":  "pt",
+	"french":     "fr",
+	"français":   "fr",
+	"german":     "de",
+	"deutsch":    "de",
+	"italian":    "it",
+	"italiano":   "it",
+	"japanese":   "ja",
+	"日本語":        "ja",
+	"chinese":    "zh",
+	"中文":         "zh",
+	"korean":     "ko",
+	"한국어":        "ko",
+	"russian":    "ru",
+	"русский":    "ru",
+	"arabic":     "ar",
+	"العربية":    "ar",
+	"hindi":      "hi",
+	"turkish":    "tr",
+	"türkçe":     "tr",
+	"polish":     "pl",
+	"polski":     "pl",
+	"dutch":      "nl",
+	"nederlands": "nl",
+	"indonesian": "id",
+	"vietnamese": "vi",
+	"thai":       "th",
+}
+
+var bracketSuffix = regexp.MustCompile(`\s*[\[(].*?[\])]\s*$`)
+
+// Code converts a language name ("English [Auto]"), tag ("en-US") or code
+// ("en") into a lowercase ISO 639-1 code. Unknown names are returned
+// lowercased and trimmed.
+func Code(name string) string {
+	name = strings.ToLower(strings.TrimSpace(bracketSuffix.ReplaceAllString(name, "")))
+	if name == "" {
+		return ""
+	}
+
+	if code, ok := names[name]; ok {
+		return code
+	}
+
+	// Locale tags such as "en-US" or "pt_BR"
+	if len(name) >= 2 && (len(name) == 2 || name[2] == '-' || name[2] == '_') {
+		return name[:2]
+	}
+
+	// Names with a region, e.g. "English (US)" after bracket removal, or "Chinese Simplified"
+	if fields := strings.Fields(name); len(fields) > 1 {
+		if code, ok := names[fields[0]]; ok {
+			return code
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,38 @@
+package language
+
+import "testing"
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		// Codes and locale tags
+		{"en", "en"},
+		{"EN", "en"},
+		{"en-US", "en"},
+		{"pt_BR", "pt"},
+
+		// English and native names, with Udemy's bracketed notes
+		{"English", "en"},
+		{"English [Auto]", "en"},
+		{"  Español  ", "es"},
+		{"Français (France)", "fr"},
+		{"日本語", "ja"},
+		{"Русский", "ru"},
+		{"Chinese Simplified", "zh"},
+
+		// Unknown names come back lowercased and trimmed
+		{"Klingon", "klingon"},
+		{" Old Norse ", "old norse"},
+		{"Swahili [Auto]", "swahili"},
+		{"", ""},
+		{"   ", ""},
+		{"[Auto]", ""},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.name); got != tt.want {
+			t.Errorf("Code(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
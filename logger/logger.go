@@ -1,12 +1,18 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"udemy-course-notifier/security"
 )
 
 type Logger struct {
+	debug *log.Logger
 	info  *log.Logger
 	error *log.Logger
 	file  *os.File
@@ -19,9 +25,20 @@ func New(logFile string, level string) (*Logger, error) {
 	// Add file output if specified
 	var file *os.File
 	if logFile != "" {
+		if err := security.ValidateFilePath(logFile); err != nil {
+			return nil, fmt.Errorf("invalid log file path: %w", err)
+		}
+
+		// Create the directory on first run, e.g. for logs/app.log
+		if dir := filepath.Dir(logFile); dir != "." {
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				return nil, fmt.Errorf("failed to create log directory %s: %w", dir, err)
+			}
+		}
+
 		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to open log file %s: %w", logFile, err)
 		}
 		file = f
 		writers = append(writers, f)
@@ -29,13 +46,24 @@ func New(logFile string, level string) (*Logger, error) {
 
 	multiWriter := io.MultiWriter(writers...)
 
+	// Debug output is only written when the level is "debug"
+	debugWriter := io.Discard
+	if strings.EqualFold(level, "debug") {
+		debugWriter = multiWriter
+	}
+
 	return &Logger{
+		debug: log.New(debugWriter, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile),
 		info:  log.New(multiWriter, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile),
 		error: log.New(multiWriter, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile),
 		file:  file,
 	}, nil
 }
 
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.debug.Printf(format, v...)
+}
+
 func (l *Logger) Info(v ...interface{}) {
 	l.info.Println(v...)
 }
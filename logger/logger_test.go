@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewCreatesLogDirectory(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "logs", "nested", "app.log")
+
+	l, err := New(logFile, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("first run")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Dir(logFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("log directory mode = %v, want 0700", perm)
+	}
+	contents, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(contents), "first run") {
+		t.Errorf("log file = %q, want the logged line", contents)
+	}
+}
+
+func TestNewReportsUnusableLogDirectory(t *testing.T) {
+	// A file where the directory should be makes creating it fail
+	blocker := filepath.Join(t.TempDir(), "logs")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := New(filepath.Join(blocker, "app.log"), "info")
+	if err == nil || !strings.Contains(err.Error(), "failed to create log directory") {
+		t.Errorf("New = %v, want a log directory error", err)
+	}
+
+	if _, err := New("logs/../../app.log", "info"); err == nil {
+		t.Error("New accepted a log path that escapes its directory")
+	}
+}
@@ -1,21 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"os"
 	"os/signal"
+	"sort"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"udemy-course-notifier/config"
 	"udemy-course-notifier/database"
+	"udemy-course-notifier/filters"
 	"udemy-course-notifier/logger"
 	"udemy-course-notifier/scraper"
+	"udemy-course-notifier/security"
 	"udemy-course-notifier/similarity"
 	"udemy-course-notifier/telegram"
 )
 
 func main() {
+	startedAt := time.Now()
 	log.Println("Starting Udemy Course Notifier Bot...")
 
 	// Load configuration
@@ -39,18 +46,118 @@ func main() {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer db.Close()
+	db.SetExpiryGrace(time.Duration(cfg.Filters.ExpiryGraceMinutes) * time.Minute)
+
+	if cfg.Database.SeedFile != "" {
+		seed, err := database.LoadSeedFile(cfg.Database.SeedFile)
+		if err != nil {
+			log.Fatalf("Failed to load seed file: %v", err)
+		}
+		imported, err := db.AddCourses(seed)
+		if err != nil {
+			log.Fatalf("Failed to import seed file: %v", err)
+		}
+		log.Printf("Imported %d of %d seed courses from %s", imported, len(seed), cfg.Database.SeedFile)
+	}
 
 	// Initialize Telegram bot
 	bot, err := telegram.New(cfg.Telegram.Token, cfg.Telegram.ChannelID, db)
 	if err != nil {
 		log.Fatalf("Failed to initialize bot: %v", err)
 	}
+	bot.SetAdminIDs(cfg.Telegram.AdminIDs)
+	if cfg.Telegram.PreviewMode {
+		if err := bot.SetPreviewChannel(cfg.Telegram.PreviewChannelID); err != nil {
+			log.Fatalf("Failed to set up preview channel: %v", err)
+		}
+	}
+	bot.SetStartTime(startedAt)
+	location, _ := time.LoadLocation(cfg.Telegram.Timezone) // Validated by config.Load
+	bot.SetLocation(location)
+	bot.SetParseMode(cfg.Telegram.ParseMode)
+	bot.SetQuietHoursMode(cfg.Telegram.QuietHoursMode)
+	bot.SetDailyLimitMode(cfg.Telegram.DailyLimitMode)
+	bot.SetReferralCode(cfg.Telegram.ReferralCode)
+	bot.SetVersionAdminOnly(cfg.Telegram.VersionAdminOnly)
+	bot.SetCommandRateLimit(cfg.Telegram.CommandsPerMinute, cfg.Telegram.CommandBurst)
+	bot.SetChannelFailureLimit(cfg.Telegram.ChannelFailureLimit)
+	bot.SetDMInterval(time.Duration(cfg.Telegram.DMIntervalMs) * time.Millisecond)
+	bot.SetRemindAllLeadTime(time.Duration(cfg.Telegram.RemindAllLeadHours) * time.Hour)
+	bot.SetPriceFilterOptions(cfg.Filters.ExchangeRates, cfg.Filters.UnparseablePricePasses)
+	if cfg.Scraping.ShortExpiryMode == "hurry" {
+		bot.SetHurryWindow(time.Duration(cfg.Scraping.MinMinutesUntilExpiry) * time.Minute)
+	}
 
 	// Initialize scraper
 	courseScraper := scraper.New(cfg.Scraping.UserAgent, cfg.Scraping.RateLimitDelaySeconds)
+	courseScraper.SetRequestTimeout(time.Duration(cfg.Scraping.RequestTimeoutSeconds) * time.Second)
+	courseScraper.SetUdemyEnrichment(cfg.Scraping.EnrichFromUdemy)
+	courseScraper.SetVerifyTrackingLinks(cfg.Scraping.VerifyTrackingLinks)
+	courseScraper.SetUdemyMetaCache(db, time.Duration(cfg.Scraping.UdemyMetaTTLHours)*time.Hour)
+	courseScraper.SetMaxExpiryHorizon(time.Duration(cfg.Scraping.MaxExpiryDays) * 24 * time.Hour)
+	courseScraper.SetUnknownExpiryMode(cfg.Scraping.UnknownExpiry)
+	courseScraper.SetFollowCoupons(cfg.Scraping.FollowCoupons)
+	courseScraper.SetMaxResponseBytes(cfg.Scraping.MaxResponseBytes)
+	courseScraper.SetAcceptDashboardRedirects(cfg.Scraping.AcceptDashboardRedirects)
+	courseScraper.SetCouponFollowConcurrency(cfg.Scraping.CouponFollowConcurrency)
+	courseScraper.SetCategoryKeywords(cfg.Scraping.CategoryKeywords)
+	courseScraper.SetCouponRetryHandler(func(pending database.PendingCoupon) {
+		if err := db.AddPendingCoupon(pending); err != nil {
+			log.Printf("Failed to queue coupon for retry: %v", err)
+		}
+	})
+	courseScraper.SetCouponGivenUpCheck(func(couponURL string) bool {
+		givenUp, err := db.IsCouponGivenUp(couponURL)
+		if err != nil {
+			log.Printf("Failed to check coupon %s: %v", couponURL, err)
+		}
+		return givenUp
+	})
+	if err := courseScraper.SetExcludedPathPatterns(cfg.Scraping.ExcludedPathPatterns); err != nil {
+		log.Fatalf("Failed to configure scraper: %v", err)
+	}
+	if err := similarity.ValidatePriority(cfg.Scoring.DedupPriority); err != nil {
+		log.Fatalf("Invalid scoring configuration: %v", err)
+	}
+	var altWeights *scraper.ScoringWeights
+	if cfg.Scoring.ABTest.Enabled {
+		altWeights = &cfg.Scoring.ABTest.Weights
+	}
+	courseScraper.SetScoring(cfg.Scoring.Weights, altWeights)
+	bot.SetQualityScorer(scraper.NewQualityScorer(cfg.Scoring.Weights))
+	bot.SetSourceTrust(cfg.Scraping.SourceTrust)
+
+	sourceTracker := scraper.NewSourceTracker(cfg.Scraping.CircuitBreakerThreshold,
+		time.Duration(cfg.Scraping.CircuitBreakerCooldownMinutes)*time.Minute)
+	bot.SetSourceTracker(sourceTracker, cfg.Scraping.SourceURLs)
+	courseScraper.SetCouponFollowRecorder(func(sourceURL string, attempts, resolved int) {
+		if err := db.RecordCouponFollows(sourceURL, attempts, resolved); err != nil {
+			log.Printf("Failed to record coupon follows for %s: %v", sourceURL, err)
+		}
+	})
+
+	// Cancelled on shutdown so in-flight scrapes stop promptly
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	// Start course monitoring in a separate goroutine
-	go startCourseMonitoring(cfg, courseScraper, db, bot)
+	go startCourseMonitoring(ctx, cfg, courseScraper, sourceTracker, db, bot, appLogger)
+
+	// Start reminder scheduler in a separate goroutine
+	go startReminderScheduler(bot)
+
+	// Start retention cleanup in a separate goroutine
+	go startRetentionCleanup(ctx, cfg, db)
+
+	// Keep the pinned channel dashboard up to date
+	if cfg.Telegram.DashboardIntervalMinutes > 0 {
+		go startDashboardUpdates(ctx, time.Duration(cfg.Telegram.DashboardIntervalMinutes)*time.Minute, bot)
+	}
+
+	// Backfill details of courses stored while a page was failing
+	if cfg.Scraping.ReenrichPerCycle > 0 {
+		go startReenrichment(ctx, cfg, courseScraper, db)
+	}
 
 	// Start bot in a separate goroutine
 	go func() {
@@ -67,74 +174,407 @@ func main() {
 	<-c
 
 	log.Println("Shutting down gracefully...")
+	cancel()
 }
 
-func startCourseMonitoring(cfg *config.Config, scraper *scraper.Scraper, db *database.DB, bot *telegram.Bot) {
+func startCourseMonitoring(ctx context.Context, cfg *config.Config, source CourseSource, tracker SourceHealth, store CourseStore, bot *telegram.Bot, appLogger *logger.Logger) {
 	ticker := time.NewTicker(time.Duration(cfg.Scraping.IntervalMinutes) * time.Minute)
 	defer ticker.Stop()
 
+	// Prevents overlapping scans from inserting the same courses twice
+	var scanning atomic.Bool
+
+	runScan := func() {
+		result := scanForCourses(ctx, &scanning, cfg, source, tracker, store, bot, appLogger)
+		logScanResult(result, cfg.Scraping.MinPostQualityScore)
+		if !result.Skipped && !result.Cancelled {
+			bot.RecordScan(result.Deduplicated)
+		}
+	}
+
 	// Run initial scan
-	scanForCourses(cfg, scraper, db, bot)
+	runScan()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runScan()
+		}
+	}
+}
+
+func startReminderScheduler(bot *telegram.Bot) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
 
 	for range ticker.C {
-		scanForCourses(cfg, scraper, db, bot)
+		bot.SendDueReminders()
+		bot.DeliverHeldNotifications()
+		bot.SendWishlistExpiryDigests()
+	}
+}
+
+// startDashboardUpdates refreshes the pinned channel dashboard every interval
+func startDashboardUpdates(ctx context.Context, interval time.Duration, bot *telegram.Bot) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		bot.UpdateDashboard()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
 }
 
-func scanForCourses(cfg *config.Config, scraper *scraper.Scraper, db *database.DB, bot *telegram.Bot) {
+// startReenrichment retries courses stored with missing details once per
+// scan interval, a few at a time so the extra requests stay light
+func startReenrichment(ctx context.Context, cfg *config.Config, courseScraper *scraper.Scraper, db *database.DB) {
+	ticker := time.NewTicker(time.Duration(cfg.Scraping.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		reenrichCourses(ctx, cfg.Scraping.ReenrichPerCycle, courseScraper, db)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reenrichCourses backfills up to limit courses from their Udemy pages
+func reenrichCourses(ctx context.Context, limit int, courseScraper *scraper.Scraper, db *database.DB) {
+	courses, err := db.GetUnderEnrichedCourses(limit)
+	if err != nil {
+		log.Printf("Failed to get courses to re-enrich: %v", err)
+		return
+	}
+
+	updated := 0
+	for i := range courses {
+		if ctx.Err() != nil {
+			return
+		}
+
+		course := &courses[i]
+		changed, err := courseScraper.ReenrichCourse(ctx, course)
+		if err != nil {
+			log.Printf("Failed to re-enrich %s: %v", course.Title, err)
+		}
+		if err := db.SaveEnrichment(course); err != nil {
+			log.Printf("Failed to save re-enriched course %d: %v", course.ID, err)
+			continue
+		}
+		if changed {
+			updated++
+		}
+	}
+
+	if updated > 0 {
+		log.Printf("Re-enrichment: filled in details for %d of %d courses", updated, len(courses))
+	}
+}
+
+// startRetentionCleanup applies the retention policy at startup and then daily
+func startRetentionCleanup(ctx context.Context, cfg *config.Config, db *database.DB) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		applyRetention(cfg, db)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyRetention deletes rows older than their configured retention
+func applyRetention(cfg *config.Config, db *database.DB) {
+	if days := cfg.Retention.CoursesDays; days > 0 {
+		deleted, err := db.CleanupOldCourses(days)
+		if err != nil {
+			log.Printf("Retention cleanup failed: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Retention: removed %d courses older than %d days", deleted, days)
+		}
+		if err := db.PruneGivenUpCoupons(days); err != nil {
+			log.Printf("Retention cleanup failed: %v", err)
+		}
+	}
+
+	if days := cfg.Retention.DeliveredDays; days > 0 {
+		if err := db.PruneDelivered(days); err != nil {
+			log.Printf("Retention cleanup failed: %v", err)
+		}
+	}
+
+	if days := cfg.Retention.FeedbackDays; days > 0 {
+		if err := db.PruneFeedback(days); err != nil {
+			log.Printf("Retention cleanup failed: %v", err)
+		}
+	}
+}
+
+// scanForCourses runs one scan cycle. scanning is set for the duration of the
+// scan; a scan started while it is already set is skipped.
+func scanForCourses(ctx context.Context, scanning *atomic.Bool, cfg *config.Config, source CourseSource, tracker SourceHealth, store CourseStore, notifier Notifier, appLogger *logger.Logger) (result ScanResult) {
+	if !scanning.CompareAndSwap(false, true) {
+		log.Println("Previous scan still running, skipping this one")
+		return ScanResult{Skipped: true}
+	}
+	defer scanning.Store(false)
+
+	result.StartedAt = time.Now()
+	result.SourceErrors = make(map[string]error)
+	defer func() {
+		result.Duration = time.Since(result.StartedAt)
+	}()
+
 	log.Println("Scanning for new courses...")
 
 	// Initialize similarity engine
 	similarityEngine := similarity.New(0.85) // 85% similarity threshold
+	similarityEngine.SetPriority(cfg.Scoring.DedupPriority) // Validated at startup
+	similarityEngine.SetSynonyms(cfg.Scoring.DedupSynonyms)
+	similarityEngine.SetSlugDedup(cfg.Scoring.DedupBySlug)
+	similarityEngine.SetWindow(cfg.Scoring.DedupWindow)
+	similarityEngine.SetStopWords(cfg.Scoring.DedupStopWords)
 	var allNewCourses []database.Course
+	seenURLs := make(map[string]bool) // URLs already collected during this scan
+	reposts := make(map[string]bool)  // Stored courses older than the dedup lookback, posted again
 
-	for _, sourceURL := range cfg.Scraping.SourceURLs {
-		courses, err := scraper.ScrapeCoursesFromURL(sourceURL)
-		if err != nil {
-			log.Printf("Failed to scrape %s: %v", sourceURL, err)
-			continue
-		}
+	// Stored courses only count as already seen within the lookback window
+	lookback := time.Duration(cfg.Scraping.DedupLookbackDays) * 24 * time.Hour
 
-		// Filter out existing courses
-		var newCourses []database.Course
+	// collectNew keeps courses not yet seen in this scan or stored before
+	collectNew := func(courses []database.Course) {
 		for _, course := range courses {
-			exists, err := db.CourseExists(course.URL)
+			// Collapse identical URLs found on multiple sources before the similarity pass
+			if seenURLs[course.URL] {
+				continue
+			}
+			seenURLs[course.URL] = true
+
+			// Only links to Udemy or known tracking domains are stored
+			if err := security.ValidateCourseURL(course.URL); err != nil {
+				log.Printf("Dropping %s: %v", course.Title, err)
+				result.Rejected++
+				continue
+			}
+
+			// Content policy applies before anything is stored or posted
+			if keyword, excluded := filters.MatchExcludedKeyword(&course, cfg.Filters.GlobalExcludedKeywords); excluded {
+				appLogger.Debugf("Dropping %s: matches global excluded keyword %q", course.Title, keyword)
+				result.Excluded++
+				continue
+			}
+
+			postedAt, exists, err := store.CoursePostedAt(course.URL)
 			if err != nil {
 				log.Printf("Failed to check if course exists: %v", err)
 				continue
 			}
 
-			if !exists {
-				newCourses = append(newCourses, course)
+			if exists && (lookback <= 0 || time.Since(postedAt) < lookback) {
+				continue
+			}
+			if exists {
+				reposts[course.URL] = true
+			}
+			allNewCourses = append(allNewCourses, course)
+		}
+	}
+
+	// Coupons that failed to resolve in earlier cycles get another chance first
+	collectNew(retryPendingCoupons(ctx, cfg, source, store))
+
+	for _, sourceURL := range sourcesForCycle(cfg, store) {
+		if ctx.Err() != nil {
+			result.Cancelled = true
+			return result
+		}
+
+		if !tracker.Allow(sourceURL) {
+			log.Printf("Skipping %s: circuit breaker open", sourceURL)
+			continue
+		}
+
+		courses, err := source.ScrapeCoursesFromURL(ctx, sourceURL)
+		if err != nil {
+			result.SourceErrors[sourceURL] = err
+			if ctx.Err() == nil {
+				tracker.RecordFailure(sourceURL, err)
 			}
+			continue
+		}
+		tracker.RecordSuccess(sourceURL, len(courses))
+
+		// Editorial weighting happens before dedup so it can decide survivors;
+		// the source is stored so /rescore can weight the course again
+		for i := range courses {
+			courses[i].SourceURL = sourceURL
 		}
+		applySourceTrust(courses, cfg.Scraping.SourceTrust[sourceURL])
 
-		allNewCourses = append(allNewCourses, newCourses...)
+		// Let wishlist owners know when a saved course becomes free again
+		notifier.NotifyWishlistPriceDrops(courses)
+
+		// Filter out existing courses
+		collectNew(courses)
 	}
 
 	// Deduplicate courses across all sources
-	log.Printf("Found %d new courses before deduplication", len(allNewCourses))
+	result.Found = len(allNewCourses)
 	deduplicatedCourses := similarityEngine.DeduplicateCourses(allNewCourses)
-	log.Printf("After deduplication: %d unique courses", len(deduplicatedCourses))
+	result.Deduplicated = len(deduplicatedCourses)
+
+	if cfg.Scoring.ABTest.Enabled {
+		logScoringComparison(similarityEngine, allNewCourses, deduplicatedCourses)
+	}
+
+	if cfg.Scraping.InterleaveCategories {
+		deduplicatedCourses = interleaveCategories(deduplicatedCourses)
+	}
 
 	// Process deduplicated courses
+	pacer := postPacer{delay: time.Duration(cfg.Telegram.InterPostDelayMs) * time.Millisecond}
 	for _, course := range deduplicatedCourses {
-		// Add course to database
-		if err := db.AddCourse(&course); err != nil {
+		// Tracking links can expire while the listing still shows them; only
+		// new courses are checked, to spare a request per listing per scan
+		if err := source.VerifyCourseLink(ctx, course.URL); err != nil {
+			if ctx.Err() != nil {
+				result.Cancelled = true
+				return result
+			}
+			log.Printf("Skipping dead tracking link %s: %v", course.URL, err)
+			result.DeadLinks++
+			continue
+		}
+
+		// Details from the Udemy page, fetched for new courses only
+		source.EnrichCourse(ctx, &course)
+
+		// Add course to database, or refresh the old row of a course posted
+		// again after the dedup lookback
+		if reposts[course.URL] {
+			if err := store.RefreshCourse(&course); err != nil {
+				log.Printf("Failed to refresh course in database: %v", err)
+				continue
+			}
+		} else if err := store.AddCourse(&course); err != nil {
 			log.Printf("Failed to add course to database: %v", err)
 			continue
 		}
+		result.Stored++
+
+		// Keep low-quality courses searchable but out of the channel
+		if course.QualityScore < postQualityThreshold(cfg, course.Category) {
+			result.Gated++
+			continue
+		}
+
+		// A coupon about to run out isn't worth a channel post
+		if skipsShortExpiry(cfg, &course, time.Now()) {
+			result.ExpiringSoon++
+			continue
+		}
+
+		// Admins decide what reaches the channel and subscribers; approved
+		// courses are sent to subscribers from the Approve button
+		if cfg.Telegram.ApprovalMode {
+			if err := notifier.RequestApproval(&course); err != nil {
+				log.Printf("Failed to request approval: %v", err)
+			} else {
+				result.Queued++
+			}
+			continue
+		}
+
+		// Send matching courses to users' private chats
+		notifier.NotifySubscribers(&course)
 
 		// Post to Telegram channel
-		if err := bot.PostCourse(&course); err != nil {
+		pacer.wait()
+		err := notifier.PostCourse(&course)
+		if errors.Is(err, telegram.ErrChannelUnavailable) {
+			// Admins were alerted when posting was paused. The course stays
+			// stored but is not posted once the channel is back.
+			continue
+		}
+		pacer.posted()
+		if err != nil {
 			log.Printf("Failed to post course to Telegram: %v", err)
 		} else {
+			result.Posted++
 			log.Printf("Posted new course: %s (Quality: %.1f)", course.Title, course.QualityScore)
 		}
+	}
+
+	return result
+}
+
+// logScoringComparison reports how the alternative scoring formula would have
+// changed scores, deduplication survivors and ranking, without affecting posts
+func logScoringComparison(engine *similarity.SimilarityEngine, courses, deduplicated []database.Course) {
+	altCourses := make([]database.Course, len(courses))
+	for i, course := range courses {
+		if course.QualityScoreAlt != nil {
+			log.Printf("A/B score: %.1f vs %.1f for %s", course.QualityScore, *course.QualityScoreAlt, course.Title)
+			course.QualityScore = *course.QualityScoreAlt
+		}
+		altCourses[i] = course
+	}
+
+	altDeduplicated := engine.DeduplicateCourses(altCourses)
+
+	survivors := make(map[string]bool)
+	for _, course := range deduplicated {
+		survivors[course.URL] = true
+	}
+	altSurvivors := make(map[string]bool)
+	for _, course := range altDeduplicated {
+		altSurvivors[course.URL] = true
+		if !survivors[course.URL] {
+			log.Printf("A/B dedup: only the alternative formula keeps %s", course.Title)
+		}
+	}
+	for _, course := range deduplicated {
+		if !altSurvivors[course.URL] {
+			log.Printf("A/B dedup: only the current formula keeps %s", course.Title)
+		}
+	}
 
-		// Rate limiting between posts
-		time.Sleep(2 * time.Second)
+	rankChanges := 0
+	current := rankByScore(deduplicated)
+	for url, rank := range rankByScore(altDeduplicated) {
+		if currentRank, ok := current[url]; ok && currentRank != rank {
+			rankChanges++
+		}
 	}
+	log.Printf("A/B ranking: %d of %d surviving courses would change position", rankChanges, len(altDeduplicated))
+}
+
+// rankByScore maps course URLs to their position when ordered by quality score
+func rankByScore(courses []database.Course) map[string]int {
+	sorted := make([]database.Course, len(courses))
+	copy(sorted, courses)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].QualityScore > sorted[j].QualityScore
+	})
 
-	log.Println("Course scan completed")
-}
\ No newline at end of file
+	ranks := make(map[string]int, len(sorted))
+	for i, course := range sorted {
+		ranks[course.URL] = i
+	}
+	return ranks
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"udemy-course-notifier/config"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/similarity"
+)
+
+func TestApplyRetention(t *testing.T) {
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	course := database.Course{URL: "https://www.udemy.com/course/old/", Title: "Old Course", Category: "Development"}
+	if err := db.AddCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.MarkDelivered(1, course.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{
+		`UPDATE courses SET posted_at = datetime('now', '-60 days')`,
+		`UPDATE delivered SET delivered_at = datetime('now', '-60 days')`,
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Zero days disables each policy
+	applyRetention(&config.Config{}, db)
+	if delivered, _ := db.WasDelivered(1, course.ID); !delivered {
+		t.Fatal("retention with no policies removed a delivery")
+	}
+
+	cfg := &config.Config{}
+	cfg.Retention.DeliveredDays = 30
+	applyRetention(cfg, db)
+	if delivered, _ := db.WasDelivered(1, course.ID); delivered {
+		t.Error("delivery older than delivered_days was kept")
+	}
+	if _, err := db.GetCourse(course.ID); err != nil {
+		t.Errorf("course removed without a courses_days policy: %v", err)
+	}
+
+	cfg.Retention.CoursesDays = 30
+	applyRetention(cfg, db)
+	if _, err := db.GetCourse(course.ID); err == nil {
+		t.Error("course older than courses_days was kept")
+	}
+}
+
+func TestLogScoringComparison(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	score := func(current, alt float64, slug, title string) database.Course {
+		course := testCourse(slug, title, current)
+		course.QualityScoreAlt = &alt
+		return course
+	}
+	// Two listings of one course the formulas disagree on, and a course
+	// only the alternative formula ranks first
+	favoured := score(80, 40, "docker-a", "Docker Mastery with Kubernetes")
+	challenger := score(60, 90, "docker-b", "Docker Mastery with Kubernetes")
+	other := score(70, 95, "cooking", "Italian Cooking at Home")
+	other.Category = "Lifestyle"
+
+	engine := similarity.New(0.85)
+	courses := []database.Course{favoured, challenger, other}
+	deduplicated := engine.DeduplicateCourses(append([]database.Course(nil), courses...))
+	logScoringComparison(engine, courses, deduplicated)
+
+	for _, want := range []string{
+		"A/B score: 80.0 vs 40.0 for Docker Mastery with Kubernetes",
+		"A/B dedup: only the alternative formula keeps Docker Mastery with Kubernetes",
+		"A/B dedup: only the current formula keeps Docker Mastery with Kubernetes",
+		"A/B ranking: 1 of 2 surviving courses would change position",
+	} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("logs missing %q:\n%s", want, logs.String())
+		}
+	}
+
+	// The comparison only reports; the current survivors are left alone
+	if !sameURLs([]string{deduplicated[0].URL, deduplicated[1].URL}, []string{favoured.URL, other.URL}) {
+		t.Errorf("deduplicated = %+v, want the current formula's survivors", deduplicated)
+	}
+}
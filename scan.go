@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"udemy-course-notifier/config"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/scraper"
+	"udemy-course-notifier/telegram"
+)
+
+// ScanResult summarises one scan cycle
+type ScanResult struct {
+	StartedAt    time.Time
+	Duration     time.Duration
+	Found        int              // New courses collected across all sources
+	Deduplicated int              // Courses left after similarity deduplication
+	Stored       int              // Courses saved to the database
+	Posted       int              // Courses posted to the channel
+	Gated        int              // Stored but kept out of the channel by quality score
+	ExpiringSoon int              // Stored but not posted because the coupon was about to expire
+	Queued       int              // Sent to admins for approval instead of being posted
+	Excluded     int              // Dropped by global excluded keywords
+	Rejected     int              // Dropped because the course link points at an unexpected domain
+	DeadLinks    int              // Dropped because the tracking link no longer reaches a course
+	SourceErrors map[string]error // Scrape failures keyed by source URL
+	Skipped      bool             // A previous scan was still running
+	Cancelled    bool             // The scan stopped early on shutdown
+}
+
+// CourseSource fetches courses from an aggregator page
+type CourseSource interface {
+	ScrapeCoursesFromURL(ctx context.Context, sourceURL string) ([]database.Course, error)
+	ResolvePendingCoupon(ctx context.Context, pending database.PendingCoupon) (database.Course, error)
+	VerifyCourseLink(ctx context.Context, courseURL string) error
+	EnrichCourse(ctx context.Context, course *database.Course)
+}
+
+// CourseStore records which courses have already been seen
+type CourseStore interface {
+	CoursePostedAt(url string) (time.Time, bool, error)
+	AddCourse(course *database.Course) error
+	RefreshCourse(course *database.Course) error
+	SourceCursor() (int, error)
+	SetSourceCursor(cursor int) error
+	GetDuePendingCoupons(now time.Time, limit int) ([]database.PendingCoupon, error)
+	ReschedulePendingCoupon(url string, attempts int, nextTry time.Time) error
+	DeletePendingCoupon(url string) error
+	GiveUpPendingCoupon(url string) error
+}
+
+// SourceHealth tracks scrape results per source and decides whether a
+// source is scraped this cycle
+type SourceHealth interface {
+	Allow(sourceURL string) bool
+	RecordSuccess(sourceURL string, courseCount int)
+	RecordFailure(sourceURL string, err error)
+}
+
+// Notifier delivers newly found courses to the channel and subscribers
+type Notifier interface {
+	NotifyWishlistPriceDrops(courses []database.Course)
+	NotifySubscribers(course *database.Course)
+	PostCourse(course *database.Course) error
+	RequestApproval(course *database.Course) error
+}
+
+var (
+	_ CourseSource = (*scraper.Scraper)(nil)
+	_ CourseStore  = (*database.DB)(nil)
+	_ SourceHealth = (*scraper.SourceTracker)(nil)
+	_ Notifier     = (*telegram.Bot)(nil)
+)
+
+// applySourceTrust scales the quality scores of courses from a source by the
+// operator's trust in it, capped at 100. Sources without an entry keep 1.0.
+func applySourceTrust(courses []database.Course, trust float64) {
+	if trust <= 0 || trust == 1 {
+		return
+	}
+
+	for i := range courses {
+		courses[i].QualityScore = scraper.TrustedScore(courses[i].QualityScore, trust)
+		if alt := courses[i].QualityScoreAlt; alt != nil {
+			scaled := scraper.TrustedScore(*alt, trust)
+			courses[i].QualityScoreAlt = &scaled
+		}
+	}
+}
+
+const (
+	pendingCouponsPerScan  = 20               // Retries attempted per scan, oldest first
+	pendingCouponBaseDelay = 15 * time.Minute // Wait after the first failed retry
+	pendingCouponMaxDelay  = 24 * time.Hour
+)
+
+// pendingCouponDelay returns the wait before the next retry of a coupon that
+// has failed attempts times, doubling each time up to pendingCouponMaxDelay
+func pendingCouponDelay(attempts int) time.Duration {
+	delay := pendingCouponBaseDelay
+	for i := 1; i < attempts && delay < pendingCouponMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > pendingCouponMaxDelay {
+		delay = pendingCouponMaxDelay
+	}
+	return delay
+}
+
+// retryPendingCoupons follows coupon links that failed in earlier cycles and
+// returns the courses that now resolve. Failures are rescheduled with backoff
+// and dropped after the configured number of attempts.
+func retryPendingCoupons(ctx context.Context, cfg *config.Config, source CourseSource, store CourseStore) []database.Course {
+	now := time.Now()
+	pending, err := store.GetDuePendingCoupons(now, pendingCouponsPerScan)
+	if err != nil {
+		log.Printf("Failed to load pending coupons: %v", err)
+		return nil
+	}
+
+	var resolved []database.Course
+	for _, p := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+
+		course, err := source.ResolvePendingCoupon(ctx, p)
+		if err == nil {
+			course.SourceURL = p.SourceURL
+			batch := []database.Course{course}
+			applySourceTrust(batch, cfg.Scraping.SourceTrust[p.SourceURL])
+			resolved = append(resolved, batch...)
+			if err := store.DeletePendingCoupon(p.URL); err != nil {
+				log.Printf("Failed to delete pending coupon: %v", err)
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			break // Shutdown is not the coupon's fault
+		}
+
+		attempts := p.Attempts + 1
+		if attempts >= cfg.Scraping.CouponRetryAttempts {
+			log.Printf("Giving up on coupon %s after %d attempts: %v", p.URL, attempts, err)
+			if err := store.GiveUpPendingCoupon(p.URL); err != nil {
+				log.Printf("Failed to give up pending coupon: %v", err)
+			}
+			continue
+		}
+
+		log.Printf("Retry %d of coupon %s failed: %v", attempts, p.URL, err)
+		if err := store.ReschedulePendingCoupon(p.URL, attempts, now.Add(pendingCouponDelay(attempts))); err != nil {
+			log.Printf("Failed to reschedule pending coupon: %v", err)
+		}
+	}
+
+	if len(resolved) > 0 {
+		log.Printf("Resolved %d previously failed coupon links", len(resolved))
+	}
+	return resolved
+}
+
+// rotateSources picks up to max sources starting at cursor, wrapping around
+// the list, and returns them with the cursor for the next cycle. A max of 0,
+// or one covering every source, selects them all.
+func rotateSources(sources []string, max, cursor int) ([]string, int) {
+	if max <= 0 || max >= len(sources) {
+		return sources, 0
+	}
+
+	start := cursor % len(sources)
+	if start < 0 {
+		start = 0
+	}
+
+	selected := make([]string, 0, max)
+	for i := 0; i < max; i++ {
+		selected = append(selected, sources[(start+i)%len(sources)])
+	}
+	return selected, (start + max) % len(sources)
+}
+
+// sourcesForCycle returns the sources to scrape this cycle and advances the
+// stored rotation cursor
+func sourcesForCycle(cfg *config.Config, store CourseStore) []string {
+	cursor, err := store.SourceCursor()
+	if err != nil {
+		log.Printf("Failed to read source rotation cursor: %v", err)
+	}
+
+	sources, next := rotateSources(cfg.Scraping.SourceURLs, cfg.Scraping.MaxSourcesPerCycle, cursor)
+	if len(sources) < len(cfg.Scraping.SourceURLs) {
+		if err := store.SetSourceCursor(next); err != nil {
+			log.Printf("Failed to save source rotation cursor: %v", err)
+		}
+	}
+	return sources
+}
+
+// interleaveCategories reorders courses so that two courses of the same
+// category are not adjacent whenever another category is still waiting. It
+// repeatedly takes the next course of the category with the most courses left,
+// skipping the category just used; courses keep their order within a category.
+func interleaveCategories(courses []database.Course) []database.Course {
+	var order []string // Categories in order of first appearance, for stable ties
+	queues := make(map[string][]database.Course)
+	for _, course := range courses {
+		key := strings.ToLower(strings.TrimSpace(course.Category))
+		if _, seen := queues[key]; !seen {
+			order = append(order, key)
+		}
+		queues[key] = append(queues[key], course)
+	}
+
+	interleaved := make([]database.Course, 0, len(courses))
+	last := -1
+	for len(interleaved) < len(courses) {
+		pick := -1
+		for i, key := range order {
+			if len(queues[key]) == 0 || i == last {
+				continue
+			}
+			if pick == -1 || len(queues[key]) > len(queues[order[pick]]) {
+				pick = i
+			}
+		}
+		if pick == -1 {
+			pick = last // Only the last category is left
+		}
+
+		key := order[pick]
+		interleaved = append(interleaved, queues[key][0])
+		queues[key] = queues[key][1:]
+		last = pick
+	}
+	return interleaved
+}
+
+// postPacer spaces out channel posts. The delay is measured from the previous
+// post, so the first post of a scan goes out at once and none follows the last.
+type postPacer struct {
+	delay time.Duration
+	last  time.Time // Zero until the first post
+}
+
+// wait sleeps until delay has passed since the previous post
+func (p *postPacer) wait() {
+	if p.last.IsZero() {
+		return
+	}
+	if remaining := p.delay - time.Since(p.last); remaining > 0 {
+		time.Sleep(remaining)
+	}
+}
+
+// posted records that a post was just sent
+func (p *postPacer) posted() {
+	p.last = time.Now()
+}
+
+// skipsShortExpiry reports whether a course is stored but not posted
+// because its coupon expires sooner than the configured minimum. In hurry
+// mode such courses are posted and the bot flags them instead.
+func skipsShortExpiry(cfg *config.Config, course *database.Course, now time.Time) bool {
+	if cfg.Scraping.ShortExpiryMode != "skip" {
+		return false
+	}
+	window := time.Duration(cfg.Scraping.MinMinutesUntilExpiry) * time.Minute
+	return database.ExpiresWithin(course, window, now)
+}
+
+// postQualityThreshold returns the quality score a course in category needs
+// to be posted to the channel: the category's own threshold if configured,
+// otherwise the global one. Categories match case-insensitively.
+func postQualityThreshold(cfg *config.Config, category string) float64 {
+	for name, score := range cfg.Scraping.CategoryMinPostQualityScore {
+		if strings.EqualFold(name, category) {
+			return score
+		}
+	}
+	return cfg.Scraping.MinPostQualityScore
+}
+
+// logScanResult writes a one-line summary of a scan, plus any source failures
+func logScanResult(result ScanResult, minPostQualityScore float64) {
+	if result.Skipped {
+		return
+	}
+
+	for sourceURL, err := range result.SourceErrors {
+		log.Printf("Scan error for %s: %v", sourceURL, err)
+	}
+
+	if result.Excluded > 0 {
+		log.Printf("Dropped %d courses matching global excluded keywords", result.Excluded)
+	}
+
+	if result.Rejected > 0 {
+		log.Printf("Rejected %d courses linking outside Udemy and known tracking domains", result.Rejected)
+	}
+
+	if result.DeadLinks > 0 {
+		log.Printf("Dropped %d courses whose tracking links no longer reach a course page", result.DeadLinks)
+	}
+
+	if result.Queued > 0 {
+		log.Printf("Sent %d courses to admins for approval", result.Queued)
+	}
+
+	if result.ExpiringSoon > 0 {
+		log.Printf("Stored without posting %d courses expiring within scraping.min_minutes_until_expiry", result.ExpiringSoon)
+	}
+
+	if result.Gated > 0 {
+		log.Printf("Stored without posting %d courses below the post quality score (%.0f, or their category's own threshold)", result.Gated, minPostQualityScore)
+	}
+
+	status := "completed"
+	if result.Cancelled {
+		status = "cancelled"
+	}
+	log.Printf("Course scan %s in %s: %d found, %d unique, %d stored, %d posted, %d failed sources",
+		status, result.Duration.Round(time.Second), result.Found, result.Deduplicated,
+		result.Stored, result.Posted, len(result.SourceErrors))
+}
@@ -0,0 +1,847 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/config"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/logger"
+	"udemy-course-notifier/telegram"
+)
+
+type fakeSource struct {
+	courses   map[string][]database.Course
+	errs      map[string]error
+	deadLinks map[string]bool
+	resolves  map[string]database.Course // Pending coupon URLs that now resolve
+}
+
+func (f *fakeSource) ScrapeCoursesFromURL(ctx context.Context, sourceURL string) ([]database.Course, error) {
+	if err := f.errs[sourceURL]; err != nil {
+		return nil, err
+	}
+	return append([]database.Course(nil), f.courses[sourceURL]...), nil
+}
+
+func (f *fakeSource) ResolvePendingCoupon(ctx context.Context, pending database.PendingCoupon) (database.Course, error) {
+	if course, ok := f.resolves[pending.URL]; ok {
+		return course, nil
+	}
+	return database.Course{}, errors.New("still failing")
+}
+
+func (f *fakeSource) EnrichCourse(ctx context.Context, course *database.Course) {}
+
+func (f *fakeSource) VerifyCourseLink(ctx context.Context, courseURL string) error {
+	if f.deadLinks[courseURL] {
+		return errors.New("dead link")
+	}
+	return nil
+}
+
+type fakeHealth struct {
+	blocked   map[string]bool
+	successes map[string]int
+	failures  map[string]int
+}
+
+func (f *fakeHealth) Allow(sourceURL string) bool { return !f.blocked[sourceURL] }
+
+func (f *fakeHealth) RecordSuccess(sourceURL string, courseCount int) { f.successes[sourceURL]++ }
+
+func (f *fakeHealth) RecordFailure(sourceURL string, err error) { f.failures[sourceURL]++ }
+
+type fakeStore struct {
+	postedAt  map[string]time.Time
+	added     []string
+	refreshed []string
+	sources   map[string]string // Source recorded for each course added
+	cursor    int
+}
+
+func (f *fakeStore) CoursePostedAt(url string) (time.Time, bool, error) {
+	postedAt, exists := f.postedAt[url]
+	return postedAt, exists, nil
+}
+
+func (f *fakeStore) AddCourse(course *database.Course) error {
+	f.added = append(f.added, course.URL)
+	if f.sources == nil {
+		f.sources = make(map[string]string)
+	}
+	f.sources[course.URL] = course.SourceURL
+	return nil
+}
+
+func (f *fakeStore) RefreshCourse(course *database.Course) error {
+	f.added = append(f.added, course.URL)
+	f.refreshed = append(f.refreshed, course.URL)
+	return nil
+}
+
+func (f *fakeStore) SourceCursor() (int, error) { return f.cursor, nil }
+
+func (f *fakeStore) SetSourceCursor(cursor int) error {
+	f.cursor = cursor
+	return nil
+}
+
+func (f *fakeStore) GetDuePendingCoupons(now time.Time, limit int) ([]database.PendingCoupon, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) ReschedulePendingCoupon(url string, attempts int, nextTry time.Time) error {
+	return nil
+}
+
+func (f *fakeStore) DeletePendingCoupon(url string) error { return nil }
+func (f *fakeStore) GiveUpPendingCoupon(url string) error { return nil }
+
+type fakeNotifier struct {
+	subscribed []string
+	posted     []string
+	postTimes  []time.Time
+	approvals  []string
+	postErrs   map[string]error
+}
+
+func (f *fakeNotifier) NotifyWishlistPriceDrops(courses []database.Course) {}
+
+func (f *fakeNotifier) NotifySubscribers(course *database.Course) {
+	f.subscribed = append(f.subscribed, course.URL)
+}
+
+func (f *fakeNotifier) PostCourse(course *database.Course) error {
+	if err := f.postErrs[course.URL]; err != nil {
+		return err
+	}
+	f.posted = append(f.posted, course.URL)
+	f.postTimes = append(f.postTimes, time.Now())
+	return nil
+}
+
+func (f *fakeNotifier) RequestApproval(course *database.Course) error {
+	f.approvals = append(f.approvals, course.URL)
+	return nil
+}
+
+const (
+	sourceA = "https://a.example/"
+	sourceB = "https://b.example/"
+)
+
+func testCourse(slug, title string, quality float64) database.Course {
+	return database.Course{
+		URL:          "https://www.udemy.com/course/" + slug + "/",
+		Title:        title,
+		Category:     "Development",
+		QualityScore: quality,
+	}
+}
+
+// scanFixture runs scanForCourses against fakes. It scrapes sourceA and
+// sourceB unless a test narrows cfg.Scraping.SourceURLs.
+type scanFixture struct {
+	cfg      *config.Config
+	source   CourseSource
+	health   *fakeHealth
+	store    *fakeStore
+	notifier *fakeNotifier
+	scanning atomic.Bool
+	logger   *logger.Logger
+}
+
+func newScanFixture(t *testing.T, courses map[string][]database.Course) *scanFixture {
+	t.Helper()
+	appLogger, err := logger.New("", "error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &scanFixture{
+		cfg:      &config.Config{},
+		source:   &fakeSource{courses: courses},
+		health:   &fakeHealth{successes: map[string]int{}, failures: map[string]int{}},
+		store:    &fakeStore{},
+		notifier: &fakeNotifier{},
+		logger:   appLogger,
+	}
+	f.cfg.Scraping.SourceURLs = []string{sourceA, sourceB}
+	f.cfg.Scraping.CouponRetryAttempts = 5
+	return f
+}
+
+func (f *scanFixture) scan(ctx context.Context) ScanResult {
+	return scanForCourses(ctx, &f.scanning, f.cfg, f.source, f.health, f.store, f.notifier, f.logger)
+}
+
+func TestScanForCourses(t *testing.T) {
+	python := testCourse("python", "Python Programming for Everyone", 80)
+	golang := testCourse("golang", "Go Concurrency in Practice", 70)
+	cooking := testCourse("cooking", "Italian Cooking at Home", 60)
+	crypto := testCourse("crypto", "Crypto Trading Secrets Revealed", 90)
+	poker := testCourse("poker", "Online POKER Strategy", 80)
+	odds := testCourse("odds", "Probability for Everyone", 80)
+	odds.Description = "Learn to beat the odds at online Gambling sites"
+	lowQuality := testCourse("low", "Spreadsheet Tricks and Shortcuts", 10)
+	atThreshold := testCourse("at", "Photography Lighting Fundamentals", 50)
+	yoga := testCourse("yoga", "Morning Yoga for Beginners", 60)
+	yoga.Category = "Health & Fitness"
+	// Titles differ, so only the exact URL match can collapse these
+	sharedA := testCourse("shared", "Kubernetes for Absolute Beginners", 70)
+	sharedB := testCourse("shared", "Watercolor Painting Masterclass", 90)
+	tracked := database.Course{
+		URL:          "https://click.linksynergy.com/deeplink?murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fdead%2F",
+		Title:        "Dead Tracking Link Course",
+		Category:     "Development",
+		QualityScore: 80,
+	}
+
+	tests := []struct {
+		name      string
+		courses   map[string][]database.Course
+		errs      map[string]error
+		blocked   map[string]bool
+		stored    map[string]time.Time
+		deadLinks map[string]bool
+		postErrs  map[string]error
+		configure func(cfg *config.Config)
+		running   bool
+
+		want         ScanResult
+		wantStored   []string // Courses added to the store, if checked
+		wantPosted   []string
+		wantQueued   []string
+		wantNotified []string // Subscribers DMed, if not just the courses posted
+	}{
+		{
+			name:       "posts new courses from every source",
+			courses:    map[string][]database.Course{sourceA: {python}, sourceB: {golang}},
+			want:       ScanResult{Found: 2, Deduplicated: 2, Stored: 2, Posted: 2},
+			wantPosted: []string{python.URL, golang.URL},
+		},
+		{
+			name:       "skips courses already stored",
+			courses:    map[string][]database.Course{sourceA: {python, golang}},
+			stored:     map[string]time.Time{python.URL: time.Now()},
+			want:       ScanResult{Found: 1, Deduplicated: 1, Stored: 1, Posted: 1},
+			wantPosted: []string{golang.URL},
+		},
+		{
+			name:       "collapses the same URL listed on two sources",
+			courses:    map[string][]database.Course{sourceA: {sharedA}, sourceB: {sharedB}},
+			want:       ScanResult{Found: 1, Deduplicated: 1, Stored: 1, Posted: 1},
+			wantStored: []string{sharedA.URL},
+			wantPosted: []string{sharedA.URL},
+		},
+		{
+			name:    "drops global excluded keywords in titles and descriptions",
+			courses: map[string][]database.Course{sourceA: {crypto, poker, odds, cooking}},
+			configure: func(cfg *config.Config) {
+				cfg.Filters.GlobalExcludedKeywords = []string{"crypto", "poker", "gambling"}
+			},
+			want:       ScanResult{Found: 1, Deduplicated: 1, Stored: 1, Posted: 1, Excluded: 3},
+			wantStored: []string{cooking.URL},
+			wantPosted: []string{cooking.URL},
+		},
+		{
+			name:    "stores but gates courses below the quality threshold",
+			courses: map[string][]database.Course{sourceA: {python, atThreshold, lowQuality}},
+			configure: func(cfg *config.Config) {
+				cfg.Scraping.MinPostQualityScore = 50
+			},
+			want:       ScanResult{Found: 3, Deduplicated: 3, Stored: 3, Posted: 2, Gated: 1},
+			wantPosted: []string{python.URL, atThreshold.URL},
+		},
+		{
+			name:    "gates by a category's own threshold, others by the global one",
+			courses: map[string][]database.Course{sourceA: {python, golang, yoga}},
+			configure: func(cfg *config.Config) {
+				cfg.Scraping.MinPostQualityScore = 50
+				cfg.Scraping.CategoryMinPostQualityScore = map[string]float64{"development": 75}
+			},
+			want:       ScanResult{Found: 3, Deduplicated: 3, Stored: 3, Posted: 2, Gated: 1},
+			wantPosted: []string{python.URL, yoga.URL},
+		},
+		{
+			name:    "queues courses for approval instead of posting",
+			courses: map[string][]database.Course{sourceA: {python}},
+			configure: func(cfg *config.Config) {
+				cfg.Telegram.ApprovalMode = true
+			},
+			want:       ScanResult{Found: 1, Deduplicated: 1, Stored: 1, Queued: 1},
+			wantQueued: []string{python.URL}, // No DMs until an admin approves it
+		},
+		{
+			name:       "drops dead tracking links",
+			courses:    map[string][]database.Course{sourceA: {tracked, python}},
+			deadLinks:  map[string]bool{tracked.URL: true},
+			want:       ScanResult{Found: 2, Deduplicated: 2, Stored: 1, Posted: 1, DeadLinks: 1},
+			wantPosted: []string{python.URL},
+		},
+		{
+			name:       "records source errors and carries on",
+			courses:    map[string][]database.Course{sourceB: {golang}},
+			errs:       map[string]error{sourceA: errors.New("boom")},
+			want:       ScanResult{Found: 1, Deduplicated: 1, Stored: 1, Posted: 1},
+			wantPosted: []string{golang.URL},
+		},
+		{
+			name:         "keeps going when a post fails",
+			courses:      map[string][]database.Course{sourceA: {python, golang}},
+			postErrs:     map[string]error{python.URL: errors.New("bad request")},
+			want:         ScanResult{Found: 2, Deduplicated: 2, Stored: 2, Posted: 1},
+			wantPosted:   []string{golang.URL},
+			wantNotified: []string{python.URL, golang.URL},
+		},
+		{
+			name:         "drops channel posts while channel posting is paused",
+			courses:      map[string][]database.Course{sourceA: {python}},
+			postErrs:     map[string]error{python.URL: telegram.ErrChannelUnavailable},
+			want:         ScanResult{Found: 1, Deduplicated: 1, Stored: 1},
+			wantPosted:   nil,
+			wantNotified: []string{python.URL},
+		},
+		{
+			name:       "skips sources the health tracker holds back",
+			courses:    map[string][]database.Course{sourceA: {python}, sourceB: {golang}},
+			blocked:    map[string]bool{sourceA: true},
+			want:       ScanResult{Found: 1, Deduplicated: 1, Stored: 1, Posted: 1},
+			wantPosted: []string{golang.URL},
+		},
+		{
+			name:    "skips a scan while another is running",
+			courses: map[string][]database.Course{sourceA: {python}},
+			running: true,
+			want:    ScanResult{Skipped: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newScanFixture(t, tt.courses)
+			f.source = &fakeSource{courses: tt.courses, errs: tt.errs, deadLinks: tt.deadLinks}
+			f.health.blocked = tt.blocked
+			f.store.postedAt = tt.stored
+			f.notifier.postErrs = tt.postErrs
+			if tt.configure != nil {
+				tt.configure(f.cfg)
+			}
+			f.scanning.Store(tt.running)
+
+			got := f.scan(context.Background())
+			health, notifier := f.health, f.notifier
+
+			if f.scanning.Load() != tt.running {
+				t.Errorf("scanning flag = %v after the scan, want %v", f.scanning.Load(), tt.running)
+			}
+			if got.Found != tt.want.Found || got.Deduplicated != tt.want.Deduplicated ||
+				got.Stored != tt.want.Stored || got.Posted != tt.want.Posted ||
+				got.Gated != tt.want.Gated || got.Queued != tt.want.Queued ||
+				got.Excluded != tt.want.Excluded || got.DeadLinks != tt.want.DeadLinks ||
+				got.Skipped != tt.want.Skipped {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+			if len(got.SourceErrors) != len(tt.errs) {
+				t.Errorf("source errors = %v, want %v", got.SourceErrors, tt.errs)
+			}
+			for sourceURL := range tt.errs {
+				if health.failures[sourceURL] != 1 {
+					t.Errorf("failures recorded for %s = %d, want 1", sourceURL, health.failures[sourceURL])
+				}
+			}
+			for _, sourceURL := range f.cfg.Scraping.SourceURLs {
+				scraped := health.successes[sourceURL] + health.failures[sourceURL]
+				if want := !tt.running && !tt.blocked[sourceURL]; (scraped == 1) != want {
+					t.Errorf("source %s scraped %d times, want scraped = %v", sourceURL, scraped, want)
+				}
+			}
+			if tt.wantStored != nil && !sameURLs(f.store.added, tt.wantStored) {
+				t.Errorf("stored %v, want %v", f.store.added, tt.wantStored)
+			}
+			if !sameURLs(notifier.posted, tt.wantPosted) {
+				t.Errorf("posted %v, want %v", notifier.posted, tt.wantPosted)
+			}
+			if !sameURLs(notifier.approvals, tt.wantQueued) {
+				t.Errorf("queued %v, want %v", notifier.approvals, tt.wantQueued)
+			}
+			wantNotified := tt.wantNotified
+			if wantNotified == nil {
+				wantNotified = tt.wantPosted
+			}
+			if !sameURLs(notifier.subscribed, wantNotified) {
+				t.Errorf("subscribers notified of %v, want %v", notifier.subscribed, wantNotified)
+			}
+		})
+	}
+}
+
+// sameURLs compares URL lists ignoring order, since posts follow quality order
+func sameURLs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, url := range got {
+		counts[url]++
+	}
+	for _, url := range want {
+		if counts[url] == 0 {
+			return false
+		}
+		counts[url]--
+	}
+	return true
+}
+
+// blockingSource holds every scrape until release is closed
+type blockingSource struct {
+	fakeSource
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingSource) ScrapeCoursesFromURL(ctx context.Context, sourceURL string) ([]database.Course, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return b.fakeSource.ScrapeCoursesFromURL(ctx, sourceURL)
+}
+
+func TestScanForCoursesSkipsConcurrentScan(t *testing.T) {
+	course := testCourse("go", "Go Concurrency Patterns", 70)
+	f := newScanFixture(t, nil)
+	f.cfg.Scraping.SourceURLs = []string{sourceA}
+	source := &blockingSource{
+		fakeSource: fakeSource{courses: map[string][]database.Course{sourceA: {course}}},
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	f.source = source
+
+	first := make(chan ScanResult, 1)
+	go func() {
+		first <- f.scan(context.Background())
+	}()
+	<-source.started
+
+	health := &fakeHealth{successes: map[string]int{}, failures: map[string]int{}}
+	second := scanForCourses(context.Background(), &f.scanning, f.cfg, source, health, f.store, &fakeNotifier{}, f.logger)
+	if !second.Skipped {
+		t.Errorf("second scan = %+v, want it skipped while the first runs", second)
+	}
+	if len(health.successes)+len(health.failures) != 0 {
+		t.Errorf("skipped scan touched sources: %v %v", health.successes, health.failures)
+	}
+
+	close(source.release)
+	if got := <-first; got.Skipped || got.Stored != 1 {
+		t.Errorf("first scan = %+v, want it to store the course", got)
+	}
+	if f.scanning.Load() {
+		t.Error("scanning flag still set after both scans")
+	}
+}
+
+func TestScanForCoursesResultTiming(t *testing.T) {
+	f := newScanFixture(t, map[string][]database.Course{sourceA: {testCourse("go", "Go in Practice", 70)}})
+
+	before := time.Now()
+	got := f.scan(context.Background())
+	if got.StartedAt.Before(before) || got.Duration <= 0 || got.Cancelled {
+		t.Errorf("result = %+v, want a start time, a duration and no cancellation", got)
+	}
+
+	// A scan cancelled before it reaches the sources reports it and scrapes nothing
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.health = &fakeHealth{successes: map[string]int{}, failures: map[string]int{}}
+	got = f.scan(ctx)
+	if !got.Cancelled || got.Found != 0 {
+		t.Errorf("cancelled scan = %+v, want Cancelled and nothing found", got)
+	}
+	if len(f.health.successes)+len(f.health.failures) != 0 {
+		t.Errorf("cancelled scan scraped sources: %v %v", f.health.successes, f.health.failures)
+	}
+}
+
+func TestSourcesForCycleRotates(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Scraping.SourceURLs = []string{"https://1.example/", "https://2.example/", "https://3.example/", "https://4.example/", "https://5.example/"}
+	cfg.Scraping.MaxSourcesPerCycle = 2
+	store := &fakeStore{}
+
+	want := [][]string{
+		{"https://1.example/", "https://2.example/"},
+		{"https://3.example/", "https://4.example/"},
+		{"https://5.example/", "https://1.example/"},
+		{"https://2.example/", "https://3.example/"},
+	}
+	for cycle, w := range want {
+		got := sourcesForCycle(cfg, store)
+		if strings.Join(got, " ") != strings.Join(w, " ") {
+			t.Errorf("cycle %d scraped %v, want %v", cycle+1, got, w)
+		}
+	}
+
+	// A cursor left over from a longer source list still lands in range
+	store.cursor = 12
+	if got := sourcesForCycle(cfg, store); got[0] != "https://3.example/" {
+		t.Errorf("stale cursor started at %s, want https://3.example/", got[0])
+	}
+
+	cfg.Scraping.MaxSourcesPerCycle = 0
+	if got := sourcesForCycle(cfg, store); len(got) != len(cfg.Scraping.SourceURLs) {
+		t.Errorf("without a cap scraped %d sources, want all %d", len(got), len(cfg.Scraping.SourceURLs))
+	}
+}
+
+func TestApplySourceTrust(t *testing.T) {
+	alt := 40.0
+	courses := []database.Course{{QualityScore: 50, QualityScoreAlt: &alt}, {QualityScore: 90}}
+
+	applySourceTrust(courses, 1.2)
+	if courses[0].QualityScore != 60 || *courses[0].QualityScoreAlt != 48 {
+		t.Errorf("scaled scores = %v / %v, want 60 / 48", courses[0].QualityScore, *courses[0].QualityScoreAlt)
+	}
+	if courses[1].QualityScore != 100 {
+		t.Errorf("scaled score = %v, want it capped at 100", courses[1].QualityScore)
+	}
+	if alt != 40 {
+		t.Error("the original alternative score was modified in place")
+	}
+
+	applySourceTrust(courses, 0)
+	if courses[0].QualityScore != 60 {
+		t.Error("a zero trust changed scores instead of being a no-op")
+	}
+}
+
+func TestScanForCoursesSourceTrustDecidesDedup(t *testing.T) {
+	fromTrusted := testCourse("docker", "Docker Mastery with Kubernetes", 70)
+	fromTrusted.URL += "?couponCode=CURATED"
+	fromOther := testCourse("docker", "Docker Mastery with Kubernetes", 80)
+	fromOther.URL += "?couponCode=SPAMMY"
+
+	for _, tt := range []struct {
+		trust float64
+		want  string
+	}{
+		{1, fromOther.URL},
+		{1.2, fromTrusted.URL},
+	} {
+		f := newScanFixture(t, map[string][]database.Course{sourceA: {fromTrusted}, sourceB: {fromOther}})
+		f.cfg.Scraping.SourceTrust = map[string]float64{sourceA: tt.trust}
+		f.scan(context.Background())
+
+		if !sameURLs(f.notifier.posted, []string{tt.want}) {
+			t.Errorf("trust %v posted %v, want %s", tt.trust, f.notifier.posted, tt.want)
+		}
+		wantSource := sourceA
+		if tt.want == fromOther.URL {
+			wantSource = sourceB
+		}
+		if got := f.store.sources[tt.want]; got != wantSource {
+			t.Errorf("trust %v stored source %q, want %q so /rescore can weight it", tt.trust, got, wantSource)
+		}
+	}
+}
+
+func TestPendingCouponDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 15 * time.Minute},
+		{2, 30 * time.Minute},
+		{4, 2 * time.Hour},
+		{7, 16 * time.Hour},
+		{8, 24 * time.Hour},
+		{50, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := pendingCouponDelay(tt.attempts); got != tt.want {
+			t.Errorf("pendingCouponDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPendingCoupons(t *testing.T) {
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	const (
+		recovers  = "https://coupons.example/go"
+		fails     = "https://coupons.example/rust"
+		exhausted = "https://coupons.example/java"
+	)
+	due := time.Now().Add(-time.Minute)
+	for _, p := range []database.PendingCoupon{
+		{URL: recovers, SourceURL: sourceA, NextTry: due},
+		{URL: fails, SourceURL: sourceA, NextTry: due},
+		{URL: exhausted, SourceURL: sourceA, Attempts: 4, NextTry: due},
+	} {
+		if err := db.AddPendingCoupon(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{}
+	cfg.Scraping.CouponRetryAttempts = 5
+	resolved := testCourse("go", "Go in Practice", 50)
+	source := &fakeSource{resolves: map[string]database.Course{recovers: resolved}}
+
+	got := retryPendingCoupons(context.Background(), cfg, source, db)
+	if len(got) != 1 || got[0].URL != resolved.URL {
+		t.Fatalf("resolved %+v, want only %s", got, resolved.URL)
+	}
+
+	// The failure waits out its backoff, the resolved and exhausted ones are done
+	if pending, err := db.GetDuePendingCoupons(time.Now(), 10); err != nil || len(pending) != 0 {
+		t.Errorf("due right after the retry: %+v, %v; want none", pending, err)
+	}
+	pending, err := db.GetDuePendingCoupons(time.Now().Add(pendingCouponBaseDelay+time.Minute), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].URL != fails || pending[0].Attempts != 1 {
+		t.Errorf("due after the backoff: %+v, want %s with 1 attempt", pending, fails)
+	}
+	if givenUp, err := db.IsCouponGivenUp(exhausted); err != nil || !givenUp {
+		t.Errorf("IsCouponGivenUp(%s) = %v, %v; want true", exhausted, givenUp, err)
+	}
+	if givenUp, _ := db.IsCouponGivenUp(fails); givenUp {
+		t.Errorf("gave up on %s after one failed retry", fails)
+	}
+}
+
+// adjacentRepeats counts neighbouring courses that share a category
+func adjacentRepeats(courses []database.Course) int {
+	repeats := 0
+	for i := 1; i < len(courses); i++ {
+		if strings.EqualFold(strings.TrimSpace(courses[i].Category), strings.TrimSpace(courses[i-1].Category)) {
+			repeats++
+		}
+	}
+	return repeats
+}
+
+func TestInterleaveCategories(t *testing.T) {
+	inCategory := func(slug, category string) database.Course {
+		course := testCourse(slug, slug, 50)
+		course.Category = category
+		return course
+	}
+
+	tests := []struct {
+		name        string
+		courses     []database.Course
+		wantRepeats int
+	}{
+		{"empty", nil, 0},
+		{"one category", []database.Course{
+			inCategory("w1", "Web"), inCategory("w2", "Web"),
+		}, 1},
+		{"alternatives for every post", []database.Course{
+			inCategory("w1", "Web"), inCategory("w2", "Web"), inCategory("w3", "Web"),
+			inCategory("d1", "Data"), inCategory("d2", "Data"), inCategory("g1", "Design"),
+		}, 0},
+		{"too few alternatives", []database.Course{
+			inCategory("w1", "Web"), inCategory("w2", "Web"), inCategory("w3", "Web"), inCategory("d1", "Data"),
+		}, 1},
+		{"categories differ only in case and spacing", []database.Course{
+			inCategory("w1", "Web Development"), inCategory("w2", "web development "), inCategory("d1", "Data"),
+		}, 0},
+	}
+	for _, tt := range tests {
+		got := interleaveCategories(tt.courses)
+		if len(got) != len(tt.courses) {
+			t.Errorf("%s: got %d courses, want all %d", tt.name, len(got), len(tt.courses))
+			continue
+		}
+		if repeats := adjacentRepeats(got); repeats != tt.wantRepeats {
+			t.Errorf("%s: %d adjacent same-category posts, want %d", tt.name, repeats, tt.wantRepeats)
+		}
+
+		// Courses within a category keep their order
+		position := make(map[string]int)
+		for i, course := range got {
+			position[course.URL] = i
+		}
+		for i := 1; i < len(tt.courses); i++ {
+			for j := 0; j < i; j++ {
+				same := strings.EqualFold(strings.TrimSpace(tt.courses[i].Category), strings.TrimSpace(tt.courses[j].Category))
+				if same && position[tt.courses[j].URL] > position[tt.courses[i].URL] {
+					t.Errorf("%s: %s posted before %s", tt.name, tt.courses[i].URL, tt.courses[j].URL)
+				}
+			}
+		}
+	}
+}
+
+func TestScanForCoursesInterleavesCategories(t *testing.T) {
+	// Dedup ranks by quality, so the scores fix the order before interleaving
+	web1 := testCourse("react", "React Hooks in Depth", 90)
+	web2 := testCourse("css", "Modern CSS Layouts with Grid", 80)
+	data := testCourse("pandas", "Data Wrangling using Pandas", 70)
+	data.Category = "Data Science"
+
+	for _, interleave := range []bool{false, true} {
+		f := newScanFixture(t, map[string][]database.Course{sourceA: {web1, web2, data}})
+		f.cfg.Scraping.InterleaveCategories = interleave
+		f.scan(context.Background())
+
+		want := []string{web1.URL, web2.URL, data.URL}
+		if interleave {
+			want = []string{web1.URL, data.URL, web2.URL}
+		}
+		if strings.Join(f.notifier.posted, " ") != strings.Join(want, " ") {
+			t.Errorf("interleave %v: posted %v, want %v", interleave, f.notifier.posted, want)
+		}
+	}
+}
+
+func TestScanForCoursesRejectsUnknownDomains(t *testing.T) {
+	udemy := testCourse("go-basics", "Go Basics for Backend Developers", 70)
+	tracked := testCourse("unused", "Kubernetes Operators Hands On", 70)
+	tracked.URL = "https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fk8s%2F"
+	foreign := testCourse("unused", "Photography Lighting Fundamentals", 70)
+	foreign.URL = "https://courses.evil.example/course/photo/"
+
+	f := newScanFixture(t, map[string][]database.Course{sourceA: {udemy, tracked, foreign}})
+	got := f.scan(context.Background())
+
+	if !sameURLs(f.store.added, []string{udemy.URL, tracked.URL}) {
+		t.Errorf("stored %v, want the Udemy and tracking links only", f.store.added)
+	}
+	if got.Rejected != 1 {
+		t.Errorf("result = %+v, want 1 rejected", got)
+	}
+}
+
+func TestScanForCoursesDedupLookback(t *testing.T) {
+	recent := testCourse("recent", "Recently Posted Course", 90)
+	old := testCourse("old", "Long Ago Posted Course", 80)
+	stored := map[string]time.Time{
+		recent.URL: time.Now().Add(-30*24*time.Hour + time.Minute),
+		old.URL:    time.Now().Add(-30*24*time.Hour - time.Minute),
+	}
+
+	tests := []struct {
+		name          string
+		lookbackDays  int
+		wantRefreshed []string
+	}{
+		{"only courses older than the window come round again", 30, []string{old.URL}},
+		{"without a window stored courses never do", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newScanFixture(t, map[string][]database.Course{sourceA: {recent, old}})
+			f.cfg.Scraping.DedupLookbackDays = tt.lookbackDays
+			f.store.postedAt = stored
+			f.scan(context.Background())
+
+			if !sameURLs(f.store.refreshed, tt.wantRefreshed) || !sameURLs(f.store.added, tt.wantRefreshed) {
+				t.Errorf("refreshed %v and added %v, want only %v refreshed", f.store.refreshed, f.store.added, tt.wantRefreshed)
+			}
+			if !sameURLs(f.notifier.posted, tt.wantRefreshed) {
+				t.Errorf("posted %v, want %v", f.notifier.posted, tt.wantRefreshed)
+			}
+		})
+	}
+}
+
+func TestPostQualityThreshold(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Scraping.MinPostQualityScore = 40
+	cfg.Scraping.CategoryMinPostQualityScore = map[string]float64{"Development": 70, "Lifestyle": 20}
+
+	tests := []struct {
+		category string
+		want     float64
+	}{
+		{"Development", 70},
+		{"development", 70},
+		{"Lifestyle", 20}, // Lower than the global threshold
+		{"Design", 40},
+		{"", 40},
+	}
+	for _, tt := range tests {
+		if got := postQualityThreshold(cfg, tt.category); got != tt.want {
+			t.Errorf("postQualityThreshold(%q) = %v, want %v", tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestScanForCoursesPacesPosts(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	f := newScanFixture(t, map[string][]database.Course{sourceA: {
+		testCourse("python", "Python Programming for Everyone", 90),
+		testCourse("golang", "Go Concurrency in Practice", 80),
+		testCourse("cooking", "Italian Cooking at Home", 70),
+	}})
+	f.cfg.Telegram.InterPostDelayMs = int(delay / time.Millisecond)
+
+	start := time.Now()
+	f.scan(context.Background())
+	finished := time.Now()
+
+	postTimes := f.notifier.postTimes
+	if len(postTimes) != 3 {
+		t.Fatalf("posted %d courses, want 3", len(postTimes))
+	}
+	if first := postTimes[0].Sub(start); first >= delay {
+		t.Errorf("first post waited %v, want it sent at once", first)
+	}
+	for i := 1; i < len(postTimes); i++ {
+		if gap := postTimes[i].Sub(postTimes[i-1]); gap < delay {
+			t.Errorf("gap before post %d = %v, want at least %v", i+1, gap, delay)
+		}
+	}
+	if after := finished.Sub(postTimes[2]); after >= delay {
+		t.Errorf("scan returned %v after the last post, want no delay after it", after)
+	}
+}
+
+func TestScanForCoursesShortExpiry(t *testing.T) {
+	soon := testCourse("soon", "Python Programming for Everyone", 90)
+	soon.URL += "?couponCode=LASTCALL"
+	soon.ExpiresAt = time.Now().Add(10 * time.Minute)
+	later := testCourse("later", "Go Concurrency in Practice", 80)
+	later.URL += "?couponCode=PLENTY"
+	later.ExpiresAt = time.Now().Add(2 * time.Hour)
+	unknown := testCourse("unknown", "Italian Cooking at Home", 70)
+
+	tests := []struct {
+		mode         string
+		wantPosted   []string
+		expiringSoon int
+	}{
+		{"skip", []string{later.URL, unknown.URL}, 1},
+		{"hurry", []string{soon.URL, later.URL, unknown.URL}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			f := newScanFixture(t, map[string][]database.Course{sourceA: {soon, later, unknown}})
+			f.cfg.Scraping.MinMinutesUntilExpiry = 60
+			f.cfg.Scraping.ShortExpiryMode = tt.mode
+			got := f.scan(context.Background())
+
+			if got.Stored != 3 || got.ExpiringSoon != tt.expiringSoon {
+				t.Errorf("stored %d and held back %d as expiring soon, want 3 and %d", got.Stored, got.ExpiringSoon, tt.expiringSoon)
+			}
+			if !sameURLs(f.notifier.posted, tt.wantPosted) {
+				t.Errorf("posted %v, want %v", f.notifier.posted, tt.wantPosted)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"strings"
+	"unicode"
+)
+
+// defaultCategoryKeywords maps keywords found in a course's title or
+// description to the category they suggest
+func defaultCategoryKeywords() map[string]string {
+	return map[string]string{
+		"python":      "Programming",
+		"javascript":  "Programming",
+		"java":        "Programming",
+		"golang":      "Programming",
+		"react":       "Web Development",
+		"angular":     "Web Development",
+		"vue":         "Web Development",
+		"html":        "Web Development",
+		"css":         "Web Development",
+		"data":        "Data Science",
+		"analytics":   "Data Science",
+		"machine":     "Data Science",
+		"ai":          "Artificial Intelligence",
+		"design":      "Design",
+		"photoshop":   "Design",
+		"marketing":   "Marketing",
+		"business":    "Business",
+		"excel":       "Business",
+		"photography": "Photography",
+		"music":       "Music",
+		"fitness":     "Health & Fitness",
+		"yoga":        "Health & Fitness",
+		"language":    "Language",
+		"english":     "Language",
+		"spanish":     "Language",
+		"finance":     "Finance",
+		"investing":   "Finance",
+		"crypto":      "Finance",
+	}
+}
+
+// SetCategoryKeywords adds keyword to category mappings on top of the
+// built-in ones; a configured keyword overrides a built-in one. Keywords may
+// span several words, e.g. "power bi".
+func (s *Scraper) SetCategoryKeywords(keywords map[string]string) {
+	merged := defaultCategoryKeywords()
+	for keyword, category := range keywords {
+		keyword = strings.Join(categoryWords(keyword), " ")
+		if keyword == "" || strings.TrimSpace(category) == "" {
+			continue
+		}
+		merged[keyword] = strings.TrimSpace(category)
+	}
+	s.categoryKeywords = merged
+}
+
+// inferCategory guesses a category from keywords in the title and
+// description. Keywords match whole words, and each keyword found counts once
+// per text; the category with the most hits wins, ties going alphabetically.
+func (s *Scraper) inferCategory(title, description string) string {
+	hits := make(map[string]int)
+	for _, text := range []string{title, description} {
+		padded := " " + strings.Join(categoryWords(text), " ") + " "
+		for keyword, category := range s.categoryKeywords {
+			if strings.Contains(padded, " "+keyword+" ") {
+				hits[category]++
+			}
+		}
+	}
+
+	best := ""
+	for category, count := range hits {
+		if count > hits[best] || (count == hits[best] && category < best) {
+			best = category
+		}
+	}
+	return best
+}
+
+// categoryWords lowercases text and splits it into words
+func categoryWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,52 @@
+package scraper
+
+import "testing"
+
+func TestInferCategory(t *testing.T) {
+	s := New("test", 0)
+
+	tests := []struct {
+		name               string
+		title, description string
+		want               string
+	}{
+		{"title keyword", "Python for Everybody", "", "Programming"},
+		{"description only", "The Complete 2024 Bootcamp", "Build dashboards with analytics and data", "Data Science"},
+		{"most hits wins", "Photoshop for Marketing", "Run marketing campaigns for your business", "Marketing"},
+		{"tie goes alphabetically", "Excel for Designers", "design spreadsheets", "Business"},
+		{"whole words only", "Email Etiquette", "Write emails that get read", ""},
+		{"no keywords", "Intro to Knitting", "Cast on your first scarf", ""},
+	}
+	for _, tt := range tests {
+		if got := s.inferCategory(tt.title, tt.description); got != tt.want {
+			t.Errorf("%s: inferCategory(%q, %q) = %q, want %q", tt.name, tt.title, tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestSetCategoryKeywords(t *testing.T) {
+	s := New("test", 0)
+	s.SetCategoryKeywords(map[string]string{
+		"Power BI": "Business Intelligence",
+		"python":   " Data Science ",
+		"knitting": "Crafts",
+		"":         "Ignored",
+		"blank":    "  ",
+	})
+
+	tests := []struct {
+		title, description string
+		want               string
+	}{
+		{"Power-BI Dashboards", "", "Business Intelligence"},
+		{"Python Basics", "", "Data Science"},
+		{"Intro to Knitting", "", "Crafts"},
+		{"Learn React", "", "Web Development"}, // Built-in keywords still apply
+		{"Blank Canvas", "", ""},
+	}
+	for _, tt := range tests {
+		if got := s.inferCategory(tt.title, tt.description); got != tt.want {
+			t.Errorf("inferCategory(%q, %q) = %q, want %q", tt.title, tt.description, got, tt.want)
+		}
+	}
+}
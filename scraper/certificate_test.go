@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestExtractCertificate(t *testing.T) {
+	fixture := func(name string) string {
+		page, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(page)
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name string
+		page string
+		want *bool
+	}{
+		{"includes list with a certificate", fixture("udemy_certificate.html"), &yes},
+		{"includes list without a certificate", fixture("udemy_no_certificate.html"), &no},
+		{"no includes list", fixture("udemy_course.html"), nil},
+		{"embedded course data", `<script>window.course = {"has_certificate": true};</script>`, &yes},
+		{"embedded data beats the page text", `<script>{"has_certificate" : false}</script><p>Certificate of completion</p>`, &no},
+	}
+	for _, tt := range tests {
+		got := extractCertificate(parseHTML(t, tt.page))
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%s: extractCertificate() = %s, want %s", tt.name, describeBool(got), describeBool(tt.want))
+		}
+	}
+}
+
+func TestEnrichCourseCertificate(t *testing.T) {
+	pages := map[string]string{
+		"/course/docker-for-developers/": "udemy_certificate.html",
+		"/course/docker-quick-start/":    "udemy_no_certificate.html",
+	}
+
+	s := New("test", 0)
+	s.SetUdemyEnrichment(true)
+	serveUdemy(t, s, func(w http.ResponseWriter, r *http.Request) {
+		name, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		page, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(page)
+	})
+
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{"docker-for-developers", "true"},
+		{"docker-quick-start", "false"},
+		{"unreachable", "unknown"},
+	}
+	for _, tt := range tests {
+		course := database.Course{URL: "https://www.udemy.com/course/" + tt.slug + "/", Title: "Docker"}
+		s.EnrichCourse(context.Background(), &course)
+		if got := describeBool(course.Certificate); got != tt.want {
+			t.Errorf("%s: certificate = %s, want %s", tt.slug, got, tt.want)
+		}
+	}
+}
+
+// describeBool renders an optional bool for test messages
+func describeBool(b *bool) string {
+	if b == nil {
+		return "unknown"
+	}
+	if *b {
+		return "true"
+	}
+	return "false"
+}
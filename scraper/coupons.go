@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// SetCouponFollowConcurrency sets how many coupon pages from one listing page
+// are followed at once. Requests still start no closer together than the
+// rate limit; following in parallel overlaps the time spent waiting for pages.
+func (s *Scraper) SetCouponFollowConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	s.couponConcurrency = n
+}
+
+// SetFollowCoupons sets, per source URL, whether coupon page links are
+// followed. Sources set to false only yield the direct Udemy links they list.
+func (s *Scraper) SetFollowCoupons(sources map[string]bool) {
+	s.followCouponSources = sources
+}
+
+// followsCoupons reports whether coupon links on the source are followed
+func (s *Scraper) followsCoupons(sourceURL string) bool {
+	follow, ok := s.followCouponSources[sourceURL]
+	return !ok || follow
+}
+
+// couponPageURL resolves a coupon link found on a source page to an absolute URL
+func couponPageURL(sourceURL, href string) string {
+	if !strings.HasPrefix(href, "/") {
+		return href
+	}
+	parsedSourceURL, _ := url.Parse(sourceURL)
+	return parsedSourceURL.Scheme + "://" + parsedSourceURL.Host + href
+}
+
+// couponResult is the outcome of following one coupon page
+type couponResult struct {
+	courseURL string
+	err       error
+}
+
+// followCoupons follows each coupon page, at most couponConcurrency at a
+// time, and returns the results keyed by coupon URL
+func (s *Scraper) followCoupons(ctx context.Context, couponURLs []string) map[string]couponResult {
+	results := make([]couponResult, len(couponURLs))
+	sem := make(chan struct{}, s.couponConcurrency)
+	var wg sync.WaitGroup
+
+	for i, couponURL := range couponURLs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, couponURL string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			courseURL, err := s.followCouponLink(ctx, couponURL)
+			results[i] = couponResult{courseURL: courseURL, err: err}
+		}(i, couponURL)
+	}
+	wg.Wait()
+
+	byURL := make(map[string]couponResult, len(couponURLs))
+	for i, couponURL := range couponURLs {
+		byURL[couponURL] = results[i]
+	}
+	return byURL
+}
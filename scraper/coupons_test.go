@@ -0,0 +1,172 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// couponServer serves aggregator pages by path; other paths are not found
+func couponServer(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// couponListing renders a listing that links to a coupon page
+func couponListing(path, title string) string {
+	return `<div class="card"><a href="` + path + `">` + title + `</a></div>`
+}
+
+func TestExtractCoursesRecordsCouponFollows(t *testing.T) {
+	server := couponServer(t, map[string]string{
+		"/": "<html><body>" +
+			couponListing("/coupon/go", "Go Programming Masterclass") +
+			couponListing("/coupon/topic", "Python Topic Collection Page") +
+			couponListing("/coupon/gone", "Rust Systems Programming") +
+			courseCard("direct", "Directly Linked Udemy Course") +
+			"</body></html>",
+		"/coupon/go":    `<a href="https://www.udemy.com/course/go-masterclass/?couponCode=FREE">Enroll</a>`,
+		"/coupon/topic": `<a href="https://www.udemy.com/topic/python/">Browse</a>`,
+	})
+
+	type follows struct{ attempts, resolved int }
+	var mu sync.Mutex
+	recorded := make(map[string]follows)
+
+	s := New("test", 0)
+	s.SetCouponFollowRecorder(func(sourceURL string, attempts, resolved int) {
+		mu.Lock()
+		defer mu.Unlock()
+		f := recorded[sourceURL]
+		recorded[sourceURL] = follows{f.attempts + attempts, f.resolved + resolved}
+	})
+
+	sourceURL := server.URL + "/"
+	courses, err := s.ScrapeCoursesFromURL(context.Background(), sourceURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 2 {
+		t.Errorf("scraped %d courses, want the resolved coupon and the direct link", len(courses))
+	}
+
+	// The direct link is not a coupon follow; the topic page doesn't count as resolved
+	if got := recorded[sourceURL]; got != (follows{3, 1}) {
+		t.Errorf("recorded %+v for %s, want 3 attempts and 1 resolved", got, sourceURL)
+	}
+}
+
+func TestFollowCouponsHonorsConcurrencyCap(t *testing.T) {
+	const (
+		coupons = 12
+		limit   = 3
+	)
+	topics := []string{"Kubernetes", "Photoshop", "Excel", "Guitar", "Spanish", "Blender",
+		"Swift", "Marketing", "Statistics", "Unity", "Drawing", "Linux"}
+
+	var listing strings.Builder
+	for i := 0; i < coupons; i++ {
+		listing.WriteString(couponListing(fmt.Sprintf("/coupon/%d", i), topics[i]+" Complete Beginner Guide"))
+	}
+
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/coupon/") {
+			w.Write([]byte("<html><body>" + listing.String() + "</body></html>"))
+			return
+		}
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		slug := strings.TrimPrefix(r.URL.Path, "/coupon/")
+		fmt.Fprintf(w, `<a href="https://www.udemy.com/course/course-%s/?couponCode=FREE">Enroll</a>`, slug)
+	}))
+	t.Cleanup(server.Close)
+
+	s := New("test", 0)
+	s.SetCouponFollowConcurrency(limit)
+	courses, err := s.ScrapeCoursesFromURL(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := peak.Load(); got > limit || got < 2 {
+		t.Errorf("followed at most %d coupon pages at once, want between 2 and %d", got, limit)
+	}
+	if len(courses) != coupons {
+		t.Fatalf("scraped %d courses, want %d", len(courses), coupons)
+	}
+	// Courses come out in listing order however the follows interleave
+	for i, course := range courses {
+		if want := fmt.Sprintf("/course/course-%d/", i); !strings.Contains(course.URL, want) {
+			t.Errorf("course %d is %s, want %s", i, course.URL, want)
+		}
+	}
+}
+
+func TestExtractCoursesSkipsCouponsWhenFollowingIsOff(t *testing.T) {
+	var couponRequests atomic.Int32
+	pages := map[string]string{
+		"/": "<html><body>" +
+			couponListing("/coupon/go", "Go Programming Masterclass") +
+			courseCard("direct", "Directly Linked Udemy Course") +
+			"</body></html>",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/coupon/") {
+			couponRequests.Add(1)
+			w.Write([]byte(`<a href="https://www.udemy.com/course/go-masterclass/">Enroll</a>`))
+			return
+		}
+		w.Write([]byte(pages[r.URL.Path]))
+	}))
+	t.Cleanup(server.Close)
+
+	sourceURL := server.URL + "/"
+	recorded := 0
+	s := New("test", 0)
+	s.SetCouponFollowRecorder(func(string, int, int) { recorded++ })
+
+	s.SetFollowCoupons(map[string]bool{sourceURL: false})
+	courses, err := s.ScrapeCoursesFromURL(context.Background(), sourceURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 1 || !strings.Contains(courses[0].URL, "/course/direct/") {
+		t.Errorf("scraped %v, want only the direct Udemy link", courses)
+	}
+	if got := couponRequests.Load(); got != 0 || recorded != 0 {
+		t.Errorf("followed %d coupon pages and recorded %d follows, want none", got, recorded)
+	}
+
+	// Sources not listed keep following coupon links
+	s.SetFollowCoupons(map[string]bool{"https://other.example/": false})
+	courses, err = s.ScrapeCoursesFromURL(context.Background(), sourceURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 2 || couponRequests.Load() != 1 {
+		t.Errorf("scraped %d courses with %d coupon requests, want 2 and 1", len(courses), couponRequests.Load())
+	}
+}
@@ -0,0 +1,49 @@
+package scraper
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	coursePathPattern            = regexp.MustCompile(`^/course/[A-Za-z0-9_%-]+/?$`)
+	dashboardRedirectPathPattern = regexp.MustCompile(`^/course-dashboard-redirect/?$`)
+)
+
+// SetAcceptDashboardRedirects controls whether /course-dashboard-redirect
+// links, which identify a course by ID rather than slug, count as courses
+func (s *Scraper) SetAcceptDashboardRedirects(accept bool) {
+	s.acceptDashboardRedirects = accept
+}
+
+// isCourseURL reports whether a resolved URL points at a Udemy course page
+// rather than a category, topic or other listing. Tracking links are judged
+// by the Udemy URL they wrap; ones that hide their destination are accepted.
+func (s *Scraper) isCourseURL(courseURL string) bool {
+	parsedURL, err := url.Parse(courseURL)
+	if err != nil {
+		return false
+	}
+
+	if murl := parsedURL.Query().Get("murl"); murl != "" {
+		inner, err := url.Parse(murl)
+		if err != nil || inner.Host == "" {
+			return false
+		}
+		parsedURL = inner
+	}
+
+	host := strings.ToLower(parsedURL.Hostname())
+	if host != "udemy.com" && !strings.HasSuffix(host, ".udemy.com") {
+		return true // Opaque tracking link; the destination cannot be checked
+	}
+
+	if coursePathPattern.MatchString(parsedURL.Path) {
+		return true
+	}
+
+	return s.acceptDashboardRedirects &&
+		dashboardRedirectPathPattern.MatchString(parsedURL.Path) &&
+		parsedURL.Query().Get("course_id") != ""
+}
@@ -0,0 +1,42 @@
+package scraper
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestIsCourseURL(t *testing.T) {
+	wrapped := "https://click.linksynergy.com/deeplink?murl=" +
+		url.QueryEscape("https://www.udemy.com/topic/python/")
+
+	tests := []struct {
+		url          string
+		course       bool
+		withRedirect bool // Result with dashboard redirects accepted
+	}{
+		{"https://www.udemy.com/course/go-basics/", true, true},
+		{"https://www.udemy.com/course/go-basics/?couponCode=FREE", true, true},
+		{"https://www.udemy.com/topic/python/", false, false},
+		{"https://www.udemy.com/courses/development/", false, false},
+		{"https://www.udemy.com/user/jane-doe/", false, false},
+		{"https://www.udemy.com/course/go-basics/learn/lecture/1", false, false},
+		{"https://www.udemy.com/course-dashboard-redirect/?course_id=12345", false, true},
+		{"https://www.udemy.com/course-dashboard-redirect/", false, false},
+		{wrapped, false, false},
+		{"https://trk.example/abc123", true, true},
+	}
+
+	s := New("test", 0)
+	for _, tt := range tests {
+		if got := s.isCourseURL(tt.url); got != tt.course {
+			t.Errorf("isCourseURL(%q) = %v, want %v", tt.url, got, tt.course)
+		}
+	}
+
+	s.SetAcceptDashboardRedirects(true)
+	for _, tt := range tests {
+		if got := s.isCourseURL(tt.url); got != tt.withRedirect {
+			t.Errorf("with redirects, isCourseURL(%q) = %v, want %v", tt.url, got, tt.withRedirect)
+		}
+	}
+}
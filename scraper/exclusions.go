@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// pathExclusions matches link paths that should not be followed. Plain
+// entries are substring matches; entries prefixed with "regex:" are
+// regular expressions.
+type pathExclusions struct {
+	substrings []string
+	patterns   []*regexp.Regexp
+}
+
+func newPathExclusions(entries []string) (*pathExclusions, error) {
+	ex := &pathExclusions{}
+	for _, entry := range entries {
+		if expr, ok := strings.CutPrefix(entry, "regex:"); ok {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid excluded path pattern %q: %w", entry, err)
+			}
+			ex.patterns = append(ex.patterns, re)
+			continue
+		}
+		if entry != "" {
+			ex.substrings = append(ex.substrings, entry)
+		}
+	}
+	return ex, nil
+}
+
+// matches reports whether the path of href is excluded
+func (ex *pathExclusions) matches(href string) bool {
+	path := href
+	if parsed, err := url.Parse(href); err == nil && parsed.Path != "" {
+		path = parsed.Path
+	}
+
+	for _, substring := range ex.substrings {
+		if strings.Contains(path, substring) {
+			return true
+		}
+	}
+	for _, re := range ex.patterns {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetExcludedPathPatterns replaces the list of link paths skipped during extraction
+func (s *Scraper) SetExcludedPathPatterns(entries []string) error {
+	ex, err := newPathExclusions(entries)
+	if err != nil {
+		return err
+	}
+	s.excludedPaths = ex
+	return nil
+}
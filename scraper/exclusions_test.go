@@ -0,0 +1,51 @@
+package scraper
+
+import "testing"
+
+func TestPathExclusions(t *testing.T) {
+	ex, err := newPathExclusions([]string{"/user/", "/tag/", "regex:^/go/[0-9]+$", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		href string
+		want bool
+	}{
+		{"https://www.udemy.com/user/jane-doe/", true},
+		{"/tag/python/", true},
+		{"https://source.example/go/123", true},
+		{"https://source.example/go/123/extra", false},
+		{"https://www.udemy.com/course/python-basics/", false},
+		{"https://www.udemy.com/course/python-basics/?ref=/user/", false},
+		{"/coupon/python-basics/", false},
+	}
+	for _, tt := range tests {
+		if got := ex.matches(tt.href); got != tt.want {
+			t.Errorf("matches(%q) = %v, want %v", tt.href, got, tt.want)
+		}
+	}
+}
+
+func TestPathExclusionsInvalidRegex(t *testing.T) {
+	if _, err := newPathExclusions([]string{"regex:("}); err == nil {
+		t.Error("an invalid regular expression was accepted")
+	}
+}
+
+func TestExtractCoursesSkipsExcludedPaths(t *testing.T) {
+	s := New("test", 0)
+	if err := s.SetExcludedPathPatterns([]string{"regex:^/course/sponsored-"}); err != nil {
+		t.Fatal(err)
+	}
+
+	page := "<html><body>" +
+		courseCard("sponsored-bootcamp", "Sponsored Bootcamp Course Listing") +
+		courseCard("kept", "Course That Should Be Kept") +
+		"</body></html>"
+
+	courses := extractFromHTML(t, s, page)
+	if len(courses) != 1 || courses[0].URL != "https://www.udemy.com/course/kept/" {
+		t.Errorf("got %+v, want only the course link", courses)
+	}
+}
@@ -0,0 +1,208 @@
+package scraper
+
+import (
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ExpirationParser extracts a coupon's expiry from a scraped listing. It
+// returns the zero time when the listing does not reveal an expiry.
+type ExpirationParser interface {
+	ParseExpiration(courseURL string, selection *goquery.Selection) time.Time
+}
+
+// CouponCodeExpirationParser reads dates embedded in the coupon code, such as
+// "22JULY2025" or "31122025". It is used for every source without a dedicated parser.
+type CouponCodeExpirationParser struct{}
+
+func (CouponCodeExpirationParser) ParseExpiration(courseURL string, selection *goquery.Selection) time.Time {
+	couponCode := couponCodeFromURL(courseURL)
+	if couponCode == "" {
+		return time.Time{}
+	}
+	return parseCouponExpiration(couponCode)
+}
+
+var numericDatePattern = regexp.MustCompile(`\d{4}[-_.]\d{2}[-_.]\d{2}|\d{2}[-_.]\d{2}[-_.]\d{4}|\d+`)
+
+// numericDateLayouts are tried in order for each run of digits in a coupon
+// code, separators removed. Year-first is unambiguous so it goes first, and
+// day-first is more common in coupon codes than month-first.
+var numericDateLayouts = []struct {
+	layout     string
+	monthsOnly bool // The date names a month, so it runs to the month's end
+}{
+	{"20060102", false}, // 20251231
+	{"02012006", false}, // 31122025
+	{"01022006", false}, // 12312025
+	{"0106", true},      // 1225 (MMYY)
+}
+
+// maxCouponYearsAhead rejects numeric dates too far out to be a coupon
+// expiry, which are more likely order numbers or other digits in the code
+const maxCouponYearsAhead = 2
+
+// parseNumericCouponDate finds a date written in digits in a coupon code,
+// returning the end of that day (or month, for MMYY) in UTC. Only real
+// calendar dates between now and maxCouponYearsAhead years out are accepted.
+func parseNumericCouponDate(couponCode string, now time.Time) (time.Time, bool) {
+	for _, token := range numericDatePattern.FindAllString(couponCode, -1) {
+		digits := strings.NewReplacer("-", "", "_", "", ".", "").Replace(token)
+
+		for _, format := range numericDateLayouts {
+			if len(digits) != len(format.layout) {
+				continue
+			}
+			// time.Parse rejects out-of-range months and days, like 31 February
+			date, err := time.Parse(format.layout, digits)
+			if err != nil {
+				continue
+			}
+
+			expiration := date.Add(24*time.Hour - time.Second)
+			if format.monthsOnly {
+				expiration = date.AddDate(0, 1, 0).Add(-time.Second)
+			}
+			if expiration.After(now) && date.Year() <= now.Year()+maxCouponYearsAhead {
+				return expiration, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// couponCodeFromURL returns the couponCode parameter of a Udemy URL, looking
+// inside affiliate murl wrappers first
+func couponCodeFromURL(courseURL string) string {
+	if !strings.Contains(courseURL, "couponCode=") {
+		return ""
+	}
+
+	parsedURL, err := url.Parse(courseURL)
+	if err != nil {
+		return ""
+	}
+
+	if murl := parsedURL.Query().Get("murl"); murl != "" {
+		// Decode the murl parameter to get the actual Udemy URL
+		decodedURL, err := url.QueryUnescape(murl)
+		if err != nil {
+			return ""
+		}
+		innerURL, err := url.Parse(decodedURL)
+		if err != nil {
+			return ""
+		}
+		return innerURL.Query().Get("couponCode")
+	}
+
+	return parsedURL.Query().Get("couponCode")
+}
+
+var relativeExpiryPattern = regexp.MustCompile(`(?i)(?:expires?\s+in|ends?\s+in)\s+(\d+)\s*(minute|min|hour|hr|day)s?|(\d+)\s*(minute|min|hour|hr|day)s?\s+left`)
+
+// RelativeTextExpirationParser reads countdown text such as "Expires in 2 days"
+// or "5 hours left" from the listing surrounding the course link.
+type RelativeTextExpirationParser struct{}
+
+func (RelativeTextExpirationParser) ParseExpiration(courseURL string, selection *goquery.Selection) time.Time {
+	if selection == nil {
+		return time.Time{}
+	}
+
+	text := selection.Closest("div, article, section").Text()
+	matches := relativeExpiryPattern.FindStringSubmatch(text)
+	if matches == nil {
+		return time.Time{}
+	}
+
+	amount, unit := matches[1], matches[2]
+	if amount == "" {
+		amount, unit = matches[3], matches[4]
+	}
+
+	n, err := strconv.Atoi(amount)
+	if err != nil || n <= 0 {
+		return time.Time{}
+	}
+
+	var step time.Duration
+	switch strings.ToLower(unit) {
+	case "minute", "min":
+		step = time.Minute
+	case "hour", "hr":
+		step = time.Hour
+	default:
+		step = 24 * time.Hour
+	}
+
+	return time.Now().Add(time.Duration(n) * step)
+}
+
+// defaultExpirationParsers maps aggregator hosts to their site-specific parsers
+func defaultExpirationParsers() map[string]ExpirationParser {
+	return map[string]ExpirationParser{
+		"courson.xyz": RelativeTextExpirationParser{},
+	}
+}
+
+// RegisterExpirationParser sets the expiry parser used for pages on host.
+// Sources without a registered parser use CouponCodeExpirationParser.
+func (s *Scraper) RegisterExpirationParser(host string, parser ExpirationParser) {
+	s.expirationParsers[normalizeHost(host)] = parser
+}
+
+// expirationParserFor returns the parser registered for the source's host, if any
+func (s *Scraper) expirationParserFor(sourceURL string) (ExpirationParser, bool) {
+	parsed, err := url.Parse(sourceURL)
+	if err != nil {
+		return nil, false
+	}
+	parser, ok := s.expirationParsers[normalizeHost(parsed.Hostname())]
+	return parser, ok
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+// SetMaxExpiryHorizon caps how far in the future a parsed expiry may be.
+// Year-only coupon codes parse as December 31, which is a guess rather than
+// a deadline, so later dates are pulled in to the horizon. 0 disables the cap.
+func (s *Scraper) SetMaxExpiryHorizon(horizon time.Duration) {
+	if horizon >= 0 {
+		s.maxExpiryHorizon = horizon
+	}
+}
+
+// clampExpiration limits expiration to the configured horizon from now
+func (s *Scraper) clampExpiration(expiration time.Time, courseURL string) time.Time {
+	if s.maxExpiryHorizon <= 0 {
+		return expiration
+	}
+	limit := time.Now().Add(s.maxExpiryHorizon)
+	if expiration.After(limit) {
+		log.Printf("Expiry %s for %s is beyond the %s horizon, clamping", expiration.Format("2006-01-02"), courseURL, s.maxExpiryHorizon)
+		return limit
+	}
+	return expiration
+}
+
+// Unknown expiry modes: guess an expiry from the title (7 days by default),
+// or leave it zero so posts say no expiry was detected
+const (
+	UnknownExpiryGuess = "guess"
+	UnknownExpiryZero  = "unknown"
+)
+
+// SetUnknownExpiryMode sets what courses get when no expiry can be parsed:
+// UnknownExpiryGuess (default) or UnknownExpiryZero
+func (s *Scraper) SetUnknownExpiryMode(mode string) {
+	s.unknownExpiry = mode
+}
@@ -0,0 +1,135 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// fixedExpirationParser reports the same expiry for every listing
+type fixedExpirationParser struct{ expiration time.Time }
+
+func (p fixedExpirationParser) ParseExpiration(courseURL string, selection *goquery.Selection) time.Time {
+	return p.expiration
+}
+
+func TestExpirationParserRegistry(t *testing.T) {
+	s := New("test", 0)
+	siteExpiry := time.Now().Add(3 * 24 * time.Hour).Truncate(time.Second)
+	s.RegisterExpirationParser("WWW.Coupons.Example", fixedExpirationParser{siteExpiry})
+	s.RegisterExpirationParser("silent.example", fixedExpirationParser{})
+
+	codeDate := time.Now().AddDate(0, 0, 10)
+	couponURL := "https://www.udemy.com/course/go/?couponCode=GO" + codeDate.Format("02012006")
+	codeExpiry := time.Date(codeDate.Year(), codeDate.Month(), codeDate.Day(), 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		sourceURL string
+		want      time.Time
+	}{
+		{"registered host", "https://coupons.example/free", siteExpiry},
+		{"registered parser finds nothing", "https://silent.example/", codeExpiry},
+		{"unregistered host", "https://other.example/", codeExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.extractExpirationDate(tt.sourceURL, couponURL, "Go Course", nil)
+			if !got.Equal(tt.want) {
+				t.Errorf("expiry = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeTextExpirationParser(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Duration
+	}{
+		{"Coupon expires in 2 days", 48 * time.Hour},
+		{"Hurry, 5 hours left!", 5 * time.Hour},
+		{"Ends in 30 min", 30 * time.Minute},
+		{"Enroll while it lasts", 0},
+	}
+	for _, tt := range tests {
+		doc := parseHTML(t, `<div class="card"><a href="https://www.udemy.com/course/go/">Go</a><span>`+tt.text+`</span></div>`)
+		got := RelativeTextExpirationParser{}.ParseExpiration("", doc.Find("a"))
+		if tt.want == 0 {
+			if !got.IsZero() {
+				t.Errorf("%q: expiry = %s, want none", tt.text, got)
+			}
+			continue
+		}
+		if remaining := time.Until(got); remaining > tt.want || remaining < tt.want-time.Minute {
+			t.Errorf("%q: expires in %s, want %s", tt.text, remaining, tt.want)
+		}
+	}
+}
+
+func TestExtractExpirationDateClampsHorizon(t *testing.T) {
+	s := New("test", 0)
+	now := time.Now()
+	far := now.Add(90 * 24 * time.Hour)
+	near := now.Add(10 * 24 * time.Hour)
+	s.RegisterExpirationParser("far.example", fixedExpirationParser{far})
+	s.RegisterExpirationParser("near.example", fixedExpirationParser{near})
+	yearOnlyURL := "https://www.udemy.com/course/go/?couponCode=GOFREE" + now.AddDate(1, 0, 0).Format("2006")
+
+	// within reports whether got is want, allowing for the time the call took
+	within := func(got, want time.Time) bool {
+		return !got.Before(want) && got.Sub(want) < time.Minute
+	}
+
+	s.SetMaxExpiryHorizon(30 * 24 * time.Hour)
+	if got := s.extractExpirationDate("https://far.example/", "https://www.udemy.com/course/go/", "Go", nil); !within(got, now.Add(30*24*time.Hour)) {
+		t.Errorf("far expiry = %s, want it clamped to 30 days out", got)
+	}
+	if got := s.extractExpirationDate("https://near.example/", "https://www.udemy.com/course/go/", "Go", nil); !got.Equal(near) {
+		t.Errorf("near expiry = %s, want it unchanged at %s", got, near)
+	}
+	if got := s.extractExpirationDate("https://other.example/", yearOnlyURL, "Go", nil); !within(got, now.Add(30*24*time.Hour)) {
+		t.Errorf("year-only coupon expiry = %s, want it clamped to 30 days out", got)
+	}
+
+	// A negative horizon is ignored; zero turns the cap off
+	s.SetMaxExpiryHorizon(-time.Hour)
+	if got := s.extractExpirationDate("https://far.example/", "https://www.udemy.com/course/go/", "Go", nil); !within(got, now.Add(30*24*time.Hour)) {
+		t.Errorf("far expiry after a negative horizon = %s, want the 30-day cap kept", got)
+	}
+	s.SetMaxExpiryHorizon(0)
+	if got := s.extractExpirationDate("https://far.example/", "https://www.udemy.com/course/go/", "Go", nil); !got.Equal(far) {
+		t.Errorf("far expiry without a cap = %s, want %s", got, far)
+	}
+}
+
+func TestExtractExpirationDateUnknownExpiry(t *testing.T) {
+	s := New("test", 0)
+	const noCoupon = "https://www.udemy.com/course/go/"
+
+	within := func(got time.Time, want time.Duration) bool {
+		d := time.Until(got)
+		return d > want-time.Minute && d <= want
+	}
+
+	// Guessing is the default
+	if got := s.extractExpirationDate("https://other.example/", noCoupon, "Go Basics", nil); !within(got, 7*24*time.Hour) {
+		t.Errorf("guessed expiry = %s, want 7 days out", got)
+	}
+	if got := s.extractExpirationDate("https://other.example/", noCoupon, "Limited Offer: Go Basics", nil); !within(got, 2*24*time.Hour) {
+		t.Errorf("guessed expiry for a limited offer = %s, want 2 days out", got)
+	}
+
+	s.SetUnknownExpiryMode(UnknownExpiryZero)
+	if got := s.extractExpirationDate("https://other.example/", noCoupon, "Limited Offer: Go Basics", nil); !got.IsZero() {
+		t.Errorf("unknown expiry = %s, want zero", got)
+	}
+
+	// A parsed expiry is used in either mode
+	codeDate := time.Now().AddDate(0, 0, 3)
+	couponURL := noCoupon + "?couponCode=GO" + codeDate.Format("02012006")
+	if got := s.extractExpirationDate("https://other.example/", couponURL, "Go Basics", nil); got.IsZero() || got.Day() != codeDate.Day() {
+		t.Errorf("coupon expiry = %s, want %s", got, codeDate.Format("2006-01-02"))
+	}
+}
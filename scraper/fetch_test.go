@@ -0,0 +1,132 @@
+package scraper
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchDocumentCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s := New("test", 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := s.fetchDocument(ctx, server.URL)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled fetch took %s", elapsed)
+	}
+}
+
+func TestFetchDocumentRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s := New("test", 0)
+	s.SetRequestTimeout(50 * time.Millisecond)
+
+	_, err := s.fetchDocument(context.Background(), server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestFetchDocumentOversizedBody(t *testing.T) {
+	const limit = 1024
+	var size int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := "<html><body>" + strings.Repeat("x", size) + "</body></html>"
+		w.Write([]byte(page[:size]))
+	}))
+	defer server.Close()
+
+	s := New("test", 0)
+	s.SetMaxResponseBytes(limit)
+
+	size = limit
+	if _, err := s.fetchDocument(context.Background(), server.URL); err != nil {
+		t.Errorf("body at the limit: %v", err)
+	}
+
+	size = limit + 1
+	_, err := s.fetchDocument(context.Background(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("oversized body: err = %v, want a size error", err)
+	}
+}
+
+func TestFetchDocumentDecodesCompressedBody(t *testing.T) {
+	page, err := os.ReadFile("testdata/listing.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compress := map[string]func(w io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+	}
+	for encoding, newWriter := range compress {
+		t.Run(encoding, func(t *testing.T) {
+			var gotAccept string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAccept = r.Header.Get("Accept-Encoding")
+				w.Header().Set("Content-Encoding", encoding)
+				writer := newWriter(w)
+				writer.Write(page)
+				writer.Close()
+			}))
+			defer server.Close()
+
+			doc, err := New("test", 0).fetchDocument(context.Background(), server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(gotAccept, "gzip") {
+				t.Errorf("Accept-Encoding = %q, want gzip offered", gotAccept)
+			}
+			if title := doc.Find("title").Text(); title != "Free Udemy Coupons" {
+				t.Errorf("decoded page title = %q", title)
+			}
+		})
+	}
+}
+
+func TestFetchDocumentRejectsUnsupportedEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte{0x1b, 0x2c, 0x00})
+	}))
+	defer server.Close()
+
+	if _, err := New("test", 0).fetchDocument(context.Background(), server.URL); err == nil {
+		t.Error("a Brotli body was accepted")
+	}
+}
@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit breaker states reported for a source
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// SourceState is the scrape health of a single source URL
+type SourceState struct {
+	URL         string
+	LastScrape  time.Time
+	LastCount   int
+	ZeroStreak  int // Consecutive successful scrapes that found no courses
+	ErrorStreak int // Consecutive failed scrapes
+	LastError   string
+	OpenUntil   time.Time // Source is skipped until this time once the breaker trips
+}
+
+// CircuitState describes whether the source is currently being scraped
+func (s SourceState) CircuitState(now time.Time, threshold int) string {
+	if threshold <= 0 || s.ErrorStreak < threshold {
+		return CircuitClosed
+	}
+	if now.Before(s.OpenUntil) {
+		return CircuitOpen
+	}
+	return CircuitHalfOpen
+}
+
+// SourceTracker records per-source scrape results and trips a circuit
+// breaker for sources that keep failing
+type SourceTracker struct {
+	mu        sync.Mutex
+	states    map[string]*SourceState
+	threshold int
+	cooldown  time.Duration
+}
+
+// NewSourceTracker creates a tracker that skips a source for cooldown after
+// threshold consecutive failures. A threshold of 0 disables the breaker.
+func NewSourceTracker(threshold int, cooldown time.Duration) *SourceTracker {
+	return &SourceTracker{
+		states:    make(map[string]*SourceState),
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (t *SourceTracker) state(sourceURL string) *SourceState {
+	state, exists := t.states[sourceURL]
+	if !exists {
+		state = &SourceState{URL: sourceURL}
+		t.states[sourceURL] = state
+	}
+	return state
+}
+
+// Allow reports whether the source should be scraped now
+func (t *SourceTracker) Allow(sourceURL string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.state(sourceURL).CircuitState(time.Now(), t.threshold) != CircuitOpen
+}
+
+// RecordSuccess records a completed scrape and closes the breaker
+func (t *SourceTracker) RecordSuccess(sourceURL string, courseCount int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.state(sourceURL)
+	state.LastScrape = time.Now()
+	state.LastCount = courseCount
+	state.ErrorStreak = 0
+	state.LastError = ""
+	state.OpenUntil = time.Time{}
+	if courseCount == 0 {
+		state.ZeroStreak++
+	} else {
+		state.ZeroStreak = 0
+	}
+}
+
+// RecordFailure records a failed scrape, tripping the breaker at the threshold
+func (t *SourceTracker) RecordFailure(sourceURL string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.state(sourceURL)
+	state.LastScrape = time.Now()
+	state.ErrorStreak++
+	if err != nil {
+		state.LastError = err.Error()
+	}
+	if t.threshold > 0 && state.ErrorStreak >= t.threshold {
+		state.OpenUntil = time.Now().Add(t.cooldown)
+	}
+}
+
+// Snapshot returns a copy of the state of each given source, in order
+func (t *SourceTracker) Snapshot(sourceURLs []string) []SourceState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	states := make([]SourceState, 0, len(sourceURLs))
+	for _, sourceURL := range sourceURLs {
+		states = append(states, *t.state(sourceURL))
+	}
+	return states
+}
+
+// Threshold returns the number of consecutive failures that trips the breaker
+func (t *SourceTracker) Threshold() int {
+	return t.threshold
+}
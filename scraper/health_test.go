@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSourceTrackerCircuitBreaker(t *testing.T) {
+	const source = "https://source.example/"
+	tracker := NewSourceTracker(2, time.Hour)
+
+	circuit := func() string {
+		return tracker.Snapshot([]string{source})[0].CircuitState(time.Now(), tracker.Threshold())
+	}
+	expireCooldown := func() {
+		tracker.states[source].OpenUntil = time.Now().Add(-time.Second)
+	}
+
+	tracker.RecordFailure(source, errors.New("timeout"))
+	if got := circuit(); got != CircuitClosed || !tracker.Allow(source) {
+		t.Fatalf("after 1 failure circuit is %s, want closed and scraped", got)
+	}
+
+	tracker.RecordFailure(source, errors.New("timeout"))
+	if got := circuit(); got != CircuitOpen || tracker.Allow(source) {
+		t.Fatalf("after 2 failures circuit is %s, want open and skipped", got)
+	}
+
+	// Once the cooldown passes one trial scrape is let through
+	expireCooldown()
+	if got := circuit(); got != CircuitHalfOpen || !tracker.Allow(source) {
+		t.Fatalf("after the cooldown circuit is %s, want half-open and scraped", got)
+	}
+
+	// A failed trial opens it again for another cooldown
+	tracker.RecordFailure(source, errors.New("still down"))
+	if got := circuit(); got != CircuitOpen || tracker.Allow(source) {
+		t.Fatalf("after a failed trial circuit is %s, want open", got)
+	}
+
+	expireCooldown()
+	tracker.RecordSuccess(source, 3)
+	state := tracker.Snapshot([]string{source})[0]
+	if got := circuit(); got != CircuitClosed || state.ErrorStreak != 0 || state.LastError != "" || !state.OpenUntil.IsZero() {
+		t.Errorf("after a successful trial circuit is %s with state %+v, want closed and reset", got, state)
+	}
+}
+
+func TestSourceStateCircuitState(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		state     SourceState
+		threshold int
+		want      string
+	}{
+		{"below the threshold", SourceState{ErrorStreak: 2}, 3, CircuitClosed},
+		{"tripped and cooling down", SourceState{ErrorStreak: 3, OpenUntil: now.Add(time.Minute)}, 3, CircuitOpen},
+		{"cooldown ends exactly now", SourceState{ErrorStreak: 3, OpenUntil: now}, 3, CircuitHalfOpen},
+		{"cooldown over", SourceState{ErrorStreak: 5, OpenUntil: now.Add(-time.Minute)}, 3, CircuitHalfOpen},
+		{"breaker disabled", SourceState{ErrorStreak: 50, OpenUntil: now.Add(time.Minute)}, 0, CircuitClosed},
+	}
+	for _, tt := range tests {
+		if got := tt.state.CircuitState(now, tt.threshold); got != tt.want {
+			t.Errorf("%s: circuit = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSourceTrackerDisabledBreaker(t *testing.T) {
+	const source = "https://source.example/"
+	tracker := NewSourceTracker(0, time.Hour)
+	for i := 0; i < 10; i++ {
+		tracker.RecordFailure(source, errors.New("timeout"))
+	}
+	if !tracker.Allow(source) {
+		t.Error("a threshold of 0 skipped a failing source, want the breaker off")
+	}
+}
+
+func TestSourceTrackerZeroStreak(t *testing.T) {
+	const source = "https://source.example/"
+	tracker := NewSourceTracker(3, time.Hour)
+	tracker.RecordSuccess(source, 0)
+	tracker.RecordSuccess(source, 0)
+	if state := tracker.Snapshot([]string{source})[0]; state.ZeroStreak != 2 || state.LastCount != 0 {
+		t.Errorf("state = %+v, want a zero streak of 2", state)
+	}
+	tracker.RecordSuccess(source, 4)
+	if state := tracker.Snapshot([]string{source})[0]; state.ZeroStreak != 0 || state.LastCount != 4 {
+		t.Errorf("state = %+v, want the zero streak reset by a scrape that found courses", state)
+	}
+}
@@ -0,0 +1,50 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNumericCouponDate(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	endOf := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 23, 59, 59, 0, time.UTC)
+	}
+
+	tests := []struct {
+		code string
+		want time.Time // Zero when no date should be found
+	}{
+		{"PYTHON20251231", endOf(2025, 12, 31)},
+		{"FREE31122025", endOf(2025, 12, 31)},
+		{"DEAL12312025", endOf(2025, 12, 31)}, // Month-first when day-first is impossible
+		{"GO-2025-12-31", endOf(2025, 12, 31)},
+		{"JS_31.12.2025", endOf(2025, 12, 31)},
+		{"SALE05072025", endOf(2025, 7, 5)}, // Day-first wins when both are valid
+		{"XMAS1225", endOf(2025, 12, 31)},   // MMYY runs to the end of the month
+		{"TODAY15062025", endOf(2025, 6, 15)},
+		{"FEB31022026", time.Time{}}, // 31 February
+		{"BAD13132025", time.Time{}}, // Month 13 either way
+		{"OLD01012020", time.Time{}}, // Already past
+		{"MAY0525", time.Time{}},     // Month already over
+		{"FAR31122099", time.Time{}}, // Too far ahead to be an expiry
+		{"ORDER123456", time.Time{}}, // Six digits match no layout
+		{"NODIGITS", time.Time{}},
+	}
+	for _, tt := range tests {
+		got, ok := parseNumericCouponDate(tt.code, now)
+		if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+			t.Errorf("parseNumericCouponDate(%q) = %v, %v; want %v", tt.code, got, ok, tt.want)
+		}
+	}
+}
+
+func TestParseCouponExpirationNumericDate(t *testing.T) {
+	date := time.Now().UTC().AddDate(0, 1, 0)
+	code := "FREE" + date.Format("02012006")
+
+	want := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, time.UTC)
+	if got := parseCouponExpiration(code); !got.Equal(want) {
+		t.Errorf("parseCouponExpiration(%q) = %v, want %v", code, got, want)
+	}
+}
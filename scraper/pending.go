@@ -0,0 +1,59 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+// SetCouponRetryHandler sets a function called with coupon links that could
+// not be followed, so they can be retried in a later scan instead of lost
+func (s *Scraper) SetCouponRetryHandler(handler func(database.PendingCoupon)) {
+	s.couponRetryHandler = handler
+}
+
+// SetCouponGivenUpCheck sets a function reporting coupon links whose retries
+// ran out; such links are skipped rather than followed and queued again
+func (s *Scraper) SetCouponGivenUpCheck(check func(couponURL string) bool) {
+	s.couponGivenUp = check
+}
+
+// deferCoupon hands a failed coupon link and its listing to the retry handler
+func (s *Scraper) deferCoupon(couponURL, sourceURL string, course database.Course) {
+	if s.couponRetryHandler == nil {
+		return
+	}
+	s.couponRetryHandler(database.PendingCoupon{
+		URL:       couponURL,
+		SourceURL: sourceURL,
+		Course:    course,
+		NextTry:   time.Now(),
+	})
+}
+
+// ResolvePendingCoupon retries following a queued coupon link and, on
+// success, returns the completed course ready for storage
+func (s *Scraper) ResolvePendingCoupon(ctx context.Context, pending database.PendingCoupon) (database.Course, error) {
+	courseURL, err := s.followCouponLink(ctx, pending.URL)
+	if ctx.Err() == nil && s.couponFollows != nil {
+		resolved := 0
+		if err == nil && s.isCourseURL(courseURL) {
+			resolved = 1
+		}
+		s.couponFollows(pending.SourceURL, 1, resolved)
+	}
+	if err != nil {
+		return database.Course{}, err
+	}
+
+	if !s.isCourseURL(courseURL) {
+		return database.Course{}, fmt.Errorf("coupon resolved to non-course link %s", courseURL)
+	}
+
+	course := pending.Course
+	course.URL = courseURL
+	course.ExpiresAt = s.extractExpirationDate(pending.SourceURL, courseURL, course.Title, nil)
+	return course, nil
+}
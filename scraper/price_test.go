@@ -0,0 +1,39 @@
+package scraper
+
+import "testing"
+
+func TestExtractCoursesOriginalPrice(t *testing.T) {
+	card := func(slug, title, prices string) string {
+		return `<div class="card"><a href="https://www.udemy.com/course/` + slug + `/">` + title + `</a>` + prices + `</div>`
+	}
+	page := "<html><body>" +
+		card("was-free", "Kubernetes for Developers", `<span class="price"><del>$89.99</del> Free</span>`) +
+		card("was-discounted", "Photoshop Masterclass Today", `<span class="price">$12.99</span><span class="was-price">$94.99</span>`) +
+		card("euro", "Spanish Grammar Essentials", `<div class="price"><s>49,99 €</s> 9,99 €</div>`) +
+		card("current-only", "Excel Formulas Explained", `<span class="price">$19.99</span>`) +
+		card("no-prices", "Guitar Chords for Beginners", ``) +
+		"</body></html>"
+
+	want := map[string]struct{ price, original string }{
+		"https://www.udemy.com/course/was-free/":       {"Free", "$89.99"},
+		"https://www.udemy.com/course/was-discounted/": {"$12.99", "$94.99"},
+		"https://www.udemy.com/course/euro/":           {"9,99 €", "49,99 €"},
+		"https://www.udemy.com/course/current-only/":   {"$19.99", ""},
+		"https://www.udemy.com/course/no-prices/":      {"Free", ""},
+	}
+
+	courses := extractFromHTML(t, New("test", 0), page)
+	if len(courses) != len(want) {
+		t.Fatalf("extracted %d courses, want %d", len(courses), len(want))
+	}
+	for _, course := range courses {
+		w, ok := want[course.URL]
+		if !ok {
+			t.Errorf("unexpected course %s", course.URL)
+			continue
+		}
+		if course.Price != w.price || course.OriginalPrice != w.original {
+			t.Errorf("%s: price %q was %q, want %q was %q", course.URL, course.Price, course.OriginalPrice, w.price, w.original)
+		}
+	}
+}
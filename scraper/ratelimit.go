@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces out the starts of all the scraper's requests by a fixed
+// interval, even when several requests are in flight at once. Requests that
+// are slow to answer then overlap, but none starts sooner than the interval
+// allows.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // Earliest start time of the next request
+}
+
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{interval: interval}
+}
+
+// wait reserves the next request slot and blocks until it starts
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(slot)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,161 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"udemy-course-notifier/database"
+)
+
+var enrollmentCountRegex = regexp.MustCompile(`(\d[\d,.\s]*)\s*students?`)
+
+// ReenrichCourse fills in details missing from a stored course (category,
+// rating, student count, captions and language) from its Udemy page. Details
+// the course already has are kept. It reports whether anything changed.
+func (s *Scraper) ReenrichCourse(ctx context.Context, course *database.Course) (bool, error) {
+	pageURL := udemyPageURL(course.URL)
+	if pageURL == "" {
+		return false, fmt.Errorf("not a Udemy course URL")
+	}
+
+	doc, err := s.fetchDocument(ctx, pageURL)
+	if err != nil {
+		return false, err
+	}
+
+	meta := extractUdemyMeta(doc)
+	meta.URL = pageURL
+	if s.udemyMetaCache != nil {
+		s.udemyMetaCache.PutUdemyMeta(meta)
+	}
+
+	changed := false
+	if course.Rating == 0 {
+		if rating := extractUdemyRating(doc); rating > 0 {
+			course.Rating = rating
+			changed = true
+		}
+	}
+	if course.StudentCount == 0 {
+		if count := extractUdemyStudentCount(doc); count > 0 {
+			course.StudentCount = count
+			changed = true
+		}
+	}
+	if course.Category == "" || course.Category == "General" {
+		// Categories come from the inferred set so /browse and filters see
+		// one taxonomy; Udemy's breadcrumb only helps when the text doesn't
+		category := s.inferCategory(course.Title, course.Description)
+		if category == "" {
+			category = s.inferCategory(extractUdemyTopics(doc), "")
+		}
+		if category != "" && category != course.Category {
+			course.Category = category
+			changed = true
+		}
+	}
+	if len(course.CaptionLanguages) == 0 && len(meta.CaptionLanguages) > 0 {
+		course.CaptionLanguages = meta.CaptionLanguages
+		changed = true
+	}
+	if course.Language == "" && meta.Language != "" {
+		course.Language = meta.Language
+		changed = true
+	}
+	if course.Certificate == nil && meta.Certificate != nil {
+		course.Certificate = meta.Certificate
+		changed = true
+	}
+
+	if changed {
+		course.QualityScore = s.calculateQualityScore(course.Rating, course.StudentCount, course.Title, course.Description)
+	}
+	return changed, nil
+}
+
+// extractUdemyRating reads the average rating from the page's JSON-LD
+// aggregateRating, falling back to the rating shown in the page header
+func extractUdemyRating(doc *goquery.Document) float64 {
+	var rating float64
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		var data interface{}
+		if err := json.Unmarshal([]byte(selection.Text()), &data); err != nil {
+			return true
+		}
+		rating = findRatingValue(data)
+		return rating == 0
+	})
+	if rating == 0 {
+		rating, _ = strconv.ParseFloat(strings.TrimSpace(doc.Find("[data-purpose='rating-number']").First().Text()), 64)
+	}
+	if rating < 0 || rating > 5 {
+		return 0
+	}
+	return rating
+}
+
+// findRatingValue returns the first aggregateRating.ratingValue in decoded
+// JSON-LD, which may be a number or a numeric string
+func findRatingValue(data interface{}) float64 {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		if aggregate, ok := value["aggregateRating"].(map[string]interface{}); ok {
+			switch rating := aggregate["ratingValue"].(type) {
+			case float64:
+				return rating
+			case string:
+				if parsed, err := strconv.ParseFloat(rating, 64); err == nil {
+					return parsed
+				}
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if found := findRatingValue(value[key]); found > 0 {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			if found := findRatingValue(child); found > 0 {
+				return found
+			}
+		}
+	}
+	return 0
+}
+
+// extractUdemyStudentCount reads the enrollment count, e.g. "12,345 students"
+func extractUdemyStudentCount(doc *goquery.Document) int {
+	text := doc.Find("[data-purpose='enrollment']").First().Text()
+	matches := enrollmentCountRegex.FindStringSubmatch(text)
+	if len(matches) < 2 {
+		return 0
+	}
+	digits := strings.NewReplacer(",", "", ".", "", " ", "").Replace(matches[1])
+	count, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// extractUdemyTopics reads the page's topic breadcrumb as one string, e.g.
+// "Development Data Science Python" from Development > Data Science > Python
+func extractUdemyTopics(doc *goquery.Document) string {
+	var topics []string
+	doc.Find("[data-purpose='topic-menu'] a, .topic-menu a").Each(func(i int, selection *goquery.Selection) {
+		topics = append(topics, strings.TrimSpace(selection.Text()))
+	})
+	return strings.Join(topics, " ")
+}
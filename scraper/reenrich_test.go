@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+const reenrichPage = `<html><head>
+<script type="application/ld+json">{"@type": "Course", "inLanguage": "en",
+ "aggregateRating": {"@type": "AggregateRating", "ratingValue": "4.4", "ratingCount": 812}}</script>
+</head><body>
+<div data-purpose="topic-menu"><a>Development</a><a>Programming Languages</a><a>Python</a></div>
+<div data-purpose="enrollment">12,345 students</div>
+</body></html>`
+
+func TestReenrichCourse(t *testing.T) {
+	s := New("test", 0)
+	serveUdemy(t, s, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(reenrichPage))
+	})
+
+	course := database.Course{URL: "https://www.udemy.com/course/intro/", Title: "An Introduction", Category: "General"}
+	changed, err := s.ReenrichCourse(context.Background(), &course)
+	if err != nil || !changed {
+		t.Fatalf("ReenrichCourse = %v, %v; want changes", changed, err)
+	}
+	if course.Rating != 4.4 || course.StudentCount != 12345 || course.Category != "Programming" || course.Language != "en" {
+		t.Errorf("re-enriched course = %+v", course)
+	}
+	if course.QualityScore == 0 {
+		t.Error("quality score not recomputed")
+	}
+
+	// Details the course already has are kept
+	course = database.Course{URL: "https://www.udemy.com/course/intro/", Title: "Excel Basics",
+		Category: "Business", Rating: 4.9, StudentCount: 10, Language: "de"}
+	if _, err := s.ReenrichCourse(context.Background(), &course); err != nil {
+		t.Fatal(err)
+	}
+	if course.Rating != 4.9 || course.StudentCount != 10 || course.Category != "Business" || course.Language != "de" {
+		t.Errorf("re-enrichment overwrote known details: %+v", course)
+	}
+
+	course = database.Course{URL: "https://courses.example/intro/"}
+	if _, err := s.ReenrichCourse(context.Background(), &course); err == nil {
+		t.Error("ReenrichCourse accepted a non-Udemy URL")
+	}
+}
@@ -0,0 +1,152 @@
+package scraper
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScoringWeights controls how much each signal contributes to a quality score
+type ScoringWeights struct {
+	RatingMultiplier      float64 `yaml:"rating_multiplier"`      // Points per rating star
+	StudentMultiplier     float64 `yaml:"student_multiplier"`     // Scales the student-count bonus
+	TitleBonus            float64 `yaml:"title_bonus"`            // Points per positive title keyword
+	TitlePenalty          float64 `yaml:"title_penalty"`          // Points removed per negative title keyword
+	DescriptionMultiplier float64 `yaml:"description_multiplier"` // Scales the description-length bonus
+	RecencyMultiplier     float64 `yaml:"recency_multiplier"`     // Scales the year-in-title bonus
+}
+
+// DefaultScoringWeights returns the weights of the original scoring formula
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
+		RatingMultiplier:      8,
+		StudentMultiplier:     1,
+		TitleBonus:            2,
+		TitlePenalty:          3,
+		DescriptionMultiplier: 1,
+		RecencyMultiplier:     1,
+	}
+}
+
+// QualityScorer computes a 0-100 quality score for a course
+type QualityScorer struct {
+	weights ScoringWeights
+}
+
+// NewQualityScorer creates a scorer using the given weights
+func NewQualityScorer(weights ScoringWeights) *QualityScorer {
+	return &QualityScorer{weights: weights}
+}
+
+// ScoreBreakdown lists the points each signal contributed to a quality score
+type ScoreBreakdown struct {
+	Rating       float64
+	Students     float64
+	TitleBonus   float64
+	TitlePenalty float64 // Points removed, as a positive number
+	Description  float64
+	Recency      float64
+	Total        float64 // Sum of the components, clamped to 0-100
+}
+
+// TrustedScore scales a quality score by the operator's trust in the source
+// the course came from, capped at 100. A trust of 0 or 1 leaves it as is.
+func TrustedScore(score, trust float64) float64 {
+	if trust <= 0 || trust == 1 {
+		return score
+	}
+	return math.Min(score*trust, 100)
+}
+
+// Score calculates the quality score from the signals available for a course
+func (q *QualityScorer) Score(rating float64, studentCount int, title, description string) float64 {
+	return q.ScoreBreakdown(rating, studentCount, title, description).Total
+}
+
+// ScoreBreakdown calculates the quality score along with each component
+func (q *QualityScorer) ScoreBreakdown(rating float64, studentCount int, title, description string) ScoreBreakdown {
+	var b ScoreBreakdown
+	w := q.weights
+
+	// Base score from rating (0-40 points)
+	if rating > 0 {
+		b.Rating = rating * w.RatingMultiplier // 5.0 rating = 40 points
+	}
+
+	// Student count bonus (0-30 points)
+	var studentPoints float64
+	switch {
+	case studentCount >= 1000:
+		studentPoints = 30
+	case studentCount >= 500:
+		studentPoints = 25
+	case studentCount >= 100:
+		studentPoints = 20
+	case studentCount >= 50:
+		studentPoints = 15
+	case studentCount >= 10:
+		studentPoints = 10
+	case studentCount > 0:
+		studentPoints = 5
+	}
+	b.Students = studentPoints * w.StudentMultiplier
+
+	// Title quality indicators (0-15 points)
+	titleLower := strings.ToLower(title)
+
+	// Positive indicators
+	positiveWords := []string{
+		"complete", "comprehensive", "masterclass", "bootcamp", "advanced",
+		"professional", "certification", "diploma", "course", "guide",
+		"tutorial", "training", "learn", "master", "expert",
+	}
+	for _, word := range positiveWords {
+		if strings.Contains(titleLower, word) {
+			b.TitleBonus += w.TitleBonus
+		}
+	}
+
+	// Negative indicators (reduce score)
+	negativeWords := []string{
+		"quick", "crash", "basics only", "intro", "beginner only",
+		"summary", "overview", "brief",
+	}
+	for _, word := range negativeWords {
+		if strings.Contains(titleLower, word) {
+			b.TitlePenalty += w.TitlePenalty
+		}
+	}
+
+	// Description quality (0-10 points)
+	if len(description) > 100 {
+		b.Description += 5 * w.DescriptionMultiplier // Detailed description
+	}
+	if len(description) > 200 {
+		b.Description += 3 * w.DescriptionMultiplier // Very detailed description
+	}
+
+	// Year/recency bonus (0-5 points)
+	currentYear := time.Now().Year()
+	for year := currentYear; year >= currentYear-2; year-- {
+		if strings.Contains(title, strconv.Itoa(year)) {
+			b.Recency = float64(3-(currentYear-year)) * w.RecencyMultiplier // 2025=3pts, 2024=2pts, 2023=1pt
+			break
+		}
+	}
+
+	score := b.Rating + b.Students + b.TitleBonus - b.TitlePenalty + b.Description + b.Recency
+
+	// Cap the score at 100
+	if score > 100 {
+		score = 100
+	}
+
+	// Ensure minimum score of 0
+	if score < 0 {
+		score = 0
+	}
+
+	b.Total = score
+	return b
+}
@@ -0,0 +1,42 @@
+package scraper
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScoreBreakdown(t *testing.T) {
+	scorer := NewQualityScorer(DefaultScoringWeights())
+	title := "Complete Go Bootcamp: Quick Start " + strconv.Itoa(time.Now().Year())
+	description := strings.Repeat("Build real services with Go. ", 5)
+
+	got := scorer.ScoreBreakdown(4.5, 600, title, description)
+	want := ScoreBreakdown{
+		Rating:       36,
+		Students:     25,
+		TitleBonus:   4, // "complete", "bootcamp"
+		TitlePenalty: 3, // "quick"
+		Description:  5,
+		Recency:      3,
+		Total:        70,
+	}
+	if got != want {
+		t.Errorf("ScoreBreakdown = %+v\nwant %+v", got, want)
+	}
+	if score := scorer.Score(4.5, 600, title, description); score != want.Total {
+		t.Errorf("Score = %v, want the breakdown total %v", score, want.Total)
+	}
+}
+
+func TestScoreBreakdownClamps(t *testing.T) {
+	weights := DefaultScoringWeights()
+	weights.RatingMultiplier = 30
+	if got := NewQualityScorer(weights).ScoreBreakdown(5, 5000, "Go", "").Total; got != 100 {
+		t.Errorf("total = %v, want it capped at 100", got)
+	}
+	if got := NewQualityScorer(DefaultScoringWeights()).ScoreBreakdown(0, 0, "Quick crash intro overview", "").Total; got != 0 {
+		t.Errorf("total = %v, want it floored at 0", got)
+	}
+}
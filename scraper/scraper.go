@@ -1,46 +1,149 @@
 package scraper
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 	"udemy-course-notifier/database"
 	"udemy-course-notifier/security"
 )
 
+// priceAmountRegex matches a price with its currency symbol, e.g. "$89.99" or "12,99 €"
+var priceAmountRegex = regexp.MustCompile(`([£$€¥₹₱₩₪₫₡₦₨₴₵₷₸₺₼₽¢]\s*\d+(?:[.,]\d{2})?|\d+(?:[.,]\d{2})?\s*[£$€¥₹₱₩₪₫₡₦₨₴₵₷₸₺₼₽])`)
+
+// strikethroughSelector finds "was" prices shown struck through
+const strikethroughSelector = ".original-price, .was-price, .strike, .strikethrough, del, s"
+
+const (
+	maxAnchorsPerPage      = 500    // Cap on candidate links processed per page
+	maxContainerTextLength = 100000 // Skip links whose surrounding text is unreasonably large
+)
+
 type Scraper struct {
-	client    *http.Client
-	userAgent string
-	rateLimit time.Duration
+	client         *http.Client
+	userAgent      string
+	requestTimeout time.Duration
+	scorer         *QualityScorer
+	altScorer      *QualityScorer // Optional scorer evaluated side-by-side for A/B comparison
+	excludedPaths  *pathExclusions
+	enrichFromUdemy bool
+	maxBodyBytes   int64
+	expirationParsers map[string]ExpirationParser // Keyed by source host
+	acceptDashboardRedirects bool
+	couponRetryHandler func(database.PendingCoupon) // Receives coupon links that failed to resolve
+	couponGivenUp  func(couponURL string) bool // Optional; reports links whose retries ran out
+	couponFollows  func(sourceURL string, attempts, resolved int) // Optional; receives coupon follow counts
+	limiter        *rateLimiter
+	couponConcurrency int // Coupon pages followed at once per listing page
+	followCouponSources map[string]bool // Per source URL; sources not listed follow coupon links
+	categoryKeywords map[string]string // Keyword to category, built-in plus configured
+	udemyMetaCache UdemyMetaCache // Optional; avoids refetching Udemy pages
+	udemyMetaTTL   time.Duration
+	maxExpiryHorizon time.Duration // Parsed expiries further out than this are clamped; 0 disables
+	unknownExpiry  string // UnknownExpiryGuess or UnknownExpiryZero
+	verifyTrackingLinks bool // Drop tracking links that no longer reach a course page
 }
 
 func New(userAgent string, rateLimitSeconds int) *Scraper {
 	return &Scraper{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		userAgent: userAgent,
-		rateLimit: time.Duration(rateLimitSeconds) * time.Second,
+		// Requests are bounded by their context, see requestTimeout
+		client:         &http.Client{},
+		userAgent:      userAgent,
+		requestTimeout: 20 * time.Second,
+		maxBodyBytes:   5 << 20,
+		scorer:         NewQualityScorer(DefaultScoringWeights()),
+		excludedPaths:  &pathExclusions{}, // Set from config, see SetExcludedPathPatterns
+		expirationParsers: defaultExpirationParsers(),
+		limiter:        newRateLimiter(time.Duration(rateLimitSeconds) * time.Second),
+		couponConcurrency: 4,
+		categoryKeywords: defaultCategoryKeywords(),
+		maxExpiryHorizon: 30 * 24 * time.Hour,
+		unknownExpiry:  UnknownExpiryGuess,
+	}
+}
+
+// SetScoring configures the quality scorer. When alt is non-nil every course
+// is also scored with the alternative weights for comparison.
+func (s *Scraper) SetScoring(weights ScoringWeights, alt *ScoringWeights) {
+	s.scorer = NewQualityScorer(weights)
+	s.altScorer = nil
+	if alt != nil {
+		s.altScorer = NewQualityScorer(*alt)
+	}
+}
+
+// SetRequestTimeout bounds how long a single page fetch may take
+func (s *Scraper) SetRequestTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.requestTimeout = timeout
 	}
 }
 
-func (s *Scraper) ScrapeCoursesFromURL(sourceURL string) ([]database.Course, error) {
-	time.Sleep(s.rateLimit) // Rate limiting
+// SetMaxResponseBytes caps how much of a response body is read before the
+// page is rejected, protecting against oversized or malicious pages
+func (s *Scraper) SetMaxResponseBytes(maxBytes int64) {
+	if maxBytes > 0 {
+		s.maxBodyBytes = maxBytes
+	}
+}
 
-	req, err := http.NewRequest("GET", sourceURL, nil)
+// SetCouponFollowRecorder sets a function called with how many coupon links
+// were followed for a source and how many of them resolved to a course
+func (s *Scraper) SetCouponFollowRecorder(record func(sourceURL string, attempts, resolved int)) {
+	s.couponFollows = record
+}
+
+func (s *Scraper) ScrapeCoursesFromURL(ctx context.Context, sourceURL string) ([]database.Course, error) {
+	var doc *goquery.Document
+	var err error
+	if strings.HasPrefix(sourceURL, "file://") {
+		doc, err = s.loadFixture(sourceURL)
+	} else {
+		doc, err = s.fetchDocument(ctx, sourceURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.extractCourses(ctx, doc, sourceURL)
+}
+
+// fetchDocument waits for the rate limit, then fetches and parses an HTML page.
+// The request is cancelled when ctx is done or the per-request timeout elapses.
+func (s *Scraper) fetchDocument(ctx context.Context, pageURL string) (*goquery.Document, error) {
+	// Rate limiting, shared by concurrent fetches
+	if err := s.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, s.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, "GET", pageURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	
 	req.Header.Set("User-Agent", s.userAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	// Setting this ourselves disables Go's transparent decompression, so the
+	// body is decoded below; it also keeps sources from picking br or others
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -52,47 +155,198 @@ func (s *Scraper) ScrapeCoursesFromURL(sourceURL string) ([]database.Course, err
 		return nil, fmt.Errorf("received status code: %d", resp.StatusCode)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	reader, err := decodedBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Read one byte past the limit so truncation can be detected. The limit
+	// applies after decompression so small compressed bodies cannot balloon.
+	body, err := io.ReadAll(io.LimitReader(reader, s.maxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > s.maxBodyBytes {
+		return nil, fmt.Errorf("failed to parse HTML: response exceeds %d bytes", s.maxBodyBytes)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	return s.extractCourses(doc, sourceURL)
+	return doc, nil
+}
+
+// decodedBody returns the response body, decompressing it according to its
+// Content-Encoding
+func decodedBody(resp *http.Response) (io.ReadCloser, error) {
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return io.NopCloser(resp.Body), nil
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress response: %w", err)
+		}
+		return reader, nil
+	case "deflate":
+		reader, err := zlib.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress response: %w", err)
+		}
+		return reader, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
 }
 
-func (s *Scraper) extractCourses(doc *goquery.Document, sourceURL string) ([]database.Course, error) {
+// loadFixture parses a local HTML file given as a file:// source URL
+func (s *Scraper) loadFixture(sourceURL string) (*goquery.Document, error) {
+	path, err := security.FixturePath(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture: %w", err)
+	}
+	defer file.Close()
+
+	body, err := io.ReadAll(io.LimitReader(file, s.maxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if int64(len(body)) > s.maxBodyBytes {
+		return nil, fmt.Errorf("failed to parse HTML: fixture exceeds %d bytes", s.maxBodyBytes)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return doc, nil
+}
+
+func (s *Scraper) extractCourses(ctx context.Context, doc *goquery.Document, sourceURL string) ([]database.Course, error) {
 	var courses []database.Course
 	count := 0
 	
 	// This is a generic scraper - specific sites may need custom selectors
 	// Look for both direct Udemy links and coupon page links
 	log.Printf("Scanning %s for course links...", sourceURL)
-	doc.Find("a[href*='udemy.com'], a[href*='/coupon/']").Each(func(i int, selection *goquery.Selection) {
-		if count >= security.LimitCourses(1000) {
-			return // Stop processing if we hit the limit
+	links := doc.Find("a[href*='udemy.com'], a[href*='/coupon/']")
+
+	// Some sources already list the direct Udemy link, so their coupon pages
+	// are skipped rather than followed
+	if !s.followsCoupons(sourceURL) {
+		direct := links.FilterFunction(func(i int, selection *goquery.Selection) bool {
+			return !strings.Contains(selection.AttrOr("href", ""), "/coupon/")
+		})
+		if skipped := links.Length() - direct.Length(); skipped > 0 {
+			log.Printf("Skipped %d coupon links on %s (coupon following is off for this source)", skipped, sourceURL)
 		}
+		links = direct
+	}
+
+	if links.Length() > maxAnchorsPerPage {
+		log.Printf("Page %s has %d candidate links, processing only the first %d", sourceURL, links.Length(), maxAnchorsPerPage)
+		links = links.Slice(0, maxAnchorsPerPage)
+	}
 
+	// Drop links that won't be used before following any coupon pages
+	oversized := 0
+	excluded := 0
+	links = links.FilterFunction(func(i int, selection *goquery.Selection) bool {
 		href, exists := selection.Attr("href")
 		if !exists {
+			return false
+		}
+
+		// Skip author profiles, tag pages and similar non-course links
+		if s.excludedPaths.matches(href) {
+			excluded++
+			return false
+		}
+
+		// Malformed pages can wrap a link in a huge text node; skip it rather
+		// than scanning megabytes for ratings and student counts
+		if textLongerThan(selection.Closest("div, article, section"), maxContainerTextLength) {
+			oversized++
+			return false
+		}
+		return true
+	})
+	if limit := security.LimitCourses(1000); links.Length() > limit {
+		links = links.Slice(0, limit)
+	}
+
+	// Follow the remaining coupon links up front, several at a time. Links
+	// given up on after repeated failures are left alone.
+	var couponURLs []string
+	seenCoupons := make(map[string]bool)
+	givenUp := make(map[string]bool)
+	links.Each(func(i int, selection *goquery.Selection) {
+		href := selection.AttrOr("href", "")
+		if !strings.Contains(href, "/coupon/") {
+			return
+		}
+		fullURL := couponPageURL(sourceURL, href)
+		if seenCoupons[fullURL] {
 			return
 		}
+		seenCoupons[fullURL] = true
+		if s.couponGivenUp != nil && s.couponGivenUp(fullURL) {
+			givenUp[fullURL] = true
+			return
+		}
+		couponURLs = append(couponURLs, fullURL)
+	})
+	coupons := s.followCoupons(ctx, couponURLs)
+
+	nonCourse := 0
+	couponAttempts := 0
+	couponResolved := 0
+	links.Each(func(i int, selection *goquery.Selection) {
+		if count >= security.LimitCourses(1000) {
+			return // Stop processing if we hit the limit
+		}
+
+		if ctx.Err() != nil {
+			return // Scan is shutting down
+		}
+
+		href := selection.AttrOr("href", "")
 
 		var courseURL string
+		var pendingCouponURL string // Set when the coupon link should be retried later
 		var err error
 
 		// Handle coupon page links vs direct Udemy links
 		if strings.Contains(href, "/coupon/") {
-			// This is a coupon page link, follow it to get the Udemy URL
-			fullURL := href
-			if strings.HasPrefix(href, "/") {
-				parsedSourceURL, _ := url.Parse(sourceURL)
-				fullURL = parsedSourceURL.Scheme + "://" + parsedSourceURL.Host + href
+			// This is a coupon page link, already followed to get the Udemy URL
+			fullURL := couponPageURL(sourceURL, href)
+			if givenUp[fullURL] {
+				return
+			}
+			coupon := coupons[fullURL]
+			courseURL, err = coupon.courseURL, coupon.err
+			if ctx.Err() == nil {
+				resolved := err == nil && s.isCourseURL(courseURL)
+				couponAttempts++
+				if resolved {
+					couponResolved++
+				}
 			}
-			
-			courseURL, err = s.followCouponLink(fullURL)
 			if err != nil {
 				log.Printf("Failed to follow coupon link %s: %v", fullURL, err)
-				return // Skip if we can't get the Udemy URL
+				if ctx.Err() != nil || s.couponRetryHandler == nil {
+					return // Skip if we can't get the Udemy URL
+				}
+				pendingCouponURL = fullURL
 			}
 		} else {
 			// Validate URL before processing
@@ -107,21 +361,27 @@ func (s *Scraper) extractCourses(doc *goquery.Document, sourceURL string) ([]dat
 			}
 		}
 
+		// Coupon pages sometimes resolve to category or topic pages
+		if pendingCouponURL == "" && !s.isCourseURL(courseURL) {
+			log.Printf("Skipping non-course Udemy link %s", courseURL)
+			nonCourse++
+			return
+		}
+
 		title := strings.TrimSpace(selection.Text())
 		if title == "" {
 			// Try to find title in parent elements
 			title = strings.TrimSpace(selection.Parent().Text())
 		}
 
-		if title == "" || len(title) < 10 { // Skip if no meaningful title
+		// Count characters, not bytes, so CJK and emoji titles are measured fairly
+		if title == "" || utf8.RuneCountInString(title) < 10 { // Skip if no meaningful title
 			return
 		}
 
 		// Sanitize and validate title length
 		title = security.SanitizeString(title)
-		if len(title) > 200 { // Reasonable title length limit
-			title = title[:200]
-		}
+		title = security.TruncateRunes(title, 200) // Reasonable title length limit
 
 		// Extract basic course info
 		rating := s.extractRating(selection)
@@ -129,24 +389,56 @@ func (s *Scraper) extractCourses(doc *goquery.Document, sourceURL string) ([]dat
 		description := security.SanitizeString(s.extractDescription(selection))
 		price := security.SanitizeString(s.extractPrice(selection))
 		discount := s.extractDiscount(selection, price)
+		originalPrice := security.SanitizeString(s.extractOriginalPrice(selection))
 		
 		course := database.Course{
-			URL:          courseURL,
-			Title:        title,
-			Description:  description,
-			Category:     security.SanitizeString(s.extractCategory(selection)),
-			Rating:       rating,
-			Price:        price,
-			Discount:     discount,
-			ExpiresAt:    s.extractExpirationDate(courseURL, title),
-			StudentCount: studentCount,
-			QualityScore: s.calculateQualityScore(rating, studentCount, title, description),
+			URL:           courseURL,
+			Title:         title,
+			Description:   description,
+			Category:      security.SanitizeString(s.extractCategory(selection, description)),
+			Rating:        rating,
+			Price:         price,
+			Discount:      discount,
+			OriginalPrice: originalPrice,
+			ExpiresAt:     s.extractExpirationDate(sourceURL, courseURL, title, selection),
+			StudentCount:  studentCount,
+			QualityScore:  s.calculateQualityScore(rating, studentCount, title, description),
+		}
+
+		if s.altScorer != nil {
+			altScore := s.altScorer.Score(rating, studentCount, title, description)
+			course.QualityScoreAlt = &altScore
+		}
+
+		// Keep the listing so the coupon can be followed again next cycle
+		if pendingCouponURL != "" {
+			s.deferCoupon(pendingCouponURL, sourceURL, course)
+			return
 		}
 
 		courses = append(courses, course)
 		count++
 	})
 
+	if excluded > 0 {
+		log.Printf("Skipped %d links on %s matching excluded path patterns", excluded, sourceURL)
+	}
+
+	if oversized > 0 {
+		log.Printf("Skipped %d links on %s with oversized container text", oversized, sourceURL)
+	}
+
+	if nonCourse > 0 {
+		log.Printf("Skipped %d links on %s that did not resolve to a course page", nonCourse, sourceURL)
+	}
+
+	if couponAttempts > 0 {
+		log.Printf("Resolved %d of %d coupon links on %s", couponResolved, couponAttempts, sourceURL)
+		if s.couponFollows != nil {
+			s.couponFollows(sourceURL, couponAttempts, couponResolved)
+		}
+	}
+
 	return courses, nil
 }
 
@@ -162,9 +454,7 @@ func (s *Scraper) cleanUdemyURL(rawURL string) (string, error) {
 	}
 
 	// If it's a tracking URL (like linksynergy), preserve it completely
-	if strings.Contains(parsedURL.Host, "linksynergy.com") || 
-	   strings.Contains(parsedURL.Host, "click.") ||
-	   strings.Contains(rawURL, "murl=") {
+	if isTrackingURL(rawURL) {
 		return rawURL, nil // Keep tracking URLs intact
 	}
 
@@ -198,7 +488,7 @@ func (s *Scraper) extractDescription(selection *goquery.Selection) string {
 	return strings.TrimSpace(desc)
 }
 
-func (s *Scraper) extractCategory(selection *goquery.Selection) string {
+func (s *Scraper) extractCategory(selection *goquery.Selection, description string) string {
 	// Look for category information in various places
 	var category string
 	
@@ -213,10 +503,9 @@ func (s *Scraper) extractCategory(selection *goquery.Selection) string {
 		}
 	}
 	
-	// If still no category, try to infer from title
+	// If still no category, try to infer from the title and description
 	if category == "" {
-		title := strings.ToLower(selection.Text())
-		category = s.inferCategoryFromTitle(title)
+		category = s.inferCategory(selection.Text(), description)
 	}
 	
 	// Default fallback
@@ -240,48 +529,6 @@ func (s *Scraper) extractCategoryFromURL(url string) string {
 	return ""
 }
 
-func (s *Scraper) inferCategoryFromTitle(title string) string {
-	// Category keywords mapping
-	categoryMap := map[string]string{
-		"python":      "Programming",
-		"javascript":  "Programming", 
-		"java":        "Programming",
-		"golang":      "Programming",
-		"react":       "Web Development",
-		"angular":     "Web Development",
-		"vue":         "Web Development",
-		"html":        "Web Development",
-		"css":         "Web Development",
-		"data":        "Data Science",
-		"analytics":   "Data Science",
-		"machine":     "Data Science",
-		"ai":          "Artificial Intelligence",
-		"design":      "Design",
-		"photoshop":   "Design",
-		"marketing":   "Marketing",
-		"business":    "Business",
-		"excel":       "Business",
-		"photography": "Photography",
-		"music":       "Music",
-		"fitness":     "Health & Fitness",
-		"yoga":        "Health & Fitness",
-		"language":    "Language",
-		"english":     "Language",
-		"spanish":     "Language",
-		"finance":     "Finance",
-		"investing":   "Finance",
-		"crypto":      "Finance",
-	}
-	
-	for keyword, category := range categoryMap {
-		if strings.Contains(title, keyword) {
-			return category
-		}
-	}
-	
-	return ""
-}
-
 func (s *Scraper) beautifyCategory(category string) string {
 	// Convert URL-style categories to readable format
 	category = strings.ReplaceAll(category, "-", " ")
@@ -361,14 +608,15 @@ func (s *Scraper) extractPrice(selection *goquery.Selection) string {
 	var priceText string
 	
 	// Try multiple selectors for price
+	// The original price is read separately by extractOriginalPrice
 	priceSelectors := []string{
-		".price", ".course-price", ".original-price", ".current-price", 
+		".price", ".course-price", ".current-price", 
 		".price-text", "[data-price]", ".cost", ".fee",
 	}
 	
 	container := selection.Closest("div, article, section")
 	for _, selector := range priceSelectors {
-		if price := container.Find(selector).First().Text(); price != "" {
+		if price := currentPriceText(container.Find(selector).First()); price != "" {
 			priceText = price
 			break
 		}
@@ -377,7 +625,7 @@ func (s *Scraper) extractPrice(selection *goquery.Selection) string {
 	// If no price found in container, check parent
 	if priceText == "" {
 		for _, selector := range priceSelectors {
-			if price := selection.Parent().Find(selector).First().Text(); price != "" {
+			if price := currentPriceText(selection.Parent().Find(selector).First()); price != "" {
 				priceText = price
 				break
 			}
@@ -394,8 +642,7 @@ func (s *Scraper) extractPrice(selection *goquery.Selection) string {
 	}
 	
 	// Extract price with currency symbols
-	priceRegex := regexp.MustCompile(`([£$€¥₹₱₩₪₫₡₦₨₴₵₷₸₺₼₽¢]\s*\d+(?:[.,]\d{2})?|\d+(?:[.,]\d{2})?\s*[£$€¥₹₱₩₪₫₡₦₨₴₵₷₸₺₼₽])`)
-	if match := priceRegex.FindString(priceText); match != "" {
+	if match := priceAmountRegex.FindString(priceText); match != "" {
 		return strings.TrimSpace(match)
 	}
 	
@@ -414,6 +661,28 @@ func (s *Scraper) extractPrice(selection *goquery.Selection) string {
 	return "Free"
 }
 
+// currentPriceText returns a price element's text without any struck-through
+// "was" price inside it
+func currentPriceText(price *goquery.Selection) string {
+	if price.Length() == 0 {
+		return ""
+	}
+	current := price.Clone()
+	current.Find(strikethroughSelector).Remove()
+	return current.Text()
+}
+
+// extractOriginalPrice returns the struck-through pre-discount price shown
+// next to the current one, or "" when the listing doesn't show one
+func (s *Scraper) extractOriginalPrice(selection *goquery.Selection) string {
+	for _, container := range []*goquery.Selection{selection.Closest("div, article, section"), selection.Parent()} {
+		if match := priceAmountRegex.FindString(container.Find(strikethroughSelector).First().Text()); match != "" {
+			return strings.TrimSpace(match)
+		}
+	}
+	return ""
+}
+
 func (s *Scraper) extractDiscount(selection *goquery.Selection, price string) string {
 	// If price indicates it's free, this is a discount
 	if strings.Contains(strings.ToLower(price), "free") || 
@@ -453,30 +722,10 @@ func (s *Scraper) extractDiscount(selection *goquery.Selection, price string) st
 	return "0%"
 }
 
-func (s *Scraper) followCouponLink(couponURL string) (string, error) {
-	time.Sleep(s.rateLimit) // Rate limiting
-	
-	req, err := http.NewRequest("GET", couponURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-	
-	req.Header.Set("User-Agent", s.userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch coupon page: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("coupon page returned status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+func (s *Scraper) followCouponLink(ctx context.Context, couponURL string) (string, error) {
+	doc, err := s.fetchDocument(ctx, couponURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse coupon page HTML: %w", err)
+		return "", fmt.Errorf("coupon page: %w", err)
 	}
 
 	// Look for Udemy course links on the coupon page (not user profiles)
@@ -523,7 +772,7 @@ func (s *Scraper) followCouponLink(couponURL string) (string, error) {
 				fullClaimURL = parsedCouponURL.Scheme + "://" + parsedCouponURL.Host + claimURL
 			}
 			
-			udemyURL, err = s.followClaimLink(fullClaimURL)
+			udemyURL, err = s.followClaimLink(ctx, fullClaimURL)
 			if err != nil {
 				log.Printf("Failed to follow claim link %s: %v", fullClaimURL, err)
 				return "", fmt.Errorf("failed to follow claim link: %w", err)
@@ -538,30 +787,10 @@ func (s *Scraper) followCouponLink(couponURL string) (string, error) {
 	return s.cleanUdemyURL(udemyURL)
 }
 
-func (s *Scraper) followClaimLink(claimURL string) (string, error) {
-	time.Sleep(s.rateLimit) // Rate limiting
-	
-	req, err := http.NewRequest("GET", claimURL, nil)
+func (s *Scraper) followClaimLink(ctx context.Context, claimURL string) (string, error) {
+	doc, err := s.fetchDocument(ctx, claimURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-	
-	req.Header.Set("User-Agent", s.userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch claim page: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("claim page returned status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse claim page HTML: %w", err)
+		return "", fmt.Errorf("claim page: %w", err)
 	}
 
 	// Look for Udemy course links on the claim page
@@ -640,32 +869,25 @@ func (s *Scraper) extractStudentCount(selection *goquery.Selection) int {
 	return 0
 }
 
-func (s *Scraper) extractExpirationDate(courseURL, title string) time.Time {
+func (s *Scraper) extractExpirationDate(sourceURL, courseURL, title string, selection *goquery.Selection) time.Time {
 	// Default expiration (7 days from now)
 	defaultExpiration := time.Now().Add(7 * 24 * time.Hour)
 	
-	// Try to extract date from coupon code in URL
-	if strings.Contains(courseURL, "couponCode=") {
-		// Extract coupon code
-		parsedURL, err := url.Parse(courseURL)
-		if err == nil {
-			if murl := parsedURL.Query().Get("murl"); murl != "" {
-				// Decode the murl parameter to get the actual Udemy URL
-				decodedURL, err := url.QueryUnescape(murl)
-				if err == nil {
-					innerURL, err := url.Parse(decodedURL)
-					if err == nil {
-						couponCode := innerURL.Query().Get("couponCode")
-						if couponCode != "" {
-							if expiration := s.parseCouponExpiration(couponCode); !expiration.IsZero() {
-								return expiration
-							}
-						}
-					}
-				}
-			}
+	// Try the aggregator's own expiry format first
+	if parser, ok := s.expirationParserFor(sourceURL); ok {
+		if expiration := parser.ParseExpiration(courseURL, selection); !expiration.IsZero() {
+			return s.clampExpiration(expiration, courseURL)
 		}
 	}
+
+	// Fall back to a date embedded in the coupon code
+	if expiration := (CouponCodeExpirationParser{}).ParseExpiration(courseURL, selection); !expiration.IsZero() {
+		return s.clampExpiration(expiration, courseURL)
+	}
+
+	if s.unknownExpiry == UnknownExpiryZero {
+		return time.Time{}
+	}
 	
 	// Intelligent defaults based on course characteristics
 	// High-quality courses tend to have longer validity
@@ -679,7 +901,7 @@ func (s *Scraper) extractExpirationDate(courseURL, title string) time.Time {
 	return defaultExpiration
 }
 
-func (s *Scraper) parseCouponExpiration(couponCode string) time.Time {
+func parseCouponExpiration(couponCode string) time.Time {
 	// Extract date-like parts from coupon code
 	// Look for patterns like "22JULY2025", "JULY2025", "2025", etc.
 	
@@ -718,6 +940,11 @@ func (s *Scraper) parseCouponExpiration(couponCode string) time.Time {
 		}
 	}
 	
+	// Numeric dates like "31122025", "2025-12-31" or "1225"
+	if expiration, ok := parseNumericCouponDate(couponCode, time.Now()); ok {
+		return expiration
+	}
+	
 	// Look for just year (like "2025") - assume end of year
 	re := regexp.MustCompile(`20\d{2}`)
 	if matches := re.FindStringSubmatch(couponCode); len(matches) > 0 {
@@ -730,81 +957,28 @@ func (s *Scraper) parseCouponExpiration(couponCode string) time.Time {
 }
 
 func (s *Scraper) calculateQualityScore(rating float64, studentCount int, title, description string) float64 {
-	var score float64
-	
-	// Base score from rating (0-40 points)
-	if rating > 0 {
-		score += rating * 8 // 5.0 rating = 40 points
-	}
-	
-	// Student count bonus (0-30 points)
-	switch {
-	case studentCount >= 1000:
-		score += 30
-	case studentCount >= 500:
-		score += 25
-	case studentCount >= 100:
-		score += 20
-	case studentCount >= 50:
-		score += 15
-	case studentCount >= 10:
-		score += 10
-	case studentCount > 0:
-		score += 5
-	}
-	
-	// Title quality indicators (0-15 points)
-	titleLower := strings.ToLower(title)
-	
-	// Positive indicators
-	positiveWords := []string{
-		"complete", "comprehensive", "masterclass", "bootcamp", "advanced", 
-		"professional", "certification", "diploma", "course", "guide",
-		"tutorial", "training", "learn", "master", "expert",
-	}
-	for _, word := range positiveWords {
-		if strings.Contains(titleLower, word) {
-			score += 2
-		}
-	}
-	
-	// Negative indicators (reduce score)
-	negativeWords := []string{
-		"quick", "crash", "basics only", "intro", "beginner only",
-		"summary", "overview", "brief",
-	}
-	for _, word := range negativeWords {
-		if strings.Contains(titleLower, word) {
-			score -= 3
+	return s.scorer.Score(rating, studentCount, title, description)
+}
+
+// textLongerThan reports whether the text inside selection is longer than
+// limit bytes. It walks the nodes and stops as soon as the limit is passed,
+// so a huge container is never copied into a single string.
+func textLongerThan(selection *goquery.Selection, limit int) bool {
+	total := 0
+	stack := append([]*html.Node(nil), selection.Nodes...)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if node.Type == html.TextNode {
+			total += len(node.Data)
+			if total > limit {
+				return true
+			}
 		}
-	}
-	
-	// Description quality (0-10 points)
-	if len(description) > 100 {
-		score += 5 // Detailed description
-	}
-	if len(description) > 200 {
-		score += 3 // Very detailed description
-	}
-	
-	// Year/recency bonus (0-5 points)
-	currentYear := time.Now().Year()
-	for year := currentYear; year >= currentYear-2; year-- {
-		if strings.Contains(title, strconv.Itoa(year)) {
-			score += float64(3 - (currentYear - year)) // 2025=3pts, 2024=2pts, 2023=1pt
-			break
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			stack = append(stack, child)
 		}
 	}
-	
-	// Cap the score at 100
-	if score > 100 {
-		score = 100
-	}
-	
-	// Ensure minimum score of 0
-	if score < 0 {
-		score = 0
-	}
-	
-	return score
-}
\ No newline at end of file
+	return false
+}
@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"udemy-course-notifier/database"
+)
+
+const testSourceURL = "https://source.example/"
+
+// extractFromHTML runs course extraction over an in-memory page
+func extractFromHTML(t *testing.T, s *Scraper, page string) []database.Course {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	courses, err := s.extractCourses(context.Background(), doc, testSourceURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return courses
+}
+
+// courseCard renders a listing for a direct Udemy link
+func courseCard(slug, title string) string {
+	return `<div class="card"><a href="https://www.udemy.com/course/` + slug + `/">` + title + `</a>` +
+		`<p class="description">A practical course with plenty of exercises.</p></div>`
+}
+
+func TestExtractCoursesSkipsOversizedContainer(t *testing.T) {
+	giant := `<div class="card"><a href="https://www.udemy.com/course/giant/">Giant Text Node Course</a>` +
+		strings.Repeat("x", maxContainerTextLength+1) + `</div>`
+	page := "<html><body>" + giant + courseCard("normal", "Normal Sized Listing Course") + "</body></html>"
+
+	courses := extractFromHTML(t, New("test", 0), page)
+
+	if len(courses) != 1 || !strings.Contains(courses[0].URL, "/course/normal") {
+		t.Fatalf("got %+v, want only the normal listing", courses)
+	}
+}
+
+func TestTextLongerThan(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(
+		`<div id="outer">abc<span>def<b>gh</b></span>ij</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer := doc.Find("#outer")
+
+	tests := []struct {
+		limit int
+		want  bool
+	}{
+		{9, true},
+		{10, false},
+		{100, false},
+	}
+	for _, tt := range tests {
+		if got := textLongerThan(outer, tt.limit); got != tt.want {
+			t.Errorf("textLongerThan(limit %d) = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+
+	if textLongerThan(doc.Find("#missing"), 0) {
+		t.Error("an empty selection has no text")
+	}
+}
+
+func TestScrapeCoursesFromFixture(t *testing.T) {
+	courses, err := New("test", 0).ScrapeCoursesFromURL(context.Background(), "file://testdata/listing.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var urls []string
+	for _, course := range courses {
+		urls = append(urls, course.URL)
+	}
+	want := []string{
+		"https://www.udemy.com/course/python-for-data-analysis/",
+		"https://www.udemy.com/course/docker-from-scratch/",
+	}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("scraped %v, want %v", urls, want)
+	}
+}
+
+func TestScrapeCoursesFromMissingFixture(t *testing.T) {
+	for _, sourceURL := range []string{"file://testdata/missing.html", "file://../config.yaml"} {
+		if _, err := New("test", 0).ScrapeCoursesFromURL(context.Background(), sourceURL); err == nil {
+			t.Errorf("ScrapeCoursesFromURL(%q) succeeded, want an error", sourceURL)
+		}
+	}
+}
+
+func TestSetScoringAlternativeWeights(t *testing.T) {
+	page := "<html><body>" + courseCard("golang", "Complete Go Programming Masterclass") + "</body></html>"
+	weights := DefaultScoringWeights()
+	alt := DefaultScoringWeights()
+	alt.TitleBonus = 5
+
+	s := New("test", 0)
+	s.SetScoring(weights, &alt)
+	courses := extractFromHTML(t, s, page)
+	if len(courses) != 1 {
+		t.Fatalf("got %+v, want one course", courses)
+	}
+	course := courses[0]
+
+	want := NewQualityScorer(weights).Score(course.Rating, course.StudentCount, course.Title, course.Description)
+	wantAlt := NewQualityScorer(alt).Score(course.Rating, course.StudentCount, course.Title, course.Description)
+	if want == wantAlt {
+		t.Fatalf("both formulas score %v, want the test weights to disagree", want)
+	}
+	if course.QualityScore != want {
+		t.Errorf("quality score = %v, want %v from the current weights", course.QualityScore, want)
+	}
+	if course.QualityScoreAlt == nil || *course.QualityScoreAlt != wantAlt {
+		t.Errorf("alternative score = %v, want %v", course.QualityScoreAlt, wantAlt)
+	}
+
+	// Turning the comparison off stops scoring with the alternative weights
+	s.SetScoring(weights, nil)
+	if course := extractFromHTML(t, s, page)[0]; course.QualityScoreAlt != nil {
+		t.Errorf("alternative score = %v with no alternative weights, want none", *course.QualityScoreAlt)
+	}
+}
@@ -0,0 +1,39 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestExtractCoursesMeasuresTitlesInCharacters(t *testing.T) {
+	long := strings.Repeat("编程", 150)
+	page := "<html><body>" +
+		courseCard("go-cjk", "Go语言编程入门实战课") + // 11 characters, 29 bytes
+		courseCard("python-emoji", "🐍 Python 🚀") + // 10 characters, 17 bytes
+		courseCard("short-cjk", "编程入门") + // 12 bytes but only 4 characters
+		courseCard("long-cjk", long) +
+		"</body></html>"
+
+	titles := map[string]string{}
+	for _, course := range extractFromHTML(t, New("test", 0), page) {
+		slug := strings.TrimSuffix(strings.TrimPrefix(course.URL, "https://www.udemy.com/course/"), "/")
+		titles[slug] = course.Title
+	}
+
+	if got := titles["go-cjk"]; got != "Go语言编程入门实战课" {
+		t.Errorf("CJK title = %q, want it kept intact", got)
+	}
+	if got := titles["python-emoji"]; got != "🐍 Python 🚀" {
+		t.Errorf("emoji title = %q, want it kept intact", got)
+	}
+	if got, ok := titles["short-cjk"]; ok {
+		t.Errorf("kept the 4-character title %q", got)
+	}
+
+	got := titles["long-cjk"]
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != 200 || !strings.HasPrefix(long, got) {
+		t.Errorf("long CJK title truncated to %d characters (valid UTF-8: %v), want the first 200",
+			utf8.RuneCountInString(got), utf8.ValidString(got))
+	}
+}
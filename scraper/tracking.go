@@ -0,0 +1,79 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SetVerifyTrackingLinks enables checking that affiliate/tracking links still
+// land on a Udemy course page before the course is kept. This costs one extra
+// request per new course with a tracking link.
+func (s *Scraper) SetVerifyTrackingLinks(enabled bool) {
+	s.verifyTrackingLinks = enabled
+}
+
+// VerifyCourseLink returns an error if a course's tracking link no longer
+// reaches a course page. Direct links, and every link when verification is
+// off, pass without a request. Scans call it only for courses not seen before.
+func (s *Scraper) VerifyCourseLink(ctx context.Context, courseURL string) error {
+	if !s.verifyTrackingLinks || !isTrackingURL(courseURL) {
+		return nil
+	}
+	return s.verifyTrackingURL(ctx, courseURL)
+}
+
+// isTrackingURL reports whether a link is an affiliate/tracking redirect
+// (e.g. linksynergy) rather than a direct Udemy link
+func isTrackingURL(rawURL string) bool {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(parsedURL.Host, "linksynergy.com") ||
+		strings.Contains(parsedURL.Host, "click.") ||
+		strings.Contains(rawURL, "murl=")
+}
+
+// verifyTrackingURL follows a tracking link's redirects and returns an error
+// unless it ends on a udemy.com course page that answers 200. Expired
+// tracking links typically 404 or land on a home or error page.
+func (s *Scraper) verifyTrackingURL(ctx context.Context, trackingURL string) error {
+	if err := s.limiter.wait(ctx); err != nil {
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, s.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, "GET", trackingURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to follow tracking link: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("tracking link ended with status code: %d", resp.StatusCode)
+	}
+
+	// resp.Request is the last request made, after any redirects
+	finalURL := resp.Request.URL
+	host := strings.ToLower(finalURL.Hostname())
+	if host != "udemy.com" && !strings.HasSuffix(host, ".udemy.com") {
+		return fmt.Errorf("tracking link ended off Udemy at %s", finalURL)
+	}
+	if !s.isCourseURL(finalURL.String()) {
+		return fmt.Errorf("tracking link ended on a non-course page %s", finalURL)
+	}
+
+	return nil
+}
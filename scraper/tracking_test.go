@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testTrackingURL = "https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fgo-basics%2F"
+
+// serveTrackingLink makes the tracking link redirect to landing, where
+// Udemy answers with status
+func serveTrackingLink(t *testing.T, s *Scraper, landing string, status int) {
+	t.Helper()
+	serveUdemy(t, s, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Host, "linksynergy.com") {
+			http.Redirect(w, r, landing, http.StatusFound)
+			return
+		}
+		w.WriteHeader(status)
+	})
+}
+
+func TestVerifyCourseLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		landing string
+		status  int
+		wantErr bool
+	}{
+		{"reaches a course page", "https://www.udemy.com/course/go-basics/", http.StatusOK, false},
+		{"redirects to a 404", "https://www.udemy.com/course/go-basics/", http.StatusNotFound, true},
+		{"lands on the home page", "https://www.udemy.com/", http.StatusOK, true},
+		{"lands off Udemy", "https://expired.example/", http.StatusOK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("test", 0)
+			s.SetVerifyTrackingLinks(true)
+			serveTrackingLink(t, s, tt.landing, tt.status)
+
+			err := s.VerifyCourseLink(context.Background(), testTrackingURL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyCourseLink = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyCourseLinkSkipsWithoutRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		enabled bool
+	}{
+		{"verification off", testTrackingURL, false},
+		{"direct link", "https://www.udemy.com/course/go-basics/", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("test", 0)
+			s.SetVerifyTrackingLinks(tt.enabled)
+			requests := serveUdemy(t, s, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			if err := s.VerifyCourseLink(context.Background(), tt.url); err != nil {
+				t.Errorf("VerifyCourseLink = %v, want the link to pass", err)
+			}
+			if n := requests.Load(); n != 0 {
+				t.Errorf("made %d requests, want none", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/language"
+)
+
+var (
+	captionLanguagesJSONRegex = regexp.MustCompile(`"caption_languages"\s*:\s*(\[[^\]]*\])`)
+	instructionalLevelRegex   = regexp.MustCompile(`"instructional_level(?:_simple)?"\s*:\s*"([^"]*)"`)
+	hasCertificateRegex       = regexp.MustCompile(`"has_certificate"\s*:\s*(true|false)`)
+)
+
+// UdemyMetaCache stores details read from Udemy course pages so a course
+// listed by several sources is fetched once. *database.DB implements it.
+type UdemyMetaCache interface {
+	GetUdemyMeta(courseURL string, maxAge time.Duration) (*database.UdemyMeta, error)
+	PutUdemyMeta(meta database.UdemyMeta) error
+}
+
+// SetUdemyEnrichment enables fetching each new course's Udemy page for
+// details that listing pages don't expose. This costs one extra request per
+// new course whose page isn't cached.
+func (s *Scraper) SetUdemyEnrichment(enabled bool) {
+	s.enrichFromUdemy = enabled
+}
+
+// SetUdemyMetaCache sets where Udemy page details are cached, and for how long
+func (s *Scraper) SetUdemyMetaCache(cache UdemyMetaCache, ttl time.Duration) {
+	s.udemyMetaCache = cache
+	s.udemyMetaTTL = ttl
+}
+
+// udemyPageURL returns the udemy.com course page behind a course URL,
+// unwrapping tracking links. It returns "" for non-Udemy URLs.
+func udemyPageURL(courseURL string) string {
+	parsedURL, err := url.Parse(courseURL)
+	if err != nil {
+		return ""
+	}
+
+	if murl := parsedURL.Query().Get("murl"); murl != "" {
+		if inner, err := url.Parse(murl); err == nil {
+			parsedURL = inner
+		}
+	}
+
+	host := strings.ToLower(parsedURL.Host)
+	if host != "udemy.com" && !strings.HasSuffix(host, ".udemy.com") {
+		return ""
+	}
+
+	return parsedURL.String()
+}
+
+// EnrichCourse fills in details from the course's Udemy page when
+// enrichment is on. Scans call it only for courses not seen before, so a
+// course is fetched once rather than every time it is listed.
+func (s *Scraper) EnrichCourse(ctx context.Context, course *database.Course) {
+	if s.enrichFromUdemy {
+		s.enrichFromUdemyPage(ctx, course)
+	}
+}
+
+// enrichFromUdemyPage fills in course details from its Udemy page. This is
+// best-effort: failures are logged and the course is left unchanged.
+func (s *Scraper) enrichFromUdemyPage(ctx context.Context, course *database.Course) {
+	pageURL := udemyPageURL(course.URL)
+	if pageURL == "" {
+		return
+	}
+
+	if s.udemyMetaCache != nil {
+		meta, err := s.udemyMetaCache.GetUdemyMeta(pageURL, s.udemyMetaTTL)
+		if err != nil {
+			log.Printf("Failed to read cached Udemy page for %s: %v", course.Title, err)
+		} else if meta != nil {
+			applyUdemyMeta(course, meta)
+			return
+		}
+	}
+
+	doc, err := s.fetchDocument(ctx, pageURL)
+	if err != nil {
+		log.Printf("Failed to fetch Udemy page for %s: %v", course.Title, err)
+		return
+	}
+
+	meta := extractUdemyMeta(doc)
+	meta.URL = pageURL
+	applyUdemyMeta(course, &meta)
+
+	if s.udemyMetaCache != nil {
+		if err := s.udemyMetaCache.PutUdemyMeta(meta); err != nil {
+			log.Printf("Failed to cache Udemy page for %s: %v", course.Title, err)
+		}
+	}
+}
+
+// applyUdemyMeta copies details from a course's Udemy page onto the course.
+// The page's language is authoritative, so it replaces any earlier guess.
+func applyUdemyMeta(course *database.Course, meta *database.UdemyMeta) {
+	course.CaptionLanguages = meta.CaptionLanguages
+	if meta.Language != "" {
+		course.Language = meta.Language
+	}
+	if meta.Certificate != nil {
+		course.Certificate = meta.Certificate
+	}
+}
+
+// extractCourseLanguage reads the course's spoken language from the page's
+// JSON-LD inLanguage, falling back to the details section
+func extractCourseLanguage(doc *goquery.Document) string {
+	var code string
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		var data interface{}
+		if err := json.Unmarshal([]byte(selection.Text()), &data); err != nil {
+			return true
+		}
+		code = language.Code(findInLanguage(data))
+		return code == ""
+	})
+	if code != "" {
+		return code
+	}
+
+	return language.Code(doc.Find("[data-purpose='lead-course-locale']").First().Text())
+}
+
+// findInLanguage returns the first inLanguage value in decoded JSON-LD, which
+// may be a plain string or a Language object with a name
+func findInLanguage(data interface{}) string {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		switch lang := value["inLanguage"].(type) {
+		case string:
+			return lang
+		case map[string]interface{}:
+			if name, ok := lang["name"].(string); ok {
+				return name
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if found := findInLanguage(value[key]); found != "" {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			if found := findInLanguage(child); found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
+
+// extractUdemyMeta reads the details cached for a Udemy course page
+func extractUdemyMeta(doc *goquery.Document) database.UdemyMeta {
+	meta := database.UdemyMeta{
+		Title:            strings.TrimSpace(doc.Find("meta[property='og:title']").AttrOr("content", "")),
+		Image:            strings.TrimSpace(doc.Find("meta[property='og:image']").AttrOr("content", "")),
+		Level:            strings.TrimSpace(doc.Find("[data-purpose='lead-course-level']").First().Text()),
+		CaptionLanguages: extractCaptionLanguages(doc),
+		Language:         extractCourseLanguage(doc),
+		Certificate:      extractCertificate(doc),
+	}
+
+	if meta.Level == "" {
+		if html, err := doc.Html(); err == nil {
+			if matches := instructionalLevelRegex.FindStringSubmatch(html); len(matches) > 1 {
+				meta.Level = matches[1]
+			}
+		}
+	}
+
+	return meta
+}
+
+// extractCertificate reports whether a Udemy course page offers a
+// certificate of completion. The embedded course data is checked first,
+// then the "This course includes" list; nil when neither is on the page.
+func extractCertificate(doc *goquery.Document) *bool {
+	if html, err := doc.Html(); err == nil {
+		if matches := hasCertificateRegex.FindStringSubmatch(html); len(matches) > 1 {
+			offered := matches[1] == "true"
+			return &offered
+		}
+	}
+
+	text := strings.ToLower(doc.Text())
+	if strings.Contains(text, "certificate of completion") {
+		offered := true
+		return &offered
+	}
+	// The includes list is present but doesn't mention a certificate
+	if strings.Contains(text, "this course includes") {
+		offered := false
+		return &offered
+	}
+	return nil
+}
+
+// extractCaptionLanguages reads the caption languages listed on a Udemy
+// course page, as ISO 639-1 codes
+func extractCaptionLanguages(doc *goquery.Document) []string {
+	var names []string
+
+	doc.Find("[data-purpose='lead-course-captions'], [data-purpose='caption-languages'], .clp-lead__caption").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		text := strings.TrimSpace(selection.Text())
+		if text == "" {
+			return true
+		}
+		names = strings.Split(text, ",")
+		return false
+	})
+
+	// Fall back to the course data embedded in the page
+	if len(names) == 0 {
+		if html, err := doc.Html(); err == nil {
+			if matches := captionLanguagesJSONRegex.FindStringSubmatch(html); len(matches) > 1 {
+				json.Unmarshal([]byte(matches[1]), &names)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var codes []string
+	for _, name := range names {
+		code := language.Code(name)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+
+	return codes
+}
@@ -0,0 +1,183 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"udemy-course-notifier/database"
+)
+
+// parseHTML parses an in-memory page
+func parseHTML(t *testing.T, page string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestExtractCaptionLanguages(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want []string
+	}{
+		{
+			name: "lead captions list",
+			page: `<div data-purpose="lead-course-captions">English [Auto], Spanish, English</div>`,
+			want: []string{"en", "es"},
+		},
+		{
+			name: "embedded course data",
+			page: `<script>window.course = {"caption_languages": ["English", "pt-BR"]};</script>`,
+			want: []string{"en", "pt"},
+		},
+		{
+			name: "no captions listed",
+			page: `<div class="clp-lead"><h1>Go Basics</h1></div>`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractCaptionLanguages(parseHTML(t, tt.page))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractCaptionLanguages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// redirectTransport sends every request to target, whatever its host.
+// Responses keep the original request, so callers still see the URL they
+// asked for.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	redirected := r.Clone(r.Context())
+	redirected.URL.Scheme, redirected.URL.Host = rt.target.Scheme, rt.target.Host
+	resp, err := http.DefaultTransport.RoundTrip(redirected)
+	if resp != nil {
+		resp.Request = r
+	}
+	return resp, err
+}
+
+// serveUdemy makes s fetch every page, Udemy's included, from handler and
+// returns a count of the requests made
+func serveUdemy(t *testing.T, s *Scraper, handler http.HandlerFunc) *atomic.Int32 {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	s.client = &http.Client{Transport: redirectTransport{target}}
+	return &requests
+}
+
+func TestEnrichCourseReusesCachedMeta(t *testing.T) {
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	s := New("test", 0)
+	s.SetUdemyEnrichment(true)
+	s.SetUdemyMetaCache(db, time.Hour)
+	fetches := serveUdemy(t, s, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><meta property="og:title" content="Go Basics"></head>
+			<body><div data-purpose="lead-course-captions">English, Spanish</div></body></html>`))
+	})
+
+	// Two sources list the same course with different coupons
+	for _, code := range []string{"FIRST", "SECOND"} {
+		course := database.Course{URL: "https://www.udemy.com/course/go-basics/?couponCode=" + code, Title: "Go Basics"}
+		s.EnrichCourse(context.Background(), &course)
+		if !reflect.DeepEqual(course.CaptionLanguages, []string{"en", "es"}) {
+			t.Errorf("course with coupon %s has captions %v, want en and es", code, course.CaptionLanguages)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetched the Udemy page %d times, want once", got)
+	}
+
+	// Past the TTL the page is fetched again
+	s.SetUdemyMetaCache(db, 0)
+	course := database.Course{URL: "https://www.udemy.com/course/go-basics/", Title: "Go Basics"}
+	s.EnrichCourse(context.Background(), &course)
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("fetched the Udemy page %d times after the TTL, want twice", got)
+	}
+}
+
+func TestExtractCourseLanguage(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "udemy_course.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		page string
+		want string
+	}{
+		{"JSON-LD in a graph beats the details section", string(fixture), "es"},
+		{"Language object", `<script type="application/ld+json">{"@type": "Course", "inLanguage": {"@type": "Language", "name": "Portuguese"}}</script>`, "pt"},
+		{"malformed JSON-LD", `<script type="application/ld+json">{"inLanguage": </script><div data-purpose="lead-course-locale">Deutsch</div>`, "de"},
+		{"details section only", `<div data-purpose="lead-course-locale">Japanese</div>`, "ja"},
+		{"nothing on the page", `<h1>Go Basics</h1>`, ""},
+	}
+	for _, tt := range tests {
+		if got := extractCourseLanguage(parseHTML(t, tt.page)); got != tt.want {
+			t.Errorf("%s: extractCourseLanguage() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEnrichCoursePrefersPageLanguage(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "udemy_course.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New("test", 0)
+	s.SetUdemyEnrichment(true)
+	serveUdemy(t, s, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/course/go-desde-cero/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(fixture)
+	})
+
+	// The title looked English to the heuristic detector
+	course := database.Course{URL: "https://www.udemy.com/course/go-desde-cero/", Title: "Go from Zero", Language: "en"}
+	s.EnrichCourse(context.Background(), &course)
+	if course.Language != "es" {
+		t.Errorf("language = %q, want the page's es", course.Language)
+	}
+
+	course = database.Course{URL: "https://www.udemy.com/course/unreachable/", Title: "Go from Zero", Language: "en"}
+	s.EnrichCourse(context.Background(), &course)
+	if course.Language != "en" {
+		t.Errorf("language = %q after a failed fetch, want the detected en", course.Language)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -22,6 +23,13 @@ var (
 		"courson.xyz",
 	}
 	
+	// Domains a stored course link may point at: Udemy itself and the
+	// affiliate tracking services that sources wrap Udemy links in
+	courseURLDomains = []string{
+		"udemy.com",
+		"linksynergy.com",
+	}
+	
 	// Regex for basic input sanitization
 	safeStringRegex = regexp.MustCompile(`^[a-zA-Z0-9\s\-_.,|:]+$`)
 )
@@ -44,21 +52,94 @@ func ValidateURL(rawURL string) error {
 
 	// Check domain allowlist
 	host := strings.ToLower(parsedURL.Host)
-	allowed := false
-	for _, domain := range allowedDomains {
+	if !hostMatches(host, allowedDomains) {
+		return fmt.Errorf("domain not allowed: %s", host)
+	}
+
+	return nil
+}
+
+// ValidateCourseURL ensures a scraped course link points at Udemy or a known
+// tracking domain before it is stored or posted. Tracking links that carry
+// their destination in murl must also lead to Udemy.
+func ValidateCourseURL(rawURL string) error {
+	if len(rawURL) > 2048 {
+		return fmt.Errorf("URL too long")
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL format: %w", err)
+	}
+
+	if parsedURL.Scheme != "https" && parsedURL.Scheme != "http" {
+		return fmt.Errorf("invalid URL scheme: %s", parsedURL.Scheme)
+	}
+
+	if !hostMatches(parsedURL.Hostname(), courseURLDomains) {
+		return fmt.Errorf("course domain not allowed: %s", parsedURL.Hostname())
+	}
+
+	if murl := parsedURL.Query().Get("murl"); murl != "" {
+		target, err := url.Parse(murl)
+		if err != nil || !hostMatches(target.Hostname(), []string{"udemy.com"}) {
+			return fmt.Errorf("tracking link does not lead to Udemy: %s", murl)
+		}
+	}
+
+	return nil
+}
+
+// hostMatches reports whether host is one of domains or a subdomain of one
+func hostMatches(host string, domains []string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range domains {
 		if host == domain || strings.HasSuffix(host, "."+domain) {
-			allowed = true
-			break
+			return true
 		}
 	}
+	return false
+}
 
-	if !allowed {
-		return fmt.Errorf("domain not allowed: %s", host)
+// ValidateSourceURL validates a scrape source. Besides allowlisted web URLs,
+// file:// URLs pointing at local fixture pages are accepted for offline
+// development and for reproducing selector bugs from a saved page.
+func ValidateSourceURL(rawURL string) error {
+	if !strings.HasPrefix(rawURL, "file://") {
+		return ValidateURL(rawURL)
 	}
 
+	path, err := FixturePath(rawURL)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(strings.ToLower(path), ".html") && !strings.HasSuffix(strings.ToLower(path), ".htm") {
+		return fmt.Errorf("fixture must be an .html file")
+	}
 	return nil
 }
 
+// FixturePath returns the validated local path of a file:// source URL.
+// Both file:///abs/path.html and file://relative/path.html are accepted.
+func FixturePath(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL format: %w", err)
+	}
+	if parsedURL.Scheme != "file" {
+		return "", fmt.Errorf("invalid URL scheme: %s", parsedURL.Scheme)
+	}
+
+	path := parsedURL.Host + parsedURL.Path
+	if path == "" {
+		return "", fmt.Errorf("fixture path is empty")
+	}
+	if err := ValidateFilePath(path); err != nil {
+		return "", fmt.Errorf("invalid fixture path: %w", err)
+	}
+	return path, nil
+}
+
 // ValidateFilePath ensures file path is safe
 func ValidateFilePath(path string) error {
 	if len(path) > 255 {
@@ -81,9 +162,7 @@ func ValidateFilePath(path string) error {
 
 // SanitizeString removes dangerous characters from user input
 func SanitizeString(input string) string {
-	if len(input) > MaxFilterStringLength {
-		input = input[:MaxFilterStringLength]
-	}
+	input = TruncateRunes(input, MaxFilterStringLength)
 
 	// Remove potentially dangerous characters
 	input = strings.ReplaceAll(input, "\x00", "")
@@ -94,9 +173,20 @@ func SanitizeString(input string) string {
 	return strings.TrimSpace(input)
 }
 
-// ValidateFilterString validates user filter input
+// TruncateRunes shortens s to at most limit characters without splitting a
+// multi-byte character
+func TruncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:limit])
+}
+
+// ValidateFilterString validates user filter input. Its length is counted in
+// characters, not bytes, so non-Latin filters get the same room.
 func ValidateFilterString(filter string) error {
-	if len(filter) > MaxFilterStringLength {
+	if utf8.RuneCountInString(filter) > MaxFilterStringLength {
 		return fmt.Errorf("filter string too long")
 	}
 
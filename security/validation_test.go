@@ -0,0 +1,77 @@
+package security
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestValidateSourceURLFixtures(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"file://testdata/listing.html", true},
+		{"file:///var/fixtures/page.htm", true},
+		{"file://testdata/notes.txt", false},
+		{"file://../secrets/page.html", false},
+		{"file://", false},
+	}
+	for _, tt := range tests {
+		err := ValidateSourceURL(tt.url)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateSourceURL(%q) = %v, want valid %v", tt.url, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateCourseURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://www.udemy.com/course/go-basics/", true},
+		{"https://udemy.com/course/go-basics/?couponCode=FREE", true},
+		{"https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fgo-basics%2F", true},
+		{"https://click.linksynergy.com/deeplink?id=abc", true},
+		{"https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fevil.example%2F", false},
+		{"https://evil.example/course/go-basics/", false},
+		{"https://udemy.com.evil.example/course/go-basics/", false},
+		{"https://notudemy.com/course/go-basics/", false},
+		{"javascript:alert(1)", false},
+		{"ftp://www.udemy.com/course/go-basics/", false},
+	}
+	for _, tt := range tests {
+		err := ValidateCourseURL(tt.url)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateCourseURL(%q) = %v, want valid %v", tt.url, err, tt.valid)
+		}
+	}
+}
+
+func TestSanitizeStringKeepsMultiByteCharacters(t *testing.T) {
+	for _, input := range []string{
+		strings.Repeat("编", MaxFilterStringLength+5),
+		strings.Repeat("🚀", MaxFilterStringLength+5),
+	} {
+		got := SanitizeString(input)
+		if !utf8.ValidString(got) || utf8.RuneCountInString(got) != MaxFilterStringLength {
+			t.Errorf("SanitizeString cut a %d-character string to %d characters (valid UTF-8: %v), want %d",
+				utf8.RuneCountInString(input), utf8.RuneCountInString(got), utf8.ValidString(got), MaxFilterStringLength)
+		}
+	}
+
+	if got := SanitizeString("Go语言\n入门 🐍"); got != "Go语言 入门 🐍" {
+		t.Errorf("SanitizeString = %q, want the characters kept and the newline replaced", got)
+	}
+}
+
+func TestValidateFilterStringCountsCharacters(t *testing.T) {
+	// Three bytes per character: over the limit in bytes, within it in characters
+	if err := ValidateFilterString(strings.Repeat("编", MaxFilterStringLength)); err != nil {
+		t.Errorf("ValidateFilterString rejected a %d-character CJK filter: %v", MaxFilterStringLength, err)
+	}
+	if err := ValidateFilterString(strings.Repeat("编", MaxFilterStringLength+1)); err == nil {
+		t.Error("ValidateFilterString accepted a filter over the character limit")
+	}
+}
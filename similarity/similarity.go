@@ -1,15 +1,36 @@
 package similarity
 
 import (
+	"fmt"
 	"math"
 	"regexp"
+	"sort"
 	"strings"
 	"udemy-course-notifier/database"
 )
 
+// Criteria FindBestCourse can use to choose between duplicates
+const (
+	PreferDiscount = "discount" // Free, then higher discount
+	PreferQuality  = "quality"
+	PreferRating   = "rating"
+	PreferStudents = "students"
+	PreferRecency  = "recency"
+)
+
+// DefaultPriority prefers the free listing of a duplicated course, then
+// falls back to quality signals
+var DefaultPriority = []string{PreferDiscount, PreferQuality, PreferRating, PreferStudents, PreferRecency}
+
 // SimilarityEngine handles course deduplication and similarity detection
 type SimilarityEngine struct {
 	similarityThreshold float64
+	priority            []string // Order of criteria used by FindBestCourse
+	synonyms            map[string]string // Variant phrase -> canonical token
+	maxSynonymWords     int
+	slugDedup           bool // Collapse listings sharing a Udemy course slug
+	window              int  // Courses compared pairwise at a time; 0 for the whole batch
+	stopWords           map[string]bool // Words dropped before comparing text
 }
 
 // New creates a new similarity engine
@@ -17,9 +38,38 @@ func New(threshold float64) *SimilarityEngine {
 	if threshold <= 0 || threshold > 1 {
 		threshold = 0.85 // Default 85% similarity threshold
 	}
-	return &SimilarityEngine{
+	se := &SimilarityEngine{
 		similarityThreshold: threshold,
+		priority:            DefaultPriority,
+	}
+	se.SetSynonyms(nil)
+	se.SetStopWords(nil)
+	return se
+}
+
+// SetPriority sets the order in which FindBestCourse compares duplicates.
+// An empty order keeps DefaultPriority.
+func (se *SimilarityEngine) SetPriority(order []string) error {
+	if err := ValidatePriority(order); err != nil {
+		return err
+	}
+	if len(order) == 0 {
+		order = DefaultPriority
 	}
+	se.priority = order
+	return nil
+}
+
+// ValidatePriority checks that every criterion in order is known
+func ValidatePriority(order []string) error {
+	for _, criterion := range order {
+		switch criterion {
+		case PreferDiscount, PreferQuality, PreferRating, PreferStudents, PreferRecency:
+		default:
+			return fmt.Errorf("unknown dedup priority %q", criterion)
+		}
+	}
+	return nil
 }
 
 // IsSimilar checks if two courses are similar enough to be considered duplicates
@@ -61,36 +111,35 @@ func (se *SimilarityEngine) CalculateSimilarity(course1, course2 *database.Cours
 	return math.Min(totalSimilarity, 1.0)
 }
 
-// FindBestCourse returns the better course from a similar pair
+// FindBestCourse returns the better course from a similar pair, comparing
+// the criteria in priority order until one differs
 func (se *SimilarityEngine) FindBestCourse(course1, course2 *database.Course) *database.Course {
-	// Compare by quality score first
-	if course1.QualityScore != course2.QualityScore {
-		if course1.QualityScore > course2.QualityScore {
-			return course1
+	for _, criterion := range se.priority {
+		var better1, better2 bool
+		switch criterion {
+		case PreferDiscount:
+			level1 := database.DiscountLevel(course1.Price, course1.Discount)
+			level2 := database.DiscountLevel(course2.Price, course2.Discount)
+			better1, better2 = level1 > level2, level2 > level1
+		case PreferQuality:
+			better1, better2 = course1.QualityScore > course2.QualityScore, course2.QualityScore > course1.QualityScore
+		case PreferRating:
+			better1, better2 = course1.Rating > course2.Rating, course2.Rating > course1.Rating
+		case PreferStudents:
+			better1, better2 = course1.StudentCount > course2.StudentCount, course2.StudentCount > course1.StudentCount
+		case PreferRecency:
+			better1, better2 = course1.PostedAt.After(course2.PostedAt), course2.PostedAt.After(course1.PostedAt)
 		}
-		return course2
-	}
-	
-	// If quality scores are equal, compare by rating
-	if course1.Rating != course2.Rating {
-		if course1.Rating > course2.Rating {
+
+		if better1 {
 			return course1
 		}
-		return course2
-	}
-	
-	// If ratings are equal, compare by student count
-	if course1.StudentCount != course2.StudentCount {
-		if course1.StudentCount > course2.StudentCount {
-			return course1
+		if better2 {
+			return course2
 		}
-		return course2
 	}
 	
-	// If all else is equal, return the more recent one
-	if course1.PostedAt.After(course2.PostedAt) {
-		return course1
-	}
+	// If all else is equal, keep the later one
 	return course2
 }
 
@@ -100,6 +149,25 @@ func (se *SimilarityEngine) DeduplicateCourses(courses []database.Course) []data
 		return courses
 	}
 	
+	courses = se.collapseSharedCoupons(courses)
+	if se.slugDedup {
+		courses = se.collapseSharedSlugs(courses)
+	}
+	
+	var deduplicated []database.Course
+	if se.window > 0 && len(courses) > se.window {
+		deduplicated = se.dedupWindowed(courses)
+	} else {
+		deduplicated = se.dedupPass(courses)
+	}
+	
+	sortCourses(deduplicated)
+	return deduplicated
+}
+
+// dedupPass compares every pair of courses, keeping the best of each group
+// of similar ones in first-seen order
+func (se *SimilarityEngine) dedupPass(courses []database.Course) []database.Course {
 	var deduplicated []database.Course
 	processed := make(map[int]bool)
 	
@@ -119,9 +187,10 @@ func (se *SimilarityEngine) DeduplicateCourses(courses []database.Course) []data
 			
 			course2 := courses[j]
 			if se.IsSimilar(&bestCourse, &course2) {
-				// Found a similar course, keep the better one
+				// Found a similar course, keep the better one. Compare pointers,
+				// since freshly scraped courses all share ID 0.
 				betterCourse := se.FindBestCourse(&bestCourse, &course2)
-				if betterCourse.ID == course2.ID {
+				if betterCourse == &course2 {
 					bestCourse = course2
 				}
 				processed[j] = true
@@ -134,6 +203,54 @@ func (se *SimilarityEngine) DeduplicateCourses(courses []database.Course) []data
 	return deduplicated
 }
 
+// collapseSharedCoupons merges listings that carry the same coupon code for
+// the same normalized title, even when their URLs differ. A shared code is a
+// precise signal, so this runs before the fuzzy similarity pass.
+func (se *SimilarityEngine) collapseSharedCoupons(courses []database.Course) []database.Course {
+	type couponKey struct {
+		title string
+		code  string
+	}
+
+	var collapsed []database.Course
+	index := make(map[couponKey]int)
+
+	for _, course := range courses {
+		code := database.CouponCode(course.URL)
+		if code == "" {
+			collapsed = append(collapsed, course)
+			continue
+		}
+
+		key := couponKey{title: se.normalizeText(course.Title), code: strings.ToUpper(code)}
+		if i, ok := index[key]; ok {
+			if better := se.FindBestCourse(&collapsed[i], &course); better == &course {
+				collapsed[i] = course
+			}
+			continue
+		}
+
+		index[key] = len(collapsed)
+		collapsed = append(collapsed, course)
+	}
+
+	return collapsed
+}
+
+// sortCourses orders courses by quality score (highest first), then title,
+// then URL, so output is reproducible for the same input
+func sortCourses(courses []database.Course) {
+	sort.SliceStable(courses, func(i, j int) bool {
+		if courses[i].QualityScore != courses[j].QualityScore {
+			return courses[i].QualityScore > courses[j].QualityScore
+		}
+		if courses[i].Title != courses[j].Title {
+			return courses[i].Title < courses[j].Title
+		}
+		return courses[i].URL < courses[j].URL
+	})
+}
+
 // calculateTextSimilarity uses Jaccard similarity on normalized text
 func (se *SimilarityEngine) calculateTextSimilarity(text1, text2 string) float64 {
 	if text1 == text2 {
@@ -172,41 +289,42 @@ func (se *SimilarityEngine) calculateTextSimilarity(text1, text2 string) float64
 	return float64(intersection) / float64(union)
 }
 
+// commonPrefixes are filler words in course titles, removed before comparing
+var commonPrefixes = []string{
+	"complete", "comprehensive", "ultimate", "full", "total", "entire",
+	"master", "mastering", "learn", "learning", "course", "tutorial",
+	"guide", "introduction", "intro", "advanced", "beginner", "basic",
+	"professional", "pro", "expert", "bootcamp", "training",
+}
+
+// Compiled once, since normalizeText runs for every pair a scan compares
+var (
+	prefixRegex       = regexp.MustCompile(`\b(?:` + strings.Join(commonPrefixes, "|") + `)\b`)
+	yearRegex         = regexp.MustCompile(`\b20\d{2}\b`)
+	specialCharsRegex = regexp.MustCompile(`[^\p{L}\p{N}\s]`)
+	whitespaceRegex   = regexp.MustCompile(`\s+`)
+)
+
 // normalizeText cleans and normalizes text for comparison
 func (se *SimilarityEngine) normalizeText(text string) string {
 	// Convert to lowercase
 	text = strings.ToLower(text)
+
+	// Unify abbreviations such as "JS" and "JavaScript"
+	text = se.applySynonyms(text)
 	
-	// Remove common course prefixes/suffixes
-	commonPrefixes := []string{
-		"complete", "comprehensive", "ultimate", "full", "total", "entire",
-		"master", "mastering", "learn", "learning", "course", "tutorial",
-		"guide", "introduction", "intro", "advanced", "beginner", "basic",
-		"professional", "pro", "expert", "bootcamp", "training",
-	}
-	
-	for _, prefix := range commonPrefixes {
-		// Remove as prefix
-		if strings.HasPrefix(text, prefix+" ") {
-			text = strings.TrimPrefix(text, prefix+" ")
-		}
-		// Remove as suffix
-		if strings.HasSuffix(text, " "+prefix) {
-			text = strings.TrimSuffix(text, " "+prefix)
-		}
-		// Remove standalone
-		text = regexp.MustCompile(`\b`+regexp.QuoteMeta(prefix)+`\b`).ReplaceAllString(text, "")
-	}
+	// Remove common course prefixes/suffixes wherever they stand
+	text = prefixRegex.ReplaceAllString(text, "")
 	
 	// Remove years (2024, 2025, etc.)
-	yearRegex := regexp.MustCompile(`\b20\d{2}\b`)
 	text = yearRegex.ReplaceAllString(text, "")
 	
 	// Remove special characters and normalize whitespace
-	text = regexp.MustCompile(`[^\p{L}\p{N}\s]`).ReplaceAllString(text, " ")
-	text = regexp.MustCompile(`\s+`).ReplaceAllString(text, " ")
+	text = specialCharsRegex.ReplaceAllString(text, " ")
+	text = whitespaceRegex.ReplaceAllString(text, " ")
 	
-	return strings.TrimSpace(text)
+	// Drop words like "the" and "with" that don't identify a course
+	return se.removeStopWords(text)
 }
 
 // getWordSet converts text to a set of words
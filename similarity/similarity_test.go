@@ -0,0 +1,93 @@
+package similarity
+
+import (
+	"reflect"
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestDeduplicateCoursesStableOrder(t *testing.T) {
+	courses := []database.Course{
+		{URL: "https://www.udemy.com/course/rust/", Title: "Rust Systems Programming", QualityScore: 70},
+		{URL: "https://www.udemy.com/course/go/", Title: "Go Web Services", QualityScore: 70},
+		{URL: "https://www.udemy.com/course/sql/", Title: "SQL Query Tuning", QualityScore: 85},
+		{URL: "https://www.udemy.com/course/css/", Title: "CSS Grid Layouts", QualityScore: 70},
+	}
+	want := []string{
+		"https://www.udemy.com/course/sql/",
+		"https://www.udemy.com/course/css/",
+		"https://www.udemy.com/course/go/",
+		"https://www.udemy.com/course/rust/",
+	}
+
+	for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {2, 0, 3, 1}} {
+		var input []database.Course
+		for _, i := range order {
+			input = append(input, courses[i])
+		}
+		got := survivorURLs(New(0.85).DeduplicateCourses(input))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("input order %v kept %v, want %v", order, got, want)
+		}
+	}
+}
+
+func TestCollapseSharedCoupons(t *testing.T) {
+	direct := database.Course{
+		URL:          "https://www.udemy.com/course/python-bootcamp/?couponCode=SPRING24",
+		Title:        "Python Bootcamp: Zero to Hero!",
+		QualityScore: 60,
+	}
+	wrapped := database.Course{
+		URL:          "https://click.linksynergy.com/deeplink?murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fpython-bootcamp%2F%3FcouponCode%3Dspring24",
+		Title:        "python bootcamp zero to hero",
+		QualityScore: 75,
+	}
+	otherCourse := database.Course{
+		URL:          "https://www.udemy.com/course/excel-basics/?couponCode=SPRING24",
+		Title:        "Excel Basics",
+		QualityScore: 50,
+	}
+	otherCode := database.Course{
+		URL:          "https://www.udemy.com/course/python-bootcamp/?couponCode=SUMMER24",
+		Title:        "Python Bootcamp: Zero to Hero",
+		QualityScore: 55,
+	}
+
+	got := survivorURLs(New(0.85).collapseSharedCoupons([]database.Course{direct, otherCourse, wrapped, otherCode}))
+	want := []string{wrapped.URL, otherCourse.URL, otherCode.URL}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collapsed to %v, want %v", got, want)
+	}
+}
+
+func TestFindBestCoursePrefersFreeOnQualityTie(t *testing.T) {
+	free := &database.Course{URL: "https://www.udemy.com/course/go/?couponCode=FREE", Title: "Go Basics",
+		Price: "Free", Discount: "100% off", QualityScore: 70, Rating: 4.2}
+	paid := &database.Course{URL: "https://www.udemy.com/course/go/?couponCode=HALF", Title: "Go Basics",
+		Price: "$9.99", Discount: "85% off", QualityScore: 70, Rating: 4.6}
+
+	se := New(0.85)
+	for _, pair := range [][2]*database.Course{{free, paid}, {paid, free}} {
+		if best := se.FindBestCourse(pair[0], pair[1]); best != free {
+			t.Errorf("FindBestCourse(%s, %s) kept %s, want the free listing", pair[0].Price, pair[1].Price, best.Price)
+		}
+	}
+
+	deduplicated := se.DeduplicateCourses([]database.Course{*paid, *free})
+	if len(deduplicated) != 1 || deduplicated[0].Price != "Free" {
+		t.Errorf("dedup kept %+v, want only the free listing", deduplicated)
+	}
+
+	// With rating ranked first the price only breaks ties
+	if err := se.SetPriority([]string{PreferRating, PreferDiscount}); err != nil {
+		t.Fatal(err)
+	}
+	if best := se.FindBestCourse(free, paid); best != paid {
+		t.Error("rating-first priority kept the lower rated listing")
+	}
+	if err := se.SetPriority([]string{"cheapest"}); err == nil {
+		t.Error("SetPriority accepted an unknown criterion")
+	}
+}
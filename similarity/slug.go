@@ -0,0 +1,73 @@
+package similarity
+
+import (
+	"net/url"
+	"strings"
+
+	"udemy-course-notifier/database"
+)
+
+// SetSlugDedup enables collapsing listings that point at the same Udemy
+// course slug, whatever their titles say. This catches the same course
+// posted under translated titles, which share no words.
+func (se *SimilarityEngine) SetSlugDedup(enabled bool) {
+	se.slugDedup = enabled
+}
+
+// courseSlug returns the lowercased Udemy course slug from a course URL,
+// e.g. "complete-python" for https://www.udemy.com/course/complete-python/,
+// looking inside tracking links. It returns "" when the URL has no slug.
+func courseSlug(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	if murl := parsedURL.Query().Get("murl"); murl != "" {
+		if inner, err := url.Parse(murl); err == nil && inner.Host != "" {
+			parsedURL = inner
+		}
+	}
+
+	host := strings.ToLower(parsedURL.Hostname())
+	if host != "udemy.com" && !strings.HasSuffix(host, ".udemy.com") {
+		return ""
+	}
+
+	path := strings.Trim(parsedURL.Path, "/")
+	if !strings.HasPrefix(path, "course/") {
+		return ""
+	}
+	slug := strings.TrimPrefix(path, "course/")
+	if slug == "" || strings.Contains(slug, "/") {
+		return ""
+	}
+	return strings.ToLower(slug)
+}
+
+// collapseSharedSlugs merges listings of the same Udemy course slug, keeping
+// the best one. Courses without a slug are left alone.
+func (se *SimilarityEngine) collapseSharedSlugs(courses []database.Course) []database.Course {
+	var collapsed []database.Course
+	index := make(map[string]int)
+
+	for _, course := range courses {
+		slug := courseSlug(course.URL)
+		if slug == "" {
+			collapsed = append(collapsed, course)
+			continue
+		}
+
+		if i, ok := index[slug]; ok {
+			if better := se.FindBestCourse(&collapsed[i], &course); better == &course {
+				collapsed[i] = course
+			}
+			continue
+		}
+
+		index[slug] = len(collapsed)
+		collapsed = append(collapsed, course)
+	}
+
+	return collapsed
+}
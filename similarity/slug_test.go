@@ -0,0 +1,84 @@
+package similarity
+
+import (
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestCourseSlug(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.udemy.com/course/complete-python/", "complete-python"},
+		{"https://udemy.com/course/Complete-Python?couponCode=FREE", "complete-python"},
+		{"https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fcomplete-python%2F", "complete-python"},
+		{"https://www.udemy.com/course/complete-python/learn/lecture/1", ""},
+		{"https://www.udemy.com/courses/development/", ""},
+		{"https://www.udemy.com/course/", ""},
+		{"https://example.com/course/complete-python/", ""},
+		{"::not a url", ""},
+	}
+	for _, tt := range tests {
+		if got := courseSlug(tt.url); got != tt.want {
+			t.Errorf("courseSlug(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSlugDedupAcrossLanguages(t *testing.T) {
+	english := database.Course{
+		URL:          "https://www.udemy.com/course/complete-python/?couponCode=FIRST",
+		Title:        "Complete Python Course",
+		QualityScore: 60,
+	}
+	spanish := database.Course{
+		URL:          "https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fcomplete-python%2F%3FcouponCode%3DSECOND",
+		Title:        "Curso Completo de Python",
+		QualityScore: 80,
+	}
+	other := database.Course{
+		URL:          "https://www.udemy.com/course/python-bootcamp/",
+		Title:        "Data Science Bootcamp with Pandas",
+		QualityScore: 70,
+	}
+
+	tests := []struct {
+		name      string
+		slugDedup bool
+		want      []string
+	}{
+		{"collapses translated titles sharing a slug", true, []string{spanish.URL, other.URL}},
+		{"keeps them apart when off", false, []string{english.URL, spanish.URL, other.URL}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := New(0.85)
+			se.SetSlugDedup(tt.slugDedup)
+
+			got := survivorURLs(se.DeduplicateCourses([]database.Course{english, spanish, other}))
+
+			if !sameSet(got, tt.want) {
+				t.Errorf("kept %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// sameSet reports whether got and want hold the same strings, in any order
+func sameSet(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[string]bool)
+	for _, s := range got {
+		seen[s] = true
+	}
+	for _, s := range want {
+		if !seen[s] {
+			return false
+		}
+	}
+	return true
+}
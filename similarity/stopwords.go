@@ -0,0 +1,41 @@
+package similarity
+
+import "strings"
+
+// DefaultStopWords are common English words that say nothing about a
+// course's subject, so two titles sharing only these are not similar. Words
+// under three characters are ignored anyway and need not be listed.
+var DefaultStopWords = []string{
+	"the", "and", "for", "with", "from", "into", "onto", "your", "you",
+	"yours", "our", "how", "what", "why", "when", "who", "which", "all",
+	"are", "this", "that", "these", "those", "its", "can", "will", "get",
+	"use", "using", "one", "more", "about", "over", "out", "own", "any",
+	"step", "steps", "way", "ways", "new", "now", "just", "than", "then",
+}
+
+// SetStopWords adds words to DefaultStopWords; the combined set is dropped
+// from text before comparing, e.g. "und" and "für" for German titles
+func (se *SimilarityEngine) SetStopWords(extra []string) {
+	stopWords := make(map[string]bool, len(DefaultStopWords)+len(extra))
+	for _, word := range DefaultStopWords {
+		stopWords[word] = true
+	}
+	for _, word := range extra {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			stopWords[word] = true
+		}
+	}
+	se.stopWords = stopWords
+}
+
+// removeStopWords drops stop words from normalized, space-separated text
+func (se *SimilarityEngine) removeStopWords(text string) string {
+	words := strings.Fields(text)
+	kept := words[:0]
+	for _, word := range words {
+		if !se.stopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
@@ -0,0 +1,42 @@
+package similarity
+
+import "testing"
+
+func TestStopWordsOnlyOverlapScoresNearZero(t *testing.T) {
+	se := New(0.85)
+	a := "The Art of Baking Bread with Your Family"
+	b := "The Secrets of Investing with Your Money"
+
+	if got := se.calculateTextSimilarity(a, b); got > 0.05 {
+		t.Errorf("titles sharing only stop words scored %.2f, want near 0", got)
+	}
+
+	// Without stop words the shared "the", "with" and "your" inflate the score
+	se.stopWords = nil
+	if got := se.calculateTextSimilarity(a, b); got < 0.3 {
+		t.Errorf("without stop words the titles scored %.2f, expected the shared words to count", got)
+	}
+}
+
+func TestSetStopWordsAddsToDefaults(t *testing.T) {
+	a := "Kochen und Backen für Familien"
+	b := "Yoga und Meditation für Senioren"
+
+	se := New(0.85)
+	if got := se.calculateTextSimilarity(a, b); got == 0 {
+		t.Fatal("German filler words already ignored without configuring them")
+	}
+
+	se.SetStopWords([]string{" Und ", "FÜR", ""})
+	if got := se.calculateTextSimilarity(a, b); got != 0 {
+		t.Errorf("with German stop words the titles scored %.2f, want 0", got)
+	}
+	if !se.stopWords["the"] {
+		t.Error("SetStopWords dropped the default English stop words")
+	}
+
+	// Titles about the same subject still match once stop words are gone
+	if got := se.calculateTextSimilarity("Python for Data Analysis", "Python and Data Analysis with Your Team"); got < 0.7 {
+		t.Errorf("related titles scored %.2f, want them to stay similar", got)
+	}
+}
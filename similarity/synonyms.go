@@ -0,0 +1,89 @@
+package similarity
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultSynonyms maps common abbreviations and spellings to one canonical
+// token, so titles that name a technology differently still match. Canonical
+// tokens are at least three characters, since shorter words are ignored.
+var DefaultSynonyms = map[string]string{
+	"js":                          "javascript",
+	"ts":                          "typescript",
+	"node js":                     "nodejs",
+	"react js":                    "react",
+	"reactjs":                     "react",
+	"vue js":                      "vue",
+	"vuejs":                       "vue",
+	"k8s":                         "kubernetes",
+	"ml":                          "machinelearning",
+	"machine learning":            "machinelearning",
+	"ai":                          "artificialintelligence",
+	"artificial intelligence":     "artificialintelligence",
+	"dl":                          "deeplearning",
+	"deep learning":               "deeplearning",
+	"nlp":                         "naturallanguageprocessing",
+	"natural language processing": "naturallanguageprocessing",
+	"py":                          "python",
+	"postgres":                    "postgresql",
+	"gcp":                         "googlecloud",
+	"google cloud":                "googlecloud",
+	"ux":                          "userexperience",
+	"user experience":             "userexperience",
+}
+
+var synonymSeparator = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// SetSynonyms adds variant -> canonical token mappings to DefaultSynonyms.
+// Variants may be phrases of several words; they are matched case-insensitively
+// as whole words, longest first.
+func (se *SimilarityEngine) SetSynonyms(extra map[string]string) {
+	merged := make(map[string]string, len(DefaultSynonyms)+len(extra))
+	for variant, canonical := range DefaultSynonyms {
+		merged[variant] = canonical
+	}
+	for variant, canonical := range extra {
+		key := strings.Join(synonymWords(variant), " ")
+		token := strings.Join(synonymWords(canonical), "")
+		if key != "" && token != "" {
+			merged[key] = token
+		}
+	}
+
+	se.synonyms = merged
+	se.maxSynonymWords = 1
+	for variant := range merged {
+		if n := len(strings.Fields(variant)); n > se.maxSynonymWords {
+			se.maxSynonymWords = n
+		}
+	}
+}
+
+// applySynonyms replaces known variants in lowercase text with their
+// canonical tokens. Punctuation becomes word breaks, so "node.js" matches
+// "node js".
+func (se *SimilarityEngine) applySynonyms(text string) string {
+	words := synonymWords(text)
+	var out []string
+	for i := 0; i < len(words); {
+		matched := false
+		for n := min(se.maxSynonymWords, len(words)-i); n > 0; n-- {
+			if canonical, ok := se.synonyms[strings.Join(words[i:i+n], " ")]; ok {
+				out = append(out, canonical)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out = append(out, words[i])
+			i++
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func synonymWords(text string) []string {
+	return strings.Fields(synonymSeparator.ReplaceAllString(strings.ToLower(text), " "))
+}
@@ -0,0 +1,56 @@
+package similarity
+
+import (
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestApplySynonyms(t *testing.T) {
+	se := New(0.85)
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Complete JS Course", "complete javascript course"},
+		{"Node.js & K8s in Practice", "nodejs kubernetes in practice"},
+		{"Machine Learning A-Z", "machinelearning a z"},
+		{"JSX and JSON for beginners", "jsx and json for beginners"}, // Whole words only
+	}
+	for _, tt := range tests {
+		if got := se.applySynonyms(tt.text); got != tt.want {
+			t.Errorf("applySynonyms(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSynonymsDeduplicate(t *testing.T) {
+	course := func(slug, title string) database.Course {
+		return database.Course{URL: "https://www.udemy.com/course/" + slug + "/", Title: title, QualityScore: 60}
+	}
+
+	tests := []struct {
+		name     string
+		extra    map[string]string
+		a, b     database.Course
+		wantKept int
+	}{
+		{"default abbreviation", nil,
+			course("complete-js", "Complete JS Course"), course("complete-javascript", "Complete JavaScript Course"), 1},
+		{"default phrase", nil,
+			course("ml-bootcamp", "ML Bootcamp with Python"), course("machine-learning-bootcamp", "Machine Learning Bootcamp with Python"), 1},
+		{"operator addition missing", nil,
+			course("pbi-dashboards", "PBI Dashboards"), course("power-bi-dashboards", "Power BI Dashboards"), 2},
+		{"operator addition", map[string]string{"PBI": "Power BI", "Power BI": "Power BI"},
+			course("pbi-dashboards", "PBI Dashboards"), course("power-bi-dashboards", "Power BI Dashboards"), 1},
+		{"different subjects", nil,
+			course("complete-js", "Complete JS Course"), course("complete-ts", "Complete TS Course"), 2},
+	}
+	for _, tt := range tests {
+		se := New(0.85)
+		se.SetSynonyms(tt.extra)
+		if kept := len(se.DeduplicateCourses([]database.Course{tt.a, tt.b})); kept != tt.wantKept {
+			t.Errorf("%s: %q and %q kept %d courses, want %d", tt.name, tt.a.Title, tt.b.Title, kept, tt.wantKept)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package similarity
+
+import (
+	"sort"
+	"strings"
+
+	"udemy-course-notifier/database"
+)
+
+// SetWindow bounds how many courses DeduplicateCourses compares pairwise at
+// once. Larger batches are deduplicated a window at a time; across windows,
+// only courses whose titles have the same fingerprint are merged, so near
+// duplicates that land in different windows may both survive. 0 compares
+// the whole batch.
+func (se *SimilarityEngine) SetWindow(size int) {
+	if size >= 0 {
+		se.window = size
+	}
+}
+
+// dedupWindowed deduplicates courses one window at a time. Each window is
+// compared pairwise; a course whose title fingerprint matches a survivor of
+// an earlier window is merged into it instead. Only the fingerprints are
+// kept between windows, so comparisons stay within one window.
+func (se *SimilarityEngine) dedupWindowed(courses []database.Course) []database.Course {
+	var survivors []database.Course
+	seen := make(map[string]int) // Title fingerprint -> index in survivors
+
+	for start := 0; start < len(courses); start += se.window {
+		end := min(start+se.window, len(courses))
+
+		var remaining []database.Course
+		for _, course := range courses[start:end] {
+			fingerprint := se.titleFingerprint(course.Title)
+			i, found := seen[fingerprint]
+			if !found || fingerprint == "" {
+				remaining = append(remaining, course)
+				continue
+			}
+			if better := se.FindBestCourse(&survivors[i], &course); better == &course {
+				survivors[i] = course
+			}
+		}
+
+		for _, course := range se.dedupPass(remaining) {
+			fingerprint := se.titleFingerprint(course.Title)
+			if _, found := seen[fingerprint]; !found && fingerprint != "" {
+				seen[fingerprint] = len(survivors)
+			}
+			survivors = append(survivors, course)
+		}
+	}
+
+	return survivors
+}
+
+// titleFingerprint reduces a title to its normalized words in sorted order,
+// so titles differing only in case, punctuation, filler words or word order
+// share a fingerprint
+func (se *SimilarityEngine) titleFingerprint(title string) string {
+	var words []string
+	for word := range se.getWordSet(se.normalizeText(title)) {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
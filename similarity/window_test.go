@@ -0,0 +1,125 @@
+package similarity
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+// largeBatch builds families of listings of the same course, with titles
+// varying only in case, punctuation and word order, shuffled so members of
+// a family are spread over many windows
+func largeBatch(families, perFamily int) []database.Course {
+	var courses []database.Course
+	for f := 0; f < families; f++ {
+		words := []string{fmt.Sprintf("topic%d", f), fmt.Sprintf("subject%d", f), "fundamentals"}
+		for m := 0; m < perFamily; m++ {
+			title := fmt.Sprintf("%s %s %s", words[m%3], words[(m+1)%3], words[(m+2)%3])
+			if m%2 == 1 {
+				title = "The Complete " + title + "!"
+			}
+			courses = append(courses, database.Course{
+				URL:          fmt.Sprintf("https://www.udemy.com/course/f%d-m%d/", f, m),
+				Title:        title,
+				Category:     "Development",
+				Rating:       4.5,
+				QualityScore: float64((f*7 + m*13) % 97),
+			})
+		}
+	}
+
+	rand.New(rand.NewSource(1)).Shuffle(len(courses), func(i, j int) {
+		courses[i], courses[j] = courses[j], courses[i]
+	})
+	return courses
+}
+
+func survivorURLs(courses []database.Course) []string {
+	urls := make([]string, len(courses))
+	for i, course := range courses {
+		urls[i] = course.URL
+	}
+	return urls
+}
+
+func TestWindowedDedupMatchesWholeBatch(t *testing.T) {
+	courses := largeBatch(200, 3)
+
+	whole := New(0.85)
+	want := whole.DeduplicateCourses(append([]database.Course(nil), courses...))
+	if len(want) != 200 {
+		t.Fatalf("whole batch kept %d courses, want 200", len(want))
+	}
+
+	for _, window := range []int{50, 128, 400} {
+		windowed := New(0.85)
+		windowed.SetWindow(window)
+		got := windowed.DeduplicateCourses(append([]database.Course(nil), courses...))
+		if !reflect.DeepEqual(survivorURLs(got), survivorURLs(want)) {
+			t.Errorf("window %d kept %d courses, want the %d the whole batch keeps", window, len(got), len(want))
+		}
+	}
+}
+
+func TestTitleFingerprint(t *testing.T) {
+	se := New(0.85)
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"Python for Data Science", "python for data science!", true},
+		{"Python for Data Science", "Data Science for Python", true},
+		{"The Complete Python Bootcamp 2024", "Python", true},
+		{"Python for Data Science", "Python for Web Development", false},
+	}
+
+	for _, tt := range tests {
+		if same := se.titleFingerprint(tt.a) == se.titleFingerprint(tt.b); same != tt.same {
+			t.Errorf("fingerprints of %q and %q equal = %v, want %v", tt.a, tt.b, same, tt.same)
+		}
+	}
+}
+
+func TestWindowedDedupNearDuplicates(t *testing.T) {
+	course := func(slug, title string) database.Course {
+		return database.Course{
+			URL:      "https://www.udemy.com/course/" + slug + "/",
+			Title:    title,
+			Category: "Development",
+			Rating:   4.5,
+		}
+	}
+	// Similar enough to merge, but the extra word gives them different
+	// fingerprints
+	ml := course("ml", "Python for Data Science and Machine Learning")
+	mlProjects := course("ml-projects", "Python for Data Science and Machine Learning Projects")
+	rust := course("rust", "Rust Ownership Explained")
+	sql := course("sql", "SQL Window Functions")
+
+	tests := []struct {
+		name    string
+		window  int
+		courses []database.Course
+		want    int
+	}{
+		{"whole batch merges them", 0, []database.Course{ml, rust, sql, mlProjects}, 3},
+		{"same window merges them", 2, []database.Course{ml, mlProjects, rust, sql}, 3},
+		// The documented weaker guarantee: across windows only matching
+		// fingerprints are merged
+		{"different windows keep both", 2, []database.Course{ml, rust, sql, mlProjects}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := New(0.85)
+			se.SetWindow(tt.window)
+			got := se.DeduplicateCourses(append([]database.Course(nil), tt.courses...))
+			if len(got) != tt.want {
+				t.Errorf("kept %v, want %d courses", survivorURLs(got), tt.want)
+			}
+		})
+	}
+}
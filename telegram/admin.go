@@ -0,0 +1,242 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/scraper"
+	"udemy-course-notifier/security"
+)
+
+// SetAdminIDs configures which Telegram users may run operator commands
+func (b *Bot) SetAdminIDs(ids []int64) {
+	b.adminIDs = make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		b.adminIDs[id] = true
+	}
+}
+
+func (b *Bot) isAdmin(userID int64) bool {
+	return b.adminIDs[userID]
+}
+
+// requireAdmin replies with a refusal and returns false for non-admin users
+func (b *Bot) requireAdmin(message *tgbotapi.Message) bool {
+	if b.isAdmin(message.From.ID) {
+		return true
+	}
+	b.sendMessage(message.Chat.ID, "⛔ This command is only available to administrators.")
+	return false
+}
+
+func (b *Bot) handleTrendsCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	historyDays, err := b.db.CourseHistoryDays()
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load category trends.")
+		log.Printf("Failed to get course history: %v", err)
+		return
+	}
+
+	trends, err := b.db.CategoryTrends(30)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load category trends.")
+		log.Printf("Failed to get category trends: %v", err)
+		return
+	}
+
+	if len(trends) == 0 {
+		b.sendMessage(message.Chat.ID, "📈 Not enough data yet. Trends will appear once courses have been collected.")
+		return
+	}
+
+	f := b.format
+	text := "📈 " + f.bold("Category Trends") + "\n\n" + f.pre(formatTrendsTable(trends, historyDays >= 14))
+	if historyDays < 30 {
+		text += "\n" + f.italic(fmt.Sprintf("Only %.0f days of history so far; counts will stabilize over time.", historyDays))
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ParseMode = f.mode
+	b.send(msg)
+}
+
+// formatTrendsTable renders trends as a ranked table, for a monospace block.
+// The week-over-week column is only meaningful with two full weeks of history.
+func formatTrendsTable(trends []database.CategoryTrend, showChange bool) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-3s %-20s %4s %4s %6s\n", "#", "Category", "30d", "7d", "WoW"))
+
+	for i, t := range trends {
+		if i >= 20 {
+			break
+		}
+
+		category := t.Category
+		if len([]rune(category)) > 20 {
+			category = string([]rune(category)[:19]) + "…"
+		}
+
+		change := "n/a"
+		if showChange {
+			change = fmt.Sprintf("%+d", t.ThisWeek-t.LastWeek)
+		}
+
+		sb.WriteString(fmt.Sprintf("%-3d %-20s %4d %4d %6s\n", i+1, category, t.Count, t.ThisWeek, change))
+	}
+
+	return sb.String()
+}
+
+// SetQualityScorer sets the scorer used by /rescore and "Why this score?"
+func (b *Bot) SetQualityScorer(scorer *scraper.QualityScorer) {
+	b.scorer = scorer
+}
+
+// SetSourceTrust sets the per-source score multipliers /rescore applies to
+// courses by the source they were scraped from
+func (b *Bot) SetSourceTrust(trust map[string]float64) {
+	b.sourceTrust = trust
+}
+
+func (b *Bot) handleRescoreCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.scorer == nil {
+		b.sendMessage(message.Chat.ID, "❌ No quality scorer configured.")
+		return
+	}
+
+	changed, err := b.db.RescoreCourses(func(course *database.Course) float64 {
+		score := b.scorer.Score(course.Rating, course.StudentCount, course.Title, course.Description)
+		return scraper.TrustedScore(score, b.sourceTrust[course.SourceURL])
+	})
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to rescore courses.")
+		log.Printf("Failed to rescore courses: %v", err)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Rescore complete: %d courses updated.", changed))
+}
+
+const (
+	recategorizeUsage = "Usage: /recategorize <course ID> <category>\nExample: /recategorize 42 IT & Software"
+	maxCategoryLength = 50
+)
+
+// handleRecategorizeCommand corrects the category of a course that was
+// inferred wrongly
+func (b *Bot) handleRecategorizeCommand(message *tgbotapi.Message, args string) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		b.sendMessage(message.Chat.ID, recategorizeUsage)
+		return
+	}
+
+	courseID, err := strconv.Atoi(fields[0])
+	if err != nil || courseID <= 0 {
+		b.sendMessage(message.Chat.ID, "❌ Invalid course ID.\n"+recategorizeUsage)
+		return
+	}
+
+	category := security.SanitizeString(strings.Join(fields[1:], " "))
+	if category == "" || len([]rune(category)) > maxCategoryLength {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ The category must be 1 to %d characters.", maxCategoryLength))
+		return
+	}
+
+	course, err := b.db.GetCourse(courseID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Course #%d not found.", courseID))
+		return
+	}
+
+	found, err := b.db.UpdateCourseCategory(courseID, category)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to update the category.")
+		log.Printf("Failed to recategorize course %d: %v", courseID, err)
+		return
+	}
+	if !found {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Course #%d not found.", courseID))
+		return
+	}
+
+	log.Printf("Admin %d recategorized course %d from %q to %q", message.From.ID, courseID, course.Category, category)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Course #%d moved from %s to %s.", courseID, course.Category, category))
+}
+
+// SetSourceTracker sets the source health data shown by /sourcestatus
+func (b *Bot) SetSourceTracker(tracker *scraper.SourceTracker, sourceURLs []string) {
+	b.sourceTracker = tracker
+	b.sourceURLs = sourceURLs
+}
+
+func (b *Bot) handleSourceStatusCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.sourceTracker == nil || len(b.sourceURLs) == 0 {
+		b.sendMessage(message.Chat.ID, "No sources configured.")
+		return
+	}
+
+	couponStats, err := b.db.GetCouponStats()
+	if err != nil {
+		log.Printf("Failed to get coupon stats: %v", err)
+	}
+
+	now := time.Now()
+	var sb strings.Builder
+	sb.WriteString("🌐 Source Status\n")
+
+	for _, state := range b.sourceTracker.Snapshot(b.sourceURLs) {
+		sb.WriteString("\n" + state.URL + "\n")
+
+		if state.LastScrape.IsZero() {
+			sb.WriteString("   Last scrape: never\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("   Last scrape: %s ago (%d courses)\n",
+				now.Sub(state.LastScrape).Round(time.Second), state.LastCount))
+		}
+		sb.WriteString(fmt.Sprintf("   Zero streak: %d | Error streak: %d\n", state.ZeroStreak, state.ErrorStreak))
+
+		circuit := state.CircuitState(now, b.sourceTracker.Threshold())
+		icon := "🟢"
+		switch circuit {
+		case scraper.CircuitOpen:
+			icon = "🔴"
+		case scraper.CircuitHalfOpen:
+			icon = "🟡"
+		}
+		sb.WriteString(fmt.Sprintf("   Circuit: %s %s\n", icon, circuit))
+
+		stats := couponStats[state.URL]
+		if rate, ok := stats.ResolveRate(); ok {
+			sb.WriteString(fmt.Sprintf("   Coupons resolved: %d/%d (%.0f%%)\n",
+				stats.Resolved, stats.Attempts, rate*100))
+		}
+
+		if state.LastError != "" {
+			sb.WriteString("   Last error: " + state.LastError + "\n")
+		}
+	}
+
+	b.sendMessage(message.Chat.ID, sb.String())
+}
@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/scraper"
+)
+
+func TestRecategorizeCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const adminID, userID = 1, 42
+	b.SetAdminIDs([]int64{adminID})
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	reply := func(from int64, args string) string {
+		fake.reset()
+		b.handleRecategorizeCommand(testMessage(from, "/recategorize "+args), args)
+		texts := textsTo(fake.sent("sendMessage"), from)
+		if len(texts) != 1 {
+			t.Fatalf("/recategorize %s: sent %d replies, want 1", args, len(texts))
+		}
+		return texts[0]
+	}
+	category := func() string {
+		stored, err := b.db.GetCourse(course.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stored.Category
+	}
+
+	if text := reply(userID, fmt.Sprintf("%d Business", course.ID)); !strings.Contains(text, "only available to administrators") || category() != "Development" {
+		t.Errorf("non-admin got %q and category %q, want a refusal", text, category())
+	}
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "Usage"},
+		{fmt.Sprint(course.ID), "Usage"},
+		{"abc Business", "Invalid course ID"},
+		{"9999 Business", "Course #9999 not found"},
+		{fmt.Sprintf("%d %s", course.ID, strings.Repeat("x", maxCategoryLength+1)), "must be 1 to"},
+	}
+	for _, tt := range tests {
+		if text := reply(adminID, tt.args); !strings.Contains(text, tt.want) {
+			t.Errorf("/recategorize %q = %q, want %q", tt.args, text, tt.want)
+		}
+	}
+	if category() != "Development" {
+		t.Fatalf("category = %q after invalid commands, want it unchanged", category())
+	}
+
+	text := reply(adminID, fmt.Sprintf("%d IT & Software", course.ID))
+	if !strings.Contains(text, "moved from Development to IT & Software") || category() != "IT & Software" {
+		t.Errorf("reply %q and category %q, want the course moved to IT & Software", text, category())
+	}
+}
+
+func TestSourceStatusCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const adminID, userID = 1, 42
+	b.SetAdminIDs([]int64{adminID})
+
+	reply := func(from int64) string {
+		fake.reset()
+		b.handleMessage(testMessage(from, "/sourcestatus"))
+		texts := textsTo(fake.sent("sendMessage"), from)
+		if len(texts) != 1 {
+			t.Fatalf("/sourcestatus: sent %d replies, want 1", len(texts))
+		}
+		return texts[0]
+	}
+
+	if text := reply(adminID); !strings.Contains(text, "No sources configured") {
+		t.Errorf("without a tracker got %q, want no sources", text)
+	}
+
+	const idle, failing, healthy = "https://idle.example/", "https://failing.example/", "https://healthy.example/"
+	tracker := scraper.NewSourceTracker(2, time.Hour)
+	tracker.RecordFailure(failing, errors.New("connection refused"))
+	tracker.RecordFailure(failing, errors.New("connection refused"))
+	tracker.RecordSuccess(healthy, 5)
+	if err := b.db.RecordCouponFollows(healthy, 4, 3); err != nil {
+		t.Fatal(err)
+	}
+	b.SetSourceTracker(tracker, []string{idle, failing, healthy})
+
+	if text := reply(userID); !strings.Contains(text, "only available to administrators") {
+		t.Errorf("non-admin got %q, want a refusal", text)
+	}
+
+	text := reply(adminID)
+	sections := strings.Split(text, "\n\n")
+	if len(sections) != 4 {
+		t.Fatalf("got %q, want a header and one section per source", text)
+	}
+	tests := []struct {
+		section string
+		want    []string
+	}{
+		{sections[1], []string{idle, "Last scrape: never", "Circuit: 🟢 closed"}},
+		{sections[2], []string{failing, "Error streak: 2", "Circuit: 🔴 open", "Last error: connection refused"}},
+		{sections[3], []string{healthy, "(5 courses)", "Zero streak: 0 | Error streak: 0", "Circuit: 🟢 closed", "Coupons resolved: 3/4 (75%)"}},
+	}
+	for _, tt := range tests {
+		for _, want := range tt.want {
+			if !strings.Contains(tt.section, want) {
+				t.Errorf("section %q is missing %q", tt.section, want)
+			}
+		}
+	}
+	if strings.Contains(sections[1], "Coupons resolved") || strings.Contains(sections[3], "Last error") {
+		t.Errorf("got %q, want coupon and error lines only where there is data", text)
+	}
+}
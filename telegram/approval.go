@@ -0,0 +1,134 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// RequestApproval queues a stored course for the channel and sends it to
+// every admin with Approve and Reject buttons. Nothing is posted until an
+// admin approves it. Messages to admins are paced like other direct messages.
+func (b *Bot) RequestApproval(course *database.Course) error {
+	if err := b.db.QueueForApproval(course.ID); err != nil {
+		return err
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Approve", fmt.Sprintf("approve:%d", course.ID)),
+			tgbotapi.NewInlineKeyboardButtonData("🗑️ Reject", fmt.Sprintf("reject:%d", course.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("🔗 View Course", b.courseLink(course.URL)),
+		),
+	)
+
+	sent := 0
+	for adminID := range b.adminIDs {
+		msg := tgbotapi.NewMessage(adminID, b.formatCourseMessage(course, b.userLocation(adminID)))
+		msg.ParseMode = b.format.mode
+		msg.ReplyMarkup = keyboard
+		msg.DisableWebPagePreview = true
+		b.dms.wait()
+		sentMsg, err := b.send(msg)
+		if err != nil {
+			log.Printf("Failed to send course for approval to admin %d: %v", adminID, err)
+			continue
+		}
+		if err := b.db.RecordApprovalMessage(course.ID, adminID, sentMsg.MessageID); err != nil {
+			log.Printf("Failed to record approval message: %v", err)
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		return fmt.Errorf("no admin could be sent course %d for approval", course.ID)
+	}
+	return nil
+}
+
+// handleApprovalCallback posts an approved course to the channel or drops a
+// rejected one. Data is "approve:<course ID>" or "reject:<course ID>". Only
+// the first admin to act on a course takes effect. It returns the callback
+// answer, and the course if it was approved and posted.
+func (b *Bot) handleApprovalCallback(callback *tgbotapi.CallbackQuery, action, courseIDStr string) (string, *database.Course) {
+	if !b.isAdmin(callback.From.ID) || callback.Message == nil {
+		return "⛔ Only administrators can review courses", nil
+	}
+
+	courseID, err := strconv.Atoi(courseIDStr)
+	if err != nil {
+		return "", nil
+	}
+
+	pending, err := b.db.TakePendingApproval(courseID)
+	if err != nil {
+		log.Printf("Failed to take pending approval: %v", err)
+		return "❌ Failed, please try again", nil
+	}
+	if !pending {
+		b.appendCallbackStatus(callback, "Already reviewed")
+		return "This course was already reviewed", nil
+	}
+
+	course, err := b.db.GetCourse(courseID)
+	if action == "reject" {
+		b.appendCallbackStatus(callback, "🗑️ Rejected")
+		b.closeApprovalMessages(courseID, course, callback, "🗑️ Rejected by another admin")
+		return "Rejected", nil
+	}
+
+	if err == nil {
+		err = b.PostCourse(course)
+	}
+	if err != nil {
+		log.Printf("Failed to post approved course %d: %v", courseID, err)
+		// Keep it pending so the approval can be retried
+		if err := b.db.QueueForApproval(courseID); err != nil {
+			log.Printf("Failed to requeue course for approval: %v", err)
+		}
+		return "❌ Failed to post, please try again", nil
+	}
+
+	b.appendCallbackStatus(callback, "✅ Approved and posted")
+	b.closeApprovalMessages(courseID, course, callback, "✅ Approved by another admin")
+	return "Posted to the channel", course
+}
+
+// closeApprovalMessages updates the other admins' copies of a reviewed course
+// with status and removes their buttons. course may be nil if it could not be
+// loaded, in which case only the buttons are removed.
+func (b *Bot) closeApprovalMessages(courseID int, course *database.Course, callback *tgbotapi.CallbackQuery, status string) {
+	messages, err := b.db.TakeApprovalMessages(courseID)
+	if err != nil {
+		log.Printf("Failed to get approval messages for course %d: %v", courseID, err)
+		return
+	}
+
+	for _, m := range messages {
+		if m.ChatID == callback.Message.Chat.ID && m.MessageID == callback.Message.MessageID {
+			continue
+		}
+
+		var edit tgbotapi.Chattable
+		if course != nil {
+			text := tgbotapi.NewEditMessageText(m.ChatID, m.MessageID,
+				b.formatCourseMessage(course, b.userLocation(m.ChatID))+"\n\n"+b.format.bold(status))
+			text.ParseMode = b.format.mode
+			text.DisableWebPagePreview = true
+			edit = text
+		} else {
+			edit = tgbotapi.NewEditMessageReplyMarkup(m.ChatID, m.MessageID,
+				tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+		}
+
+		b.dms.wait()
+		if _, err := b.send(edit); err != nil {
+			log.Printf("Failed to update approval message for admin %d: %v", m.ChatID, err)
+		}
+	}
+}
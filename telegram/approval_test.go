@@ -0,0 +1,158 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/filters"
+)
+
+const firstAdminID, secondAdminID = 1, 2
+
+// newApprovalBot returns a bot with two admins and a course sent to both
+// for approval
+func newApprovalBot(t *testing.T) (*Bot, *fakeTelegram, database.Course) {
+	t.Helper()
+	b, fake := newTestBot(t)
+	b.SetAdminIDs([]int64{firstAdminID, secondAdminID})
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	if err := b.RequestApproval(&course); err != nil {
+		t.Fatal(err)
+	}
+	for _, adminID := range []int64{firstAdminID, secondAdminID} {
+		if texts := textsTo(fake.sent("sendMessage"), adminID); len(texts) != 1 || !strings.Contains(texts[0], course.Title) {
+			t.Fatalf("admin %d was sent %q, want the course for review", adminID, texts)
+		}
+	}
+	fake.reset()
+	return b, fake, course
+}
+
+// review taps action on the copy of course sent to adminID and returns the
+// callback answer
+func review(t *testing.T, b *Bot, fake *fakeTelegram, adminID int64, action string, course database.Course) string {
+	t.Helper()
+	var messageID int
+	err := b.db.QueryRow(`SELECT message_id FROM approval_messages WHERE course_id = ? AND chat_id = ?`,
+		course.ID, adminID).Scan(&messageID)
+	if err != nil {
+		messageID = 1000 // The copy was already closed
+	}
+
+	fake.reset()
+	b.handleCallbackQuery(&tgbotapi.CallbackQuery{
+		ID:      action,
+		From:    &tgbotapi.User{ID: adminID},
+		Message: &tgbotapi.Message{MessageID: messageID, Chat: &tgbotapi.Chat{ID: adminID}, Text: course.Title},
+		Data:    fmt.Sprintf("%s:%d", action, course.ID),
+	})
+	answers := fake.sent("answerCallbackQuery")
+	if len(answers) != 1 {
+		t.Fatalf("%s answered %d times, want once", action, len(answers))
+	}
+	return answers[0].Params.Get("text")
+}
+
+func TestApproveCourse(t *testing.T) {
+	b, fake, course := newApprovalBot(t)
+
+	if answer := review(t, b, fake, firstAdminID, "approve", course); answer != "Posted to the channel" {
+		t.Errorf("approve answered %q", answer)
+	}
+	if posts := textsTo(fake.sent("sendMessage"), testChannelID); len(posts) != 1 {
+		t.Errorf("posted %d messages to the channel, want 1", len(posts))
+	}
+	edits := textsTo(fake.sent("editMessageText"), secondAdminID)
+	if len(edits) != 1 || !strings.Contains(edits[0], "Approved by another admin") {
+		t.Errorf("second admin's copy edited to %q, want it marked approved", edits)
+	}
+
+	if answer := review(t, b, fake, secondAdminID, "approve", course); !strings.Contains(answer, "already reviewed") {
+		t.Errorf("second approval answered %q, want already reviewed", answer)
+	}
+	if posts := textsTo(fake.sent("sendMessage"), testChannelID); len(posts) != 0 {
+		t.Errorf("second approval posted %d more messages", len(posts))
+	}
+}
+
+func TestRejectCourse(t *testing.T) {
+	b, fake, course := newApprovalBot(t)
+
+	if answer := review(t, b, fake, secondAdminID, "reject", course); answer != "Rejected" {
+		t.Errorf("reject answered %q", answer)
+	}
+	if posts := textsTo(fake.sent("sendMessage"), testChannelID); len(posts) != 0 {
+		t.Errorf("rejected course was posted")
+	}
+	edits := textsTo(fake.sent("editMessageText"), firstAdminID)
+	if len(edits) != 1 || !strings.Contains(edits[0], "Rejected by another admin") {
+		t.Errorf("first admin's copy edited to %q, want it marked rejected", edits)
+	}
+
+	if answer := review(t, b, fake, firstAdminID, "approve", course); !strings.Contains(answer, "already reviewed") {
+		t.Errorf("approving a rejected course answered %q, want already reviewed", answer)
+	}
+	if posts := textsTo(fake.sent("sendMessage"), testChannelID); len(posts) != 0 {
+		t.Errorf("rejected course was posted after a late approval")
+	}
+}
+
+func TestApprovalByNonAdmin(t *testing.T) {
+	b, fake, course := newApprovalBot(t)
+	const userID = 42
+
+	if answer := review(t, b, fake, userID, "approve", course); !strings.Contains(answer, "Only administrators") {
+		t.Errorf("non-admin approval answered %q, want a refusal", answer)
+	}
+	if answer := review(t, b, fake, firstAdminID, "approve", course); answer != "Posted to the channel" {
+		t.Errorf("admin approval after a refused one answered %q, want it posted", answer)
+	}
+}
+
+func TestApprovalRetriedAfterFailedPost(t *testing.T) {
+	b, fake, course := newApprovalBot(t)
+	fake.failWith(func(call apiCall) (int, string) {
+		if call.Method == "sendMessage" && call.Params.Get("chat_id") == fmt.Sprint(int64(testChannelID)) {
+			return 500, "Internal Server Error"
+		}
+		return 0, ""
+	})
+
+	if answer := review(t, b, fake, firstAdminID, "approve", course); !strings.Contains(answer, "Failed to post") {
+		t.Errorf("approval with a failing channel answered %q", answer)
+	}
+
+	fake.failWith(nil)
+	if answer := review(t, b, fake, firstAdminID, "approve", course); answer != "Posted to the channel" {
+		t.Errorf("retried approval answered %q, want it posted", answer)
+	}
+}
+
+func TestSubscribersHearOnlyOfApprovedCourses(t *testing.T) {
+	const subscriberID = 42
+	b, fake, rejected := newApprovalBot(t)
+	if err := b.filterEngine.SaveUserFilter(&filters.UserFilter{UserID: subscriberID}); err != nil {
+		t.Fatal(err)
+	}
+
+	review(t, b, fake, firstAdminID, "reject", rejected)
+	if dms := textsTo(fake.sent("sendMessage"), subscriberID); len(dms) != 0 {
+		t.Errorf("subscriber was sent a rejected course: %q", dms)
+	}
+
+	approved := addTestCourse(t, b.db, "go-advanced", nil)
+	if err := b.RequestApproval(&approved); err != nil {
+		t.Fatal(err)
+	}
+	if dms := textsTo(fake.sent("sendMessage"), subscriberID); len(dms) != 0 {
+		t.Errorf("subscriber was sent a course still awaiting review: %q", dms)
+	}
+	review(t, b, fake, firstAdminID, "approve", approved)
+	if dms := textsTo(fake.sent("sendMessage"), subscriberID); len(dms) != 1 || !strings.Contains(dms[0], approved.Title) {
+		t.Errorf("subscriber was sent %q, want the approved course", dms)
+	}
+}
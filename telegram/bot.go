@@ -1,6 +1,8 @@
 package telegram
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -10,15 +12,36 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"udemy-course-notifier/database"
 	"udemy-course-notifier/filters"
+	"udemy-course-notifier/scraper"
 	"udemy-course-notifier/security"
 )
 
 type Bot struct {
 	api           *tgbotapi.BotAPI
 	db            *database.DB
-	channelID     string
+	channelID     int64 // Numeric chat ID, resolved from @username at startup
 	filterEngine  *filters.FilterEngine
 	awaitingInput map[int64]string // Track users awaiting filter input
+	wizards       map[int64]*filterWizard // In-progress /filterwizard setups
+	adminIDs      map[int64]bool   // Users allowed to run operator commands
+	scorer        *scraper.QualityScorer
+	sourceTrust   map[string]float64 // Score multipliers by source URL, for /rescore
+	sourceTracker *scraper.SourceTracker
+	sourceURLs    []string
+	status        botStatus
+	location      *time.Location // Zone for times shown in the channel
+	format        formatter
+	quietMode     string // QuietHoursHold or QuietHoursDrop
+	commandLimiter *commandLimiter // Per-user command rate limit; nil when disabled
+	remindAllLead time.Duration   // How far ahead /remindall digests look
+	previewChannelID int64        // Receives course posts instead of channelID when non-zero
+	dailyLimitMode string         // DailyLimitHold or DailyLimitDrop
+	referralCode   string         // Udemy affiliate code added to course links; empty when off
+	versionAdminOnly bool         // Whether /version is limited to admins
+	channel        channelHealth  // Pauses channel posts after the bot is removed
+	channelFailureLimit int       // Consecutive "bot removed" failures before pausing; 0 never pauses
+	hurryWindow    time.Duration  // Channel posts expiring sooner than this get a hurry banner; 0 is off
+	dms            *dmPacer       // Spaces out direct messages to subscribers
 }
 
 func New(token, channelID string, db *database.DB) (*Bot, error) {
@@ -29,32 +52,65 @@ func New(token, channelID string, db *database.DB) (*Bot, error) {
 
 	api.Debug = false
 
+	resolvedChannelID, err := resolveChannelID(api, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBot(api, resolvedChannelID, db), nil
+}
+
+// newBot creates a bot with default settings around a connected API client
+func newBot(api *tgbotapi.BotAPI, channelID int64, db *database.DB) *Bot {
 	return &Bot{
 		api:           api,
 		db:            db,
 		channelID:     channelID,
 		filterEngine:  filters.New(db),
 		awaitingInput: make(map[int64]string),
-	}, nil
+		wizards:       make(map[int64]*filterWizard),
+		location:      time.UTC,
+		format:        formatter{mode: tgbotapi.ModeMarkdown},
+		quietMode:     QuietHoursHold,
+		dailyLimitMode: DailyLimitHold,
+		remindAllLead: 24 * time.Hour,
+		adminIDs:      make(map[int64]bool),
+		channelFailureLimit: 3,
+		dms:           &dmPacer{interval: defaultDMInterval},
+	}
 }
 
 func (b *Bot) Start() error {
 	log.Printf("Authorized on account %s", b.api.Self.UserName)
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	// Long-poll directly rather than through GetUpdatesChan, which retries
+	// every 3 seconds on its own and so never lets the backoff apply
+	offset := 0
+	attempt := 0
+	for {
+		u := tgbotapi.NewUpdate(offset)
+		u.Timeout = 60
+
+		updates, err := b.api.GetUpdates(u)
+		if err != nil {
+			attempt++
+			delay := reconnectDelay(attempt)
+			log.Printf("Failed to get Telegram updates: %v; retrying in %s (attempt %d)", err, delay.Round(time.Millisecond), attempt)
+			time.Sleep(delay)
+			continue
+		}
+		attempt = 0 // Connection is healthy again
 
-	updates := b.api.GetUpdatesChan(u)
+		for _, update := range updates {
+			offset = update.UpdateID + 1
 
-	for update := range updates {
-		if update.Message != nil {
-			b.handleMessage(update.Message)
-		} else if update.CallbackQuery != nil {
-			b.handleCallbackQuery(update.CallbackQuery)
+			if update.Message != nil {
+				b.handleMessage(update.Message)
+			} else if update.CallbackQuery != nil {
+				b.handleCallbackQuery(update.CallbackQuery)
+			}
 		}
 	}
-
-	return nil
 }
 
 func (b *Bot) handleMessage(message *tgbotapi.Message) {
@@ -66,7 +122,15 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 		return
 	}
 
-	if !message.IsCommand() {
+	if !message.IsCommand() || addressedToOtherBot(message, b.api.Self.UserName) {
+		return
+	}
+
+	if allowed, warn := b.commandAllowed(userID); !allowed {
+		// Further commands in the same throttled stretch are dropped silently
+		if warn {
+			b.sendMessage(message.Chat.ID, "⏳ Please wait a moment before sending another command.")
+		}
 		return
 	}
 
@@ -75,15 +139,91 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 
 	switch command {
 	case "start":
-		b.handleStartCommand(message)
+		if isGroupChat(message.Chat) {
+			b.handleGroupStartCommand(message)
+		} else {
+			b.handleStartCommand(message)
+		}
+	case "stop":
+		b.handleStopCommand(message)
 	case "help":
 		b.handleHelpCommand(message)
 	case "filter":
-		b.handleFilterCommand(message, args)
+		if isGroupChat(message.Chat) {
+			b.handleGroupFilterCommand(message, args)
+		} else {
+			b.handleFilterCommand(message, args)
+		}
+	case "filterwizard":
+		if isGroupChat(message.Chat) {
+			b.sendMessage(message.Chat.ID, "In groups, admins set the filter with /filter <preferences>. /filterwizard works in a private chat with me.")
+		} else {
+			b.handleFilterWizardCommand(message)
+		}
 	case "wishlist":
 		b.handleWishlistCommand(message)
+	case "compare":
+		b.handleCompareCommand(message, args)
+	case "maxprice":
+		b.handleMaxPriceCommand(message, args)
+	case "setrating":
+		b.handleSetRatingCommand(message)
+	case "exportfilter":
+		b.handleExportFilterCommand(message)
+	case "importfilter":
+		b.handleImportFilterCommand(message, args)
 	case "stats":
 		b.handleStatsCommand(message)
+	case "timezone":
+		b.handleTimezoneCommand(message, args)
+	case "quiet":
+		b.handleQuietCommand(message, args)
+	case "remindall":
+		b.handleRemindAllCommand(message, args)
+	case "digestsort":
+		b.handleDigestSortCommand(message, args)
+	case "maxperday":
+		b.handleMaxPerDayCommand(message, args)
+	case "showexpired":
+		b.handleShowExpiredCommand(message, args)
+	case "certificate":
+		b.handleCertificateCommand(message, args)
+	case "pagesize":
+		b.handlePageSizeCommand(message, args)
+	case "resetignored":
+		b.handleResetIgnoredCommand(message)
+	case "ignored":
+		b.handleIgnoredCommand(message)
+	case "me":
+		b.handleMeCommand(message)
+	case "welcome":
+		b.handleWelcomeCommand(message, args)
+	case "sample":
+		b.handleSampleCommand(message)
+	case "status":
+		b.handleStatusCommand(message)
+	case "markread":
+		b.handleMarkReadCommand(message)
+	case "whoami":
+		b.handleWhoamiCommand(message)
+	case "version":
+		b.handleVersionCommand(message)
+	case "popular":
+		b.handlePopularCommand(message)
+	case "browse":
+		b.handleBrowseCommand(message, args)
+	case "trends":
+		b.handleTrendsCommand(message)
+	case "raw":
+		b.handleRawCommand(message, args)
+	case "recategorize":
+		b.handleRecategorizeCommand(message, args)
+	case "rescore":
+		b.handleRescoreCommand(message)
+	case "recheck":
+		b.handleRecheckCommand(message)
+	case "sourcestatus":
+		b.handleSourceStatusCommand(message)
 	default:
 		b.sendMessage(message.Chat.ID, "Unknown command. Use /help to see available commands.")
 	}
@@ -96,6 +236,45 @@ func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
 	}
 
 	action := parts[0]
+
+	// Rating choices carry a decimal value rather than a course
+	if action == "setrating" {
+		b.api.Request(tgbotapi.NewCallback(callback.ID, b.handleSetRatingCallback(callback, parts[1])))
+		return
+	}
+
+	if action == "fw" {
+		b.api.Request(tgbotapi.NewCallback(callback.ID, b.handleFilterWizardCallback(callback, parts[1:])))
+		return
+	}
+
+	if action == "resetignored" {
+		b.api.Request(tgbotapi.NewCallback(callback.ID, b.handleResetIgnoredCallback(callback, parts[1])))
+		return
+	}
+
+	// Approval decisions are checked against the admin list before the course
+	if action == "approve" || action == "reject" {
+		answer, approved := b.handleApprovalCallback(callback, action, parts[1])
+		b.api.Request(tgbotapi.NewCallback(callback.ID, answer))
+		// Subscribers hear of a course only once it's approved; the admin's
+		// tap is answered first, since paced DMs can take a while
+		if approved != nil {
+			b.NotifySubscribers(approved)
+		}
+		return
+	}
+
+	if action == "ignored" {
+		b.api.Request(tgbotapi.NewCallback(callback.ID, b.handleIgnoredPageCallback(callback, parts[1])))
+		return
+	}
+
+	if action == "unignore" {
+		b.api.Request(tgbotapi.NewCallback(callback.ID, b.handleUnignoreCallback(callback, parts)))
+		return
+	}
+
 	courseIDStr := parts[1]
 	courseID, err := strconv.Atoi(courseIDStr)
 	if err != nil {
@@ -103,6 +282,15 @@ func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
 	}
 
 	userID := callback.From.ID
+	answerText := ""
+	showAlert := false
+
+	// Browse pages carry an offset and category rather than a course
+	if action == "browse" {
+		b.handleBrowseCallback(callback, courseID, strings.Join(parts[2:], ":"))
+		b.api.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return
+	}
 
 	switch action {
 	case "ignore":
@@ -110,30 +298,27 @@ func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
 			log.Printf("Failed to ignore course: %v", err)
 			return
 		}
+		if err := b.db.AddFeedback(userID, courseID, -1); err != nil {
+			log.Printf("Failed to record feedback: %v", err)
+		}
 		
 		// Edit message to show it's been ignored
-		edit := tgbotapi.NewEditMessageText(
-			callback.Message.Chat.ID,
-			callback.Message.MessageID,
-			callback.Message.Text+"\n\n✅ *Marked as not interested*",
-		)
-		edit.ParseMode = "Markdown"
-		b.api.Send(edit)
+		b.appendCallbackStatus(callback, "✅ Marked as not interested")
 
 	case "wishlist":
 		if err := b.db.AddToWishlist(userID, courseID); err != nil {
 			log.Printf("Failed to add to wishlist: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				b.appendCallbackStatus(callback, "❌ This course is no longer available")
+			}
 			return
 		}
+		if err := b.db.AddFeedback(userID, courseID, 1); err != nil {
+			log.Printf("Failed to record feedback: %v", err)
+		}
 		
 		// Edit message to show it's been added to wishlist
-		edit := tgbotapi.NewEditMessageText(
-			callback.Message.Chat.ID,
-			callback.Message.MessageID,
-			callback.Message.Text+"\n\n⭐ *Added to wishlist*",
-		)
-		edit.ParseMode = "Markdown"
-		b.api.Send(edit)
+		b.appendCallbackStatus(callback, "⭐ Added to wishlist")
 
 	case "remove_wishlist":
 		if err := b.db.RemoveFromWishlist(userID, courseID); err != nil {
@@ -142,20 +327,44 @@ func (b *Bot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
 		}
 		
 		// Edit message to show it's been removed from wishlist
-		edit := tgbotapi.NewEditMessageText(
-			callback.Message.Chat.ID,
-			callback.Message.MessageID,
-			callback.Message.Text+"\n\n🗑️ *Removed from wishlist*",
-		)
-		edit.ParseMode = "Markdown"
-		b.api.Send(edit)
+		b.appendCallbackStatus(callback, "🗑️ Removed from wishlist")
+
+	case "snooze":
+		answerText = b.snoozeCourse(userID, courseID)
+
+	case "score_info":
+		answerText = b.scoreInfo(courseID)
+		showAlert = true
+	}
+
+	// Acting on a delivered course counts as reading it
+	if err := b.db.MarkRead(userID, courseID); err != nil {
+		log.Printf("Failed to mark course read: %v", err)
 	}
 
 	// Answer callback query to remove loading state
-	answer := tgbotapi.NewCallback(callback.ID, "")
+	answer := tgbotapi.NewCallback(callback.ID, answerText)
+	answer.ShowAlert = showAlert
 	b.api.Request(answer)
 }
 
+// appendCallbackStatus appends a status line to the message behind a callback.
+// Repeated taps on the same button leave the message unchanged.
+func (b *Bot) appendCallbackStatus(callback *tgbotapi.CallbackQuery, status string) {
+	if strings.Contains(callback.Message.Text, status) {
+		return
+	}
+
+	// Message.Text arrives without markup, so it is escaped like any other text
+	edit := tgbotapi.NewEditMessageText(
+		callback.Message.Chat.ID,
+		callback.Message.MessageID,
+		b.format.escape(callback.Message.Text)+"\n\n"+b.format.bold(status),
+	)
+	edit.ParseMode = b.format.mode
+	b.send(edit)
+}
+
 func (b *Bot) handleStartCommand(message *tgbotapi.Message) {
 	text := `Welcome to the Free Udemy Course Notifier! 🎓
 
@@ -163,6 +372,8 @@ I'll help you discover free Udemy courses based on your interests.
 
 Available commands:
 /filter - Set your course preferences
+/filterwizard - Set them up step by step with buttons
+/welcome - Get the latest courses matching your filter now
 /wishlist - View your saved courses
 /stats - View your activity stats
 /help - Show this help message
@@ -175,29 +386,56 @@ You can also use the buttons on course messages to:
 }
 
 func (b *Bot) handleHelpCommand(message *tgbotapi.Message) {
-	text := `📚 *Free Udemy Course Notifier Help*
-
-*Commands:*
-/start - Welcome message and setup
+	commands := `/start - Welcome message and setup
 /filter - Configure your course preferences
+/filterwizard - Step-by-step filter setup with buttons
+/stop - Stop receiving courses (in groups, unsubscribes the group)
+/welcome [count] - Get recent courses matching your filter
+/maxprice <amount> [currency] - Hide paid courses above a price
+/setrating - Pick a minimum course rating
+/exportfilter - Get a code to share your filter
+/importfilter <code> - Apply a filter someone shared
 /wishlist - View courses you've saved
+/compare <id> <id> - Compare two wishlist courses
+/remindall on|off - Daily reminder of expiring wishlist courses
+/digestsort <order> - Sort digests by expiry, quality, rating or newest
 /stats - See your activity statistics
-/help - Show this help message
-
-*How it works:*
-1. I monitor public sources for free Udemy courses
+/popular - Courses other users liked this week
+/browse <category> - Browse stored courses in a category
+/showexpired on|off - Include expired courses in /browse and /popular
+/certificate on|off - Only get courses with a certificate of completion
+/pagesize <count> - Set how many items lists show per page
+/ignored - Review courses you marked Not Interested
+/resetignored - Let courses you marked Not Interested be sent again
+/timezone <zone> - Show times in your timezone
+/quiet <start> <end> - Pause notifications overnight
+/maxperday <count> - Limit how many courses you get a day
+/me - Review all of your preferences in one place
+/sample - Get a sample notification to check messages reach you
+/status - Check that the bot is running and when it last scanned
+/markread - Clear your unread course count
+/whoami - Show your user ID and this chat's ID
+/version - Show which build of the bot is running
+/help - Show this help message`
+
+	howItWorks := `1. I monitor public sources for free Udemy courses
 2. I filter courses based on your preferences
 3. You get notified about relevant courses
-4. Use buttons to save or ignore courses
+4. Use buttons to save or ignore courses`
 
-*Tips:*
-• Set up your preferences with /filter for better recommendations
+	tips := `• Set up your preferences with /filter for better recommendations
 • Use the wishlist to save interesting courses for later
 • Mark courses as "not interested" to improve future suggestions`
 
+	f := b.format
+	text := "📚 " + f.bold("Free Udemy Course Notifier Help") + "\n\n" +
+		f.bold("Commands:") + "\n" + f.escape(commands) + "\n\n" +
+		f.bold("How it works:") + "\n" + f.escape(howItWorks) + "\n\n" +
+		f.bold("Tips:") + "\n" + f.escape(tips)
+
 	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ParseMode = "Markdown"
-	b.api.Send(msg)
+	msg.ParseMode = f.mode
+	b.send(msg)
 }
 
 func (b *Bot) handleFilterCommand(message *tgbotapi.Message, args string) {
@@ -208,26 +446,24 @@ func (b *Bot) handleFilterCommand(message *tgbotapi.Message, args string) {
 	}
 
 	// Request filter input from user
-	text := `🎯 *Course Filter Settings*
-
-Please send your preferences in this format:
-` + "`Categories | MinRating | Keywords | ExcludedKeywords`" + `
-
-*Example:*
-` + "`Development, Business | 4.0 | programming, web | crypto, trading`" + `
-
-*Categories:* Development, Business, Design, Marketing, IT & Software, etc.
-*MinRating:* 0.0 to 5.0
-*Keywords:* Topics you want (comma-separated)
-*ExcludedKeywords:* Topics to avoid (comma-separated)
-
-Send your preferences now:`
+	f := b.format
+	text := "🎯 " + f.bold("Course Filter Settings") + "\n\n" +
+		f.escape("Please send your preferences in this format:\n") +
+		f.code("Categories | MinRating | Keywords | ExcludedKeywords | Captions") + "\n\n" +
+		f.bold("Example:") + "\n" +
+		f.code("Development, Business | 4.0 | programming, web | crypto, trading | es") + "\n\n" +
+		f.bold("Categories:") + f.escape(" Development, Business, Design, Marketing, IT & Software, etc.\n") +
+		f.bold("MinRating:") + f.escape(" 0.0 to 5.0\n") +
+		f.bold("Keywords:") + f.escape(" Topics you want (comma-separated); any one is enough, or prefix with + to require it (e.g. +python, +data)\n") +
+		f.bold("ExcludedKeywords:") + f.escape(" Topics to avoid (comma-separated)\n") +
+		f.bold("Captions:") + f.escape(" Optional caption language the course must offer (e.g. en, es)\n\n") +
+		f.escape("Send your preferences now:")
 
 	b.awaitingInput[message.From.ID] = "filter"
 	
 	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ParseMode = "Markdown"
-	b.api.Send(msg)
+	msg.ParseMode = f.mode
+	b.send(msg)
 }
 
 func (b *Bot) handleFilterInput(message *tgbotapi.Message, inputType string) {
@@ -236,6 +472,8 @@ func (b *Bot) handleFilterInput(message *tgbotapi.Message, inputType string) {
 
 	if inputType == "filter" {
 		b.processFilterInput(userID, message.Chat.ID, message.Text)
+	} else if inputType == "wizard" {
+		b.handleWizardInput(message)
 	}
 }
 
@@ -255,23 +493,35 @@ func (b *Bot) processFilterInput(userID int64, chatID int64, input string) {
 		return
 	}
 
-	text := fmt.Sprintf(`✅ *Filter preferences saved!*
+	b.sendFilterSaved(chatID, userFilter)
+}
 
-📂 Categories: %v
+// sendFilterSaved confirms a saved filter by listing what it matches
+func (b *Bot) sendFilterSaved(chatID int64, userFilter *filters.UserFilter) {
+	captionStatus := "any"
+	if userFilter.CaptionLanguage != "" {
+		captionStatus = userFilter.CaptionLanguage
+	}
+
+	details := fmt.Sprintf(`📂 Categories: %v
 ⭐ Min Rating: %.1f
-🔍 Keywords: %v
+🔍 Keywords (any): %v
+➕ Required (all): %v
 ❌ Excluded: %v
+💬 Captions: %s
 
 You'll now receive notifications for courses matching these criteria.`,
 		userFilter.Categories,
 		userFilter.MinRating,
 		userFilter.Keywords,
+		userFilter.RequiredKeywords,
 		userFilter.ExcludedKeywords,
+		captionStatus,
 	)
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	b.api.Send(msg)
+	msg := tgbotapi.NewMessage(chatID, "✅ "+b.format.bold("Filter preferences saved!")+"\n\n"+b.format.escape(details))
+	msg.ParseMode = b.format.mode
+	b.send(msg)
 }
 
 func (b *Bot) handleWishlistCommand(message *tgbotapi.Message) {
@@ -286,46 +536,45 @@ func (b *Bot) handleWishlistCommand(message *tgbotapi.Message) {
 	}
 
 	if len(wishlist) == 0 {
-		text := `⭐ *Your Wishlist*
-
-Your wishlist is empty. 
-You can add courses to your wishlist by clicking the ⭐ button on course notifications.`
+		text := "⭐ " + b.format.bold("Your Wishlist") + "\n\n" + b.format.escape(`Your wishlist is empty. 
+You can add courses to your wishlist by clicking the ⭐ button on course notifications.`)
 
 		msg := tgbotapi.NewMessage(message.Chat.ID, text)
-		msg.ParseMode = "Markdown"
-		b.api.Send(msg)
+		msg.ParseMode = b.format.mode
+		b.send(msg)
 		return
 	}
 
-	// Send courses with remove buttons (limit to 5 at a time due to message length)
+	// Send courses with remove buttons, a page at a time due to message length
+	pageSize := b.pageSize(userID)
 	coursesToShow := len(wishlist)
-	if coursesToShow > 5 {
-		coursesToShow = 5
+	if coursesToShow > pageSize {
+		coursesToShow = pageSize
 	}
 	
 	for i := 0; i < coursesToShow; i++ {
 		course := wishlist[i]
-		courseText := fmt.Sprintf("🎓 *%s*\n📂 %s | ⭐ %.1f\n🔗 %s",
-			course.Title, course.Category, course.Rating, course.URL)
+		courseText := "🎓 " + b.format.bold(course.Title) + b.format.escape(fmt.Sprintf(" (#%d)\n📂 %s | ⭐ %.1f\n🔗 %s",
+			course.ID, course.Category, course.Rating, b.courseLink(course.URL)))
 		
 		// Create remove button for each course
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
 				tgbotapi.NewInlineKeyboardButtonData("🗑️ Remove from Wishlist", fmt.Sprintf("remove_wishlist:%d", course.ID)),
-				tgbotapi.NewInlineKeyboardButtonURL("🔗 View Course", course.URL),
+				tgbotapi.NewInlineKeyboardButtonURL("🔗 View Course", b.courseLink(course.URL)),
 			),
 		)
 		
 		msg := tgbotapi.NewMessage(message.Chat.ID, courseText)
-		msg.ParseMode = "Markdown"
+		msg.ParseMode = b.format.mode
 		msg.ReplyMarkup = keyboard
 		msg.DisableWebPagePreview = true
-		b.api.Send(msg)
+		b.send(msg)
 	}
 	
 	// If there are more courses, show summary
-	if len(wishlist) > 5 {
-		summaryText := fmt.Sprintf("\n... and %d more courses in your wishlist.\nUse /wishlist again to see more.", len(wishlist)-5)
+	if len(wishlist) > pageSize {
+		summaryText := fmt.Sprintf("\n... and %d more courses in your wishlist.\nUse /wishlist again to see more.", len(wishlist)-pageSize)
 		summaryMsg := tgbotapi.NewMessage(message.Chat.ID, summaryText)
 		b.api.Send(summaryMsg)
 	}
@@ -345,9 +594,7 @@ func (b *Bot) handleStatsCommand(message *tgbotapi.Message) {
 		ignoredCount = 0
 	}
 
-	text := fmt.Sprintf(`📊 *Your Activity Stats*
-
-⭐ Courses in wishlist: %d
+	stats := fmt.Sprintf(`⭐ Courses in wishlist: %d
 ❌ Courses ignored: %d
 🎯 Filter preferences: %s
 
@@ -357,47 +604,99 @@ Use /filter to update preferences`,
 		ignoredCount,
 		b.getFilterStatus(userID),
 	)
+	text := "📊 " + b.format.bold("Your Activity Stats") + "\n\n" + b.format.escape(stats)
 
 	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ParseMode = "Markdown"
-	b.api.Send(msg)
+	msg.ParseMode = b.format.mode
+	b.send(msg)
 }
 
 func (b *Bot) PostCourse(course *database.Course) error {
-	text := b.formatCourseMessage(course)
-	
-	// Create inline keyboard with action buttons
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+	if !b.channelPostingAllowed() {
+		return ErrChannelUnavailable
+	}
+
+	text := b.hurryBanner(course) + b.formatCourseMessage(course, b.location)
+	keyboard := b.courseKeyboard(course)
+
+	// Send to channel, or to the preview channel while previewing
+	msg := tgbotapi.NewMessage(b.postTarget(), text)
+	msg.ParseMode = b.format.mode
+	msg.ReplyMarkup = keyboard
+	msg.DisableWebPagePreview = true
+
+	_, err := b.send(msg)
+	b.recordChannelPost(err)
+	return err
+}
+
+// SetPreviewChannel routes course posts to a staging channel (numeric ID or
+// @username) instead of the production one, so formatting and quality
+// settings can be tried on live data
+func (b *Bot) SetPreviewChannel(channelID string) error {
+	resolved, err := resolveChannelID(b.api, channelID)
+	if err != nil {
+		return err
+	}
+	b.previewChannelID = resolved
+	log.Printf("Preview mode: posting courses to %s instead of the production channel", channelID)
+	return nil
+}
+
+// resolveChannelID converts a configured channel (numeric ID or @username)
+// to the numeric chat ID needed for sending. Usernames are looked up once
+// via getChat, which also confirms the bot can see the channel.
+func resolveChannelID(api *tgbotapi.BotAPI, channelID string) (int64, error) {
+	if !strings.HasPrefix(channelID, "@") {
+		id, err := strconv.ParseInt(channelID, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid channel ID %q: %w", channelID, err)
+		}
+		return id, nil
+	}
+
+	chat, err := api.GetChat(tgbotapi.ChatInfoConfig{
+		ChatConfig: tgbotapi.ChatConfig{SuperGroupUsername: channelID},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve channel %s: %w", channelID, err)
+	}
+
+	log.Printf("Resolved channel %s to chat ID %d", channelID, chat.ID)
+	return chat.ID, nil
+}
+
+// courseKeyboard creates the inline action buttons attached to a course message
+func (b *Bot) courseKeyboard(course *database.Course) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⭐ Save", fmt.Sprintf("wishlist:%d", course.ID)),
 			tgbotapi.NewInlineKeyboardButtonData("❌ Not Interested", fmt.Sprintf("ignore:%d", course.ID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonURL("🔗 View Course", course.URL),
+			tgbotapi.NewInlineKeyboardButtonData("⏰ Remind me", fmt.Sprintf("snooze:%d", course.ID)),
+			tgbotapi.NewInlineKeyboardButtonURL("🔗 View Course", b.courseLink(course.URL)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("ℹ️ Why this score?", fmt.Sprintf("score_info:%d", course.ID)),
 		),
 	)
-
-	// Send to channel
-	channelID, err := strconv.ParseInt(b.channelID, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid channel ID: %w", err)
-	}
-
-	msg := tgbotapi.NewMessage(channelID, text)
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
-	msg.DisableWebPagePreview = true
-
-	_, err = b.api.Send(msg)
-	return err
 }
 
-func (b *Bot) formatCourseMessage(course *database.Course) string {
+// formatCourseMessage renders a course post, showing the expiry time in loc
+func (b *Bot) formatCourseMessage(course *database.Course, loc *time.Location) string {
 	expiresIn := time.Until(course.ExpiresAt)
 	expiry := "Unknown"
 	urgencyIcon := "🕒"
 	
-	if expiresIn > 0 {
+	if course.ExpiresAt.IsZero() {
+		// Nothing on the listing revealed an expiry, so no countdown is shown
+		expiry = ""
+	} else if expiresIn <= 0 && !database.IsExpired(course.ExpiresAt, time.Now(), b.db.ExpiryGrace()) {
+		// Past the estimated expiry but within grace; the coupon may still work
+		expiry = "expiring now"
+		urgencyIcon = "🚨"
+	} else if expiresIn > 0 {
 		hours := expiresIn.Hours()
 		if hours < 6 {
 			expiry = fmt.Sprintf("%.0f hours", hours)
@@ -414,6 +713,7 @@ func (b *Bot) formatCourseMessage(course *database.Course) string {
 				urgencyIcon = "🕒" // Normal
 			}
 		}
+		expiry += " (" + course.ExpiresAt.In(loc).Format("Jan 2, 15:04 MST") + ")"
 	}
 
 	// Quality score indicator
@@ -440,29 +740,42 @@ func (b *Bot) formatCourseMessage(course *database.Course) string {
 		}
 	}
 
-	text := fmt.Sprintf(`🎓 *%s*
+	captions := ""
+	if len(course.CaptionLanguages) > 0 {
+		captions = "\n💬 CC: " + strings.Join(course.CaptionLanguages, ", ")
+	}
+	if course.Certificate != nil && *course.Certificate {
+		captions += "\n📜 Certificate"
+	}
+
+	expiryLine := urgencyIcon + " Expires in: " + expiry
+	if course.ExpiresAt.IsZero() {
+		expiryLine = "No expiry detected"
+	}
 
-📂 Category: %s
-💰 Price: %s %s
-%s Expires in: %s
+	// The original price carries its own markup, so the price line is
+	// marked up separately from the rest of the details
+	price := b.format.escape(course.Price + " " + course.Discount)
+	if course.OriginalPrice != "" && course.OriginalPrice != course.Price {
+		price = b.format.price(course.Price+" "+course.Discount, course.OriginalPrice)
+	}
+
+	details := fmt.Sprintf(`%s
 %s Quality Score: %.0f/100
-%s %s
+%s %s%s
 
 %s`,
-		course.Title,
-		course.Category,
-		course.Price,
-		course.Discount,
-		urgencyIcon,
-		expiry,
+		expiryLine,
 		qualityIcon,
 		course.QualityScore,
 		rating,
 		students,
+		captions,
 		course.Description,
 	)
 
-	return text
+	return "🎓 " + b.format.bold(course.Title) + "\n\n" +
+		b.format.escape("📂 Category: "+course.Category+"\n💰 Price: ") + price + "\n" + b.format.escape(details)
 }
 
 func (b *Bot) sendMessage(chatID int64, text string) {
@@ -471,7 +784,7 @@ func (b *Bot) sendMessage(chatID int64, text string) {
 }
 
 func (b *Bot) getUserWishlist(userID int64) ([]database.Course, error) {
-	query := `SELECT c.id, c.url, c.title, c.description, c.category, c.rating, c.price, c.discount, c.expires_at, c.posted_at, c.quality_score, c.student_count 
+	query := `SELECT ` + database.CourseColumns("c") + ` 
 			  FROM courses c
 			  INNER JOIN wishlist w ON c.id = w.course_id
 			  WHERE w.user_id = ?
@@ -486,9 +799,7 @@ func (b *Bot) getUserWishlist(userID int64) ([]database.Course, error) {
 	var courses []database.Course
 	for rows.Next() {
 		var course database.Course
-		err := rows.Scan(&course.ID, &course.URL, &course.Title, &course.Description,
-			&course.Category, &course.Rating, &course.Price, &course.Discount,
-			&course.ExpiresAt, &course.PostedAt, &course.QualityScore, &course.StudentCount)
+		err := database.ScanCourse(rows, &course)
 		if err != nil {
 			log.Printf("Failed to scan course: %v", err)
 			continue
@@ -496,7 +807,7 @@ func (b *Bot) getUserWishlist(userID int64) ([]database.Course, error) {
 		courses = append(courses, course)
 	}
 	
-	return courses, nil
+	return dedupeWishlist(courses, time.Now(), b.db.ExpiryGrace()), nil
 }
 
 
@@ -528,7 +839,10 @@ func (b *Bot) getFilterStatus(userID int64) string {
 		status += fmt.Sprintf("Min Rating: %.1f, ", filter.MinRating)
 	}
 	if len(filter.Keywords) > 0 {
-		status += fmt.Sprintf("Keywords: %d", len(filter.Keywords))
+		status += fmt.Sprintf("Keywords: %d, ", len(filter.Keywords))
+	}
+	if len(filter.RequiredKeywords) > 0 {
+		status += fmt.Sprintf("Required: %d", len(filter.RequiredKeywords))
 	}
 	
 	if status == "" {
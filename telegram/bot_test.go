@@ -0,0 +1,330 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+const testChannelID = -1001234567890
+
+// apiCall is one request the bot made to the fake Telegram API
+type apiCall struct {
+	Method string
+	Params url.Values
+}
+
+// fakeTelegram is a stand-in for the Telegram Bot API that records every
+// call. Methods answer with a plausible result unless fail says otherwise.
+type fakeTelegram struct {
+	mu     sync.Mutex
+	calls  []apiCall
+	nextID int
+
+	// usernames maps public @usernames to the chat IDs getChat reports
+	usernames map[string]int64
+
+	// statuses maps user IDs to the status getChatMember reports for them
+	// in any group; others are plain members
+	statuses map[int64]string
+
+	// fail returns a non-empty description to make a call fail with code
+	fail func(call apiCall) (code int, description string)
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	call := apiCall{Method: path.Base(r.URL.Path), Params: r.PostForm}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	fail := f.fail
+	f.nextID++
+	messageID := f.nextID
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if fail != nil {
+		if code, description := fail(call); description != "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"ok": false, "error_code": code, "description": description,
+			})
+			return
+		}
+	}
+
+	var result interface{} = true
+	switch call.Method {
+	case "getMe":
+		result = map[string]interface{}{"id": 1, "is_bot": true, "first_name": "Test", "username": "test_bot"}
+	case "getChat":
+		chatID, _ := strconv.ParseInt(call.Params.Get("chat_id"), 10, 64)
+		if username := call.Params.Get("chat_id"); strings.HasPrefix(username, "@") {
+			f.mu.Lock()
+			id, ok := f.usernames[username]
+			f.mu.Unlock()
+			if !ok {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"ok": false, "error_code": 400, "description": "Bad Request: chat not found",
+				})
+				return
+			}
+			chatID = id
+		}
+		result = map[string]interface{}{"id": chatID, "type": "channel"}
+	case "getChatMember":
+		userID, _ := strconv.ParseInt(call.Params.Get("user_id"), 10, 64)
+		f.mu.Lock()
+		status, ok := f.statuses[userID]
+		f.mu.Unlock()
+		if !ok {
+			status = "member"
+		}
+		result = map[string]interface{}{"user": map[string]interface{}{"id": userID, "is_bot": false, "first_name": "Tester"}, "status": status}
+	case "sendMessage", "editMessageText", "editMessageReplyMarkup", "forwardMessage":
+		chatID, _ := strconv.ParseInt(call.Params.Get("chat_id"), 10, 64)
+		if id, err := strconv.Atoi(call.Params.Get("message_id")); err == nil && call.Method != "forwardMessage" {
+			messageID = id
+		}
+		result = map[string]interface{}{
+			"message_id": messageID,
+			"date":       0,
+			"chat":       map[string]interface{}{"id": chatID, "type": "private"},
+			"text":       call.Params.Get("text"),
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+}
+
+// sent returns the calls made with the given method, in order
+func (f *fakeTelegram) sent(method string) []apiCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []apiCall
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// failWith makes calls fail as decided by fail; nil lets every call succeed
+func (f *fakeTelegram) failWith(fail func(call apiCall) (code int, description string)) {
+	f.mu.Lock()
+	f.fail = fail
+	f.mu.Unlock()
+}
+
+// reset forgets the calls recorded so far
+func (f *fakeTelegram) reset() {
+	f.mu.Lock()
+	f.calls = nil
+	f.mu.Unlock()
+}
+
+// newTestBot returns a bot backed by a fresh database and a fake Telegram API
+func newTestBot(t *testing.T) (*Bot, *fakeTelegram) {
+	t.Helper()
+
+	fake := &fakeTelegram{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	api, err := tgbotapi.NewBotAPIWithClient("test-token", server.URL+"/bot%s/%s", server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	b := newBot(api, testChannelID, db)
+	b.SetDMInterval(0)
+	fake.reset()
+	return b, fake
+}
+
+// addTestCourse stores a course with the given slug and returns it
+func addTestCourse(t *testing.T, db *database.DB, slug string, configure func(*database.Course)) database.Course {
+	t.Helper()
+	course := database.Course{
+		URL:          "https://www.udemy.com/course/" + slug + "/",
+		Title:        "Course " + slug,
+		Category:     "Development",
+		Price:        "Free",
+		Discount:     "100% off",
+		QualityScore: 50,
+	}
+	if configure != nil {
+		configure(&course)
+	}
+	if err := db.AddCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	return course
+}
+
+// testMessage builds an incoming private message from userID
+func testMessage(userID int64, text string) *tgbotapi.Message {
+	message := &tgbotapi.Message{
+		MessageID: 1,
+		From:      &tgbotapi.User{ID: userID, FirstName: "Tester"},
+		Chat:      &tgbotapi.Chat{ID: userID, Type: "private"},
+		Text:      text,
+	}
+	if strings.HasPrefix(text, "/") {
+		command := strings.Fields(text)[0]
+		message.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+	return message
+}
+
+// textsTo returns the texts of messages sent to chatID, in order
+func textsTo(calls []apiCall, chatID int64) []string {
+	var texts []string
+	for _, call := range calls {
+		if call.Params.Get("chat_id") == fmt.Sprint(chatID) {
+			texts = append(texts, call.Params.Get("text"))
+		}
+	}
+	return texts
+}
+
+func TestRepeatedButtonTapsSucceed(t *testing.T) {
+	b, fake := newTestBot(t)
+	course := addTestCourse(t, b.db, "go-basics", nil)
+	const userID = 42
+
+	for _, action := range []string{"wishlist", "ignore"} {
+		fake.reset()
+		for i := 0; i < 2; i++ {
+			b.handleCallbackQuery(&tgbotapi.CallbackQuery{
+				ID:      fmt.Sprintf("%s-%d", action, i),
+				From:    &tgbotapi.User{ID: userID},
+				Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: userID}, Text: course.Title},
+				Data:    fmt.Sprintf("%s:%d", action, course.ID),
+			})
+		}
+		if answered := fake.sent("answerCallbackQuery"); len(answered) != 2 {
+			t.Errorf("%s: answered %d of 2 taps", action, len(answered))
+		}
+	}
+
+	if saved, err := b.db.IsInWishlist(userID, course.ID); err != nil || !saved {
+		t.Errorf("IsInWishlist = %v, %v; want true", saved, err)
+	}
+	if ignored, err := b.db.IsIgnored(userID, course.ID); err != nil || !ignored {
+		t.Errorf("IsIgnored = %v, %v; want true", ignored, err)
+	}
+}
+
+func TestFormatCourseMessageCaptions(t *testing.T) {
+	b, _ := newTestBot(t)
+
+	course := &database.Course{Title: "Go Basics", Price: "Free", CaptionLanguages: []string{"en", "es"}}
+	if text := b.formatCourseMessage(course, time.UTC); !strings.Contains(text, "CC: en, es") {
+		t.Errorf("message lacks the captions line:\n%s", text)
+	}
+
+	course.CaptionLanguages = nil
+	if text := b.formatCourseMessage(course, time.UTC); strings.Contains(text, "CC:") {
+		t.Errorf("message shows captions that are unknown:\n%s", text)
+	}
+}
+
+func TestFormatCourseMessageWithinGrace(t *testing.T) {
+	b, _ := newTestBot(t)
+	b.db.SetExpiryGrace(time.Hour)
+
+	course := &database.Course{Title: "Go Basics", Price: "Free", ExpiresAt: time.Now().Add(-30 * time.Minute)}
+	if text := b.formatCourseMessage(course, time.UTC); !strings.Contains(text, "expiring now") {
+		t.Errorf("course within grace not labeled as expiring now:\n%s", text)
+	}
+
+	course.ExpiresAt = time.Now().Add(-2 * time.Hour)
+	if text := b.formatCourseMessage(course, time.UTC); strings.Contains(text, "expiring now") {
+		t.Errorf("course past grace labeled as expiring now:\n%s", text)
+	}
+}
+
+func TestResolveChannelID(t *testing.T) {
+	b, fake := newTestBot(t)
+	fake.usernames = map[string]int64{"@free_courses": -1009876543210}
+
+	tests := []struct {
+		channel string
+		want    int64
+		lookup  bool // Resolving needs a getChat call
+		wantErr bool
+	}{
+		{"-1001234567890", -1001234567890, false, false},
+		{"@free_courses", -1009876543210, true, false},
+		{"@no_such_channel", 0, true, true},
+		{"free_courses", 0, false, true},
+	}
+	for _, tt := range tests {
+		fake.reset()
+		got, err := resolveChannelID(b.api, tt.channel)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("resolveChannelID(%q) = %d, %v; want %d, error %v", tt.channel, got, err, tt.want, tt.wantErr)
+		}
+		if lookups := len(fake.sent("getChat")); (lookups > 0) != tt.lookup {
+			t.Errorf("resolveChannelID(%q) made %d getChat calls", tt.channel, lookups)
+		}
+	}
+}
+
+func TestPostCourseUsesResolvedChannel(t *testing.T) {
+	b, fake := newTestBot(t)
+	fake.usernames = map[string]int64{"@free_courses": -1009876543210}
+	channelID, err := resolveChannelID(b.api, "@free_courses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.channelID = channelID
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	fake.reset()
+	if err := b.PostCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	if texts := textsTo(fake.sent("sendMessage"), -1009876543210); len(texts) != 1 {
+		t.Errorf("sent %d messages to the resolved channel, want 1", len(texts))
+	}
+}
+
+func TestFormatCourseMessageUnknownExpiry(t *testing.T) {
+	b, _ := newTestBot(t)
+
+	course := &database.Course{Title: "Go Basics", Price: "Free"}
+	text := b.formatCourseMessage(course, time.UTC)
+	if !strings.Contains(text, "No expiry detected") || strings.Contains(text, "Expires in") {
+		t.Errorf("course without an expiry shows a countdown:\n%s", text)
+	}
+
+	course.ExpiresAt = time.Now().Add(5 * time.Hour)
+	text = b.formatCourseMessage(course, time.UTC)
+	if strings.Contains(text, "No expiry detected") || !strings.Contains(text, "Expires in") {
+		t.Errorf("course with an expiry lacks its countdown:\n%s", text)
+	}
+}
@@ -0,0 +1,125 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/security"
+)
+
+const maxCallbackDataLen = 64 // Telegram's limit for inline button data
+
+// handleBrowseCommand shows stored courses in one category without touching
+// the user's saved filter
+func (b *Bot) handleBrowseCommand(message *tgbotapi.Message, args string) {
+	category := security.SanitizeString(args)
+	if category == "" {
+		b.sendMessage(message.Chat.ID, "Usage: /browse <category>\nExample: /browse Development")
+		return
+	}
+
+	text, keyboard, err := b.browsePage(message.From.ID, category, 0)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load courses.")
+		log.Printf("Failed to browse category %s: %v", category, err)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ParseMode = b.format.mode
+	msg.DisableWebPagePreview = true
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	b.send(msg)
+}
+
+// handleBrowseCallback swaps the message behind a Prev/Next button for the
+// requested page. Data is "browse:<offset>:<category>".
+func (b *Bot) handleBrowseCallback(callback *tgbotapi.CallbackQuery, offset int, category string) {
+	if callback.Message == nil || category == "" || offset < 0 {
+		return
+	}
+
+	text, keyboard, err := b.browsePage(callback.From.ID, category, offset)
+	if err != nil {
+		log.Printf("Failed to browse category %s: %v", category, err)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, text)
+	edit.ParseMode = b.format.mode
+	edit.DisableWebPagePreview = true
+	edit.ReplyMarkup = keyboard
+	b.send(edit)
+}
+
+// browsePage renders one page of a category listing with its navigation
+// buttons, including expired courses if the user asked for them
+func (b *Bot) browsePage(userID int64, category string, offset int) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	size := b.pageSize(userID)
+
+	// Fetch one extra course to learn whether a next page exists
+	courses, err := b.db.GetTopCourses(category, 0, size+1, offset, b.showsExpired(userID))
+	if err != nil {
+		return "", nil, err
+	}
+
+	hasNext := len(courses) > size
+	if hasNext {
+		courses = courses[:size]
+	}
+
+	return b.formatBrowsePage(category, offset, size, courses), browseKeyboard(category, offset, size, hasNext), nil
+}
+
+func (b *Bot) formatBrowsePage(category string, offset, size int, courses []database.Course) string {
+	if len(courses) == 0 {
+		if offset > 0 {
+			return "📂 " + b.format.bold(category) + b.format.escape("\n\nNo more courses.")
+		}
+		return "📂 " + b.format.bold(category) + b.format.escape("\n\nNo courses found in this category.")
+	}
+
+	// The category and titles are user and scraped input, so they are escaped
+	var sb strings.Builder
+	sb.WriteString("📂 " + b.format.bold(category) + b.format.escape(fmt.Sprintf(" (page %d)\n\n", offset/size+1)))
+	for i, course := range courses {
+		sb.WriteString(b.format.escape(fmt.Sprintf("%d. ", offset+i+1)) + b.format.bold(course.Title) +
+			b.format.escape(fmt.Sprintf(" (#%d)\n", course.ID)))
+		sb.WriteString(b.format.escape(fmt.Sprintf("   ⭐ %.1f | Quality Score: %.0f/100 | 💰 %s\n", course.Rating, course.QualityScore, course.Price)))
+		sb.WriteString(b.format.escape(fmt.Sprintf("   🔗 %s\n", b.courseLink(course.URL))))
+	}
+	return sb.String()
+}
+
+// browseKeyboard returns Prev/Next buttons, or nil when there is only one page
+// or the category is too long to fit in callback data
+func browseKeyboard(category string, offset, size int, hasNext bool) *tgbotapi.InlineKeyboardMarkup {
+	var row []tgbotapi.InlineKeyboardButton
+	if offset > 0 {
+		prev := offset - size
+		if prev < 0 {
+			prev = 0
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("◀️ Prev", browseCallbackData(prev, category)))
+	}
+	if hasNext {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("Next ▶️", browseCallbackData(offset+size, category)))
+	}
+
+	if len(row) == 0 || len(browseCallbackData(offset+size, category)) > maxCallbackDataLen {
+		return nil
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(row)
+	return &keyboard
+}
+
+func browseCallbackData(offset int, category string) string {
+	return "browse:" + strconv.Itoa(offset) + ":" + category
+}
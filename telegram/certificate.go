@@ -0,0 +1,38 @@
+package telegram
+
+import (
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleCertificateCommand turns the certificate requirement on or off.
+// Certificates are only known for courses enriched from their Udemy page, so
+// without scraping.enrich_from_udemy the requirement lets every course through.
+func (b *Bot) handleCertificateCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on":
+		if err := b.filterEngine.SetRequireCertificate(userID, true); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to require certificates: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, "✅ You'll only get courses that offer a certificate of completion. Courses where this couldn't be determined are still sent.")
+	case "off":
+		if err := b.filterEngine.SetRequireCertificate(userID, false); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to stop requiring certificates: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, "✅ You'll get courses whether or not they offer a certificate.")
+	default:
+		status := "off"
+		if userFilter, err := b.filterEngine.GetUserFilter(userID); err == nil && userFilter.RequireCertificate {
+			status = "on"
+		}
+		b.sendMessage(message.Chat.ID, "📜 Certificate required: "+status+"\n\nUsage: /certificate on|off")
+	}
+}
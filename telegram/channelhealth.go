@@ -0,0 +1,149 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// channelRecheckInterval spaces out automatic getChat checks while channel
+// posting is paused
+const channelRecheckInterval = 10 * time.Minute
+
+// ErrChannelUnavailable is returned by PostCourse while channel posting is
+// paused because the bot lost access to the channel
+var ErrChannelUnavailable = errors.New("channel posting paused: bot has no access to the channel")
+
+// channelHealth counts consecutive post failures caused by the bot having
+// lost access to the channel, and pauses posting once they reach the limit
+type channelHealth struct {
+	mu          sync.Mutex
+	failures    int
+	paused      bool
+	lastChecked time.Time
+}
+
+// SetChannelFailureLimit sets how many consecutive "bot removed" failures
+// pause channel posting; 0 never pauses
+func (b *Bot) SetChannelFailureLimit(limit int) {
+	b.channelFailureLimit = limit
+}
+
+// isChannelGoneError reports whether a Telegram error means the bot can no
+// longer post to the chat at all: it was kicked, lost its rights, or the
+// chat was deleted. Other failures, e.g. rate limits, bad markup or a 403
+// that doesn't name one of those causes, are not.
+func isChannelGoneError(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	for _, phrase := range []string{"chat not found", "bot was kicked", "bot is not a member", "channel_private", "need administrator rights", "chat was deleted"} {
+		if strings.Contains(message, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// postTarget returns the chat course posts are sent to
+func (b *Bot) postTarget() int64 {
+	if b.previewChannelID != 0 {
+		return b.previewChannelID
+	}
+	return b.channelID
+}
+
+// channelPostingAllowed reports whether channel posts should be attempted.
+// While paused it checks the channel with getChat at most every
+// channelRecheckInterval and resumes if the bot can see it again.
+func (b *Bot) channelPostingAllowed() bool {
+	b.channel.mu.Lock()
+	if !b.channel.paused || time.Since(b.channel.lastChecked) < channelRecheckInterval {
+		paused := b.channel.paused
+		b.channel.mu.Unlock()
+		return !paused
+	}
+	b.channel.lastChecked = time.Now()
+	b.channel.mu.Unlock()
+
+	return b.recheckChannel() == nil
+}
+
+// recheckChannel asks Telegram whether the bot can see the channel and
+// resumes posting if so
+func (b *Bot) recheckChannel() error {
+	_, err := b.api.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: b.postTarget()}})
+	if err != nil {
+		return err
+	}
+
+	b.channel.mu.Lock()
+	defer b.channel.mu.Unlock()
+	if b.channel.paused {
+		log.Printf("Channel is reachable again; resuming channel posts")
+	}
+	b.channel.paused = false
+	b.channel.failures = 0
+	return nil
+}
+
+// recordChannelPost tracks the outcome of a channel post, pausing posting and
+// alerting admins once the bot has failed enough times in a row because it
+// lost access to the channel
+func (b *Bot) recordChannelPost(err error) {
+	b.channel.mu.Lock()
+	if err == nil || !isChannelGoneError(err) {
+		if err == nil {
+			b.channel.failures = 0
+		}
+		b.channel.mu.Unlock()
+		return
+	}
+
+	b.channel.failures++
+	pause := b.channelFailureLimit > 0 && !b.channel.paused && b.channel.failures >= b.channelFailureLimit
+	if pause {
+		b.channel.paused = true
+		b.channel.lastChecked = time.Now()
+	}
+	failures := b.channel.failures
+	b.channel.mu.Unlock()
+
+	if pause {
+		log.Printf("Pausing channel posts after %d failures: %v", failures, err)
+		b.notifyAdmins(fmt.Sprintf("⚠️ I can't post to the channel any more (%v).\n\n"+
+			"Channel posts are paused after %d failed attempts. Re-add me to the channel as an administrator, "+
+			"then send /recheck. I'll also retry on my own every %s.", err, failures, formatDuration(channelRecheckInterval)))
+	}
+}
+
+// notifyAdmins sends a plain-text message to every configured admin
+func (b *Bot) notifyAdmins(text string) {
+	for adminID := range b.adminIDs {
+		if _, err := b.api.Send(tgbotapi.NewMessage(adminID, text)); err != nil {
+			log.Printf("Failed to alert admin %d: %v", adminID, err)
+		}
+	}
+}
+
+// handleRecheckCommand checks the channel right away and resumes posting if
+// the bot has access again
+func (b *Bot) handleRecheckCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if err := b.recheckChannel(); err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ I still can't reach the channel: %v", err))
+		return
+	}
+	b.sendMessage(message.Chat.ID, "✅ The channel is reachable; channel posts are on.")
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestIsChannelGoneError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&tgbotapi.Error{Code: 403, Message: "Forbidden: bot was kicked from the channel chat"}, true},
+		{&tgbotapi.Error{Code: 403, Message: "Forbidden: the group chat was deleted"}, true},
+		{&tgbotapi.Error{Code: 403, Message: "Forbidden"}, false}, // No cause given
+		{&tgbotapi.Error{Code: 403, Message: "Forbidden: bot can't send messages to bots"}, false},
+		{&tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"}, true},
+		{&tgbotapi.Error{Code: 400, Message: "Bad Request: need administrator rights in the channel chat"}, true},
+		{&tgbotapi.Error{Code: 400, Message: "Bad Request: CHANNEL_PRIVATE"}, true},
+		{fmt.Errorf("failed to post: %w", &tgbotapi.Error{Code: 403, Message: "Forbidden: bot is not a member of the channel chat"}), true},
+		{&tgbotapi.Error{Code: 429, Message: "Too Many Requests: retry after 5"}, false},
+		{&tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities"}, false},
+		{errors.New("chat not found"), false}, // Not from the Telegram API
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isChannelGoneError(tt.err); got != tt.want {
+			t.Errorf("isChannelGoneError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestPostCoursePausesWhenBotIsRemoved(t *testing.T) {
+	b, fake := newTestBot(t)
+	const adminID = 1
+	b.SetAdminIDs([]int64{adminID})
+	b.SetChannelFailureLimit(2)
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	kicked := func(call apiCall) (int, string) {
+		if call.Params.Get("chat_id") == fmt.Sprint(int64(testChannelID)) {
+			return 403, "Forbidden: bot was kicked from the channel chat"
+		}
+		return 0, ""
+	}
+	fake.failWith(kicked)
+
+	// Failures that aren't about access don't count towards the limit
+	b.recordChannelPost(&tgbotapi.Error{Code: 400, Message: "Bad Request: message is too long"})
+
+	for i := 0; i < 2; i++ {
+		if err := b.PostCourse(&course); !isChannelGoneError(err) {
+			t.Fatalf("post %d = %v, want the kicked error", i+1, err)
+		}
+	}
+	alerts := textsTo(fake.sent("sendMessage"), adminID)
+	if len(alerts) != 1 || !strings.Contains(alerts[0], "/recheck") {
+		t.Fatalf("admin alerts = %q, want one asking to /recheck", alerts)
+	}
+
+	fake.reset()
+	if err := b.PostCourse(&course); err != ErrChannelUnavailable {
+		t.Errorf("post while paused = %v, want ErrChannelUnavailable", err)
+	}
+	if posts := textsTo(fake.sent("sendMessage"), testChannelID); len(posts) != 0 {
+		t.Errorf("posted %d messages to the channel while paused", len(posts))
+	}
+
+	// /recheck keeps posts paused until the bot can see the channel again
+	b.handleRecheckCommand(testMessage(adminID, "/recheck"))
+	if err := b.PostCourse(&course); err != ErrChannelUnavailable {
+		t.Errorf("post after a failed /recheck = %v, want ErrChannelUnavailable", err)
+	}
+
+	fake.failWith(nil)
+	fake.reset()
+	b.handleRecheckCommand(testMessage(adminID, "/recheck"))
+	if replies := textsTo(fake.sent("sendMessage"), adminID); len(replies) != 1 || !strings.Contains(replies[0], "reachable") {
+		t.Errorf("/recheck replied %q, want the channel reported reachable", replies)
+	}
+	if err := b.PostCourse(&course); err != nil {
+		t.Errorf("post after /recheck = %v, want it sent", err)
+	}
+}
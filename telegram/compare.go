@@ -0,0 +1,144 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/currency"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/security"
+)
+
+func (b *Bot) handleCompareCommand(message *tgbotapi.Message, args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		b.sendMessage(message.Chat.ID, "Usage: /compare <course ID> <course ID>\nBoth courses must be in your wishlist.")
+		return
+	}
+
+	var courses [2]*database.Course
+	for i, field := range fields {
+		courseID, err := strconv.Atoi(strings.TrimPrefix(field, "#"))
+		if err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid course ID: %s", field))
+			return
+		}
+
+		// Only wishlisted courses can be compared, so arbitrary rows aren't exposed
+		inWishlist, err := b.db.IsInWishlist(message.From.ID, courseID)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to load courses.")
+			log.Printf("Failed to check wishlist: %v", err)
+			return
+		}
+		if !inWishlist {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Course #%d is not in your wishlist.", courseID))
+			return
+		}
+
+		courses[i], err = b.db.GetCourse(courseID)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to load courses.")
+			log.Printf("Failed to get course: %v", err)
+			return
+		}
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, b.format.comparison(courses[0], courses[1]))
+	msg.ParseMode = b.format.mode
+	msg.DisableWebPagePreview = true
+	b.send(msg)
+}
+
+// comparison renders two courses side by side, marking the winner of each metric
+func (f formatter) comparison(a, c *database.Course) string {
+	var sb strings.Builder
+	sb.WriteString("⚖️ " + f.bold("Course Comparison") + "\n\n")
+	sb.WriteString(f.bold("A:") + f.escape(fmt.Sprintf(" %s (#%d)\n", a.Title, a.ID)))
+	sb.WriteString(f.bold("B:") + f.escape(fmt.Sprintf(" %s (#%d)\n\n", c.Title, c.ID)))
+
+	var table strings.Builder
+	table.WriteString(fmt.Sprintf("%-9s %-11s %-11s\n", "", "A", "B"))
+
+	row := func(label, valueA, valueB string, winner int) {
+		if winner < 0 {
+			valueA += " ✓"
+		} else if winner > 0 {
+			valueB += " ✓"
+		}
+		table.WriteString(fmt.Sprintf("%-9s %-11s %-11s\n", label, valueA, valueB))
+	}
+
+	row("Rating", fmt.Sprintf("%.1f", a.Rating), fmt.Sprintf("%.1f", c.Rating), compareFloat(a.Rating, c.Rating))
+	row("Students", strconv.Itoa(a.StudentCount), strconv.Itoa(c.StudentCount),
+		compareFloat(float64(a.StudentCount), float64(c.StudentCount)))
+	row("Quality", fmt.Sprintf("%.0f", a.QualityScore), fmt.Sprintf("%.0f", c.QualityScore),
+		compareFloat(a.QualityScore, c.QualityScore))
+	row("Price", ellipsize(a.Price, 9), ellipsize(c.Price, 9), comparePrice(a, c))
+	row("Expires", formatExpiryShort(a.ExpiresAt), formatExpiryShort(c.ExpiresAt),
+		compareFloat(float64(a.ExpiresAt.Unix()), float64(c.ExpiresAt.Unix())))
+
+	sb.WriteString(f.pre(table.String()))
+	sb.WriteString(f.escape("\n✓ marks the better value for each metric"))
+	return sb.String()
+}
+
+// compareFloat returns -1 if a wins (is higher), 1 if b wins, 0 on a tie
+func compareFloat(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case b > a:
+		return 1
+	}
+	return 0
+}
+
+// comparePrice prefers free courses, then the lower listed amount
+func comparePrice(a, b *database.Course) int {
+	freeA := database.IsFreePrice(a.Price, a.Discount)
+	freeB := database.IsFreePrice(b.Price, b.Discount)
+	if freeA != freeB {
+		if freeA {
+			return -1
+		}
+		return 1
+	}
+	if freeA {
+		return 0
+	}
+
+	amountA, _, okA := currency.Parse(a.Price)
+	amountB, _, okB := currency.Parse(b.Price)
+	if !okA || !okB {
+		return 0
+	}
+	return compareFloat(amountB, amountA)
+}
+
+func formatExpiryShort(expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return "unknown"
+	}
+	remaining := time.Until(expiresAt)
+	if remaining <= 0 {
+		return "expired"
+	}
+	if remaining < 24*time.Hour {
+		return fmt.Sprintf("%.0fh", remaining.Hours())
+	}
+	return fmt.Sprintf("%.0fd", remaining.Hours()/24)
+}
+
+// ellipsize shortens text to at most limit characters, marking the cut with "…"
+func ellipsize(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return security.TruncateRunes(text, limit-1) + "…"
+}
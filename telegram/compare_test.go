@@ -0,0 +1,89 @@
+package telegram
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+func TestFormatComparisonMarksWinners(t *testing.T) {
+	a := &database.Course{ID: 1, Title: "Go Basics", Rating: 4.7, StudentCount: 1200, QualityScore: 80,
+		Price: "Free", Discount: "100% off", ExpiresAt: time.Now().Add(72 * time.Hour)}
+	c := &database.Course{ID: 2, Title: "Rust Basics", Rating: 4.2, StudentCount: 5400, QualityScore: 80,
+		Price: "$9.99", Discount: "80% off", ExpiresAt: time.Now().Add(5 * time.Hour)}
+
+	text := formatter{mode: tgbotapi.ModeMarkdown}.comparison(a, c)
+	rows := map[string]string{}
+	for _, line := range strings.Split(text, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows[fields[0]] = line
+		}
+	}
+
+	tests := []struct {
+		row    string
+		winner string // Value that should carry the mark; empty for a tie
+		loser  string
+	}{
+		{"Rating", "4.7", "4.2"},
+		{"Students", "5400", "1200"},
+		{"Price", "Free", "$9.99"},
+		{"Expires", "3d", "5h"},
+		{"Quality", "", ""},
+	}
+	for _, tt := range tests {
+		line, ok := rows[tt.row]
+		if !ok {
+			t.Errorf("comparison has no %s row:\n%s", tt.row, text)
+			continue
+		}
+		if tt.winner == "" {
+			if strings.Contains(line, "✓") {
+				t.Errorf("%s row marks a winner on a tie: %q", tt.row, line)
+			}
+			continue
+		}
+		if !strings.Contains(line, tt.winner+" ✓") || strings.Contains(line, tt.loser+" ✓") {
+			t.Errorf("%s row = %q, want %s marked", tt.row, line, tt.winner)
+		}
+	}
+}
+
+func TestCompareCommandRequiresWishlist(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	saved := addTestCourse(t, b.db, "go-basics", nil)
+	other := addTestCourse(t, b.db, "rust-basics", nil)
+	if err := b.db.AddToWishlist(userID, saved.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	b.handleCompareCommand(testMessage(userID, "/compare"), formatIDs(saved.ID, other.ID))
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 || !strings.Contains(texts[0], "is not in your wishlist") || strings.Contains(texts[0], other.Title) {
+		t.Fatalf("comparing an unsaved course sent %q, want a refusal", texts)
+	}
+
+	if err := b.db.AddToWishlist(userID, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	fake.reset()
+	b.handleCompareCommand(testMessage(userID, "/compare"), formatIDs(saved.ID, other.ID))
+	texts = textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 || !strings.Contains(texts[0], saved.Title) || !strings.Contains(texts[0], other.Title) {
+		t.Errorf("comparison sent %q, want both courses", texts)
+	}
+}
+
+// formatIDs renders course IDs as command arguments
+func formatIDs(ids ...int) string {
+	var args []string
+	for _, id := range ids {
+		args = append(args, "#"+strconv.Itoa(id))
+	}
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,107 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Daily limit modes: hold matches over a user's /maxperday limit until the
+// next day, or drop them
+const (
+	DailyLimitHold = "hold"
+	DailyLimitDrop = "drop"
+)
+
+const maxPerDayUsage = "Usage: /maxperday <count>, e.g. /maxperday 5\nUse /maxperday off to remove the limit."
+
+// SetDailyLimitMode sets what happens to matches once a user has received
+// their /maxperday courses: DailyLimitHold (default) or DailyLimitDrop
+func (b *Bot) SetDailyLimitMode(mode string) {
+	b.dailyLimitMode = mode
+}
+
+// userToday returns the current date in the user's timezone, which is when
+// their daily count starts over
+func (b *Bot) userToday(userID int64) string {
+	return time.Now().In(b.userLocation(userID)).Format("2006-01-02")
+}
+
+// dailyLimitReached reports whether the user has received their /maxperday
+// courses today
+func (b *Bot) dailyLimitReached(userID int64) bool {
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	if err != nil || userFilter.MaxPerDay <= 0 {
+		return false
+	}
+	count, err := b.db.DailySentCount(userID, b.userToday(userID))
+	if err != nil {
+		log.Printf("Failed to get daily count for user %d: %v", userID, err)
+		return false
+	}
+	return count >= userFilter.MaxPerDay
+}
+
+// recordDailySend counts a course sent to the user and tells them when it
+// was the last one they will get today
+func (b *Bot) recordDailySend(userID int64) {
+	count, err := b.db.IncrementDailySent(userID, b.userToday(userID))
+	if err != nil {
+		log.Printf("Failed to count delivery for user %d: %v", userID, err)
+		return
+	}
+
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	if err != nil || userFilter.MaxPerDay <= 0 || count != userFilter.MaxPerDay {
+		return
+	}
+
+	outcome := "More matches will be sent tomorrow."
+	if b.dailyLimitMode == DailyLimitDrop {
+		outcome = "Further matches today won't be sent to you."
+	}
+	b.sendMessage(userID, fmt.Sprintf("📬 That's your %d courses for today. %s Use /maxperday to change the limit.", count, outcome))
+}
+
+func (b *Bot) handleMaxPerDayCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+	args = strings.TrimSpace(args)
+
+	if args == "" {
+		userFilter, err := b.filterEngine.GetUserFilter(userID)
+		if err != nil || userFilter.MaxPerDay <= 0 {
+			b.sendMessage(message.Chat.ID, "📬 No daily limit is set.\n\n"+maxPerDayUsage)
+			return
+		}
+		count, _ := b.db.DailySentCount(userID, b.userToday(userID))
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("📬 Daily limit: %d courses (%d sent today)\n\n%s",
+			userFilter.MaxPerDay, count, maxPerDayUsage))
+		return
+	}
+
+	limit := 0
+	if !strings.EqualFold(args, "off") {
+		n, err := strconv.Atoi(args)
+		if err != nil || n < 1 {
+			b.sendMessage(message.Chat.ID, "❌ The limit must be a whole number of at least 1.\n"+maxPerDayUsage)
+			return
+		}
+		limit = n
+	}
+
+	if err := b.filterEngine.SetMaxPerDay(userID, limit); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save daily limit: %v", err)
+		return
+	}
+
+	if limit == 0 {
+		b.sendMessage(message.Chat.ID, "📬 Daily limit removed.")
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("📬 You'll get at most %d courses a day (%s).", limit, b.userLocation(userID)))
+}
@@ -0,0 +1,75 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotifySubscribersDailyLimit(t *testing.T) {
+	for _, mode := range []string{DailyLimitHold, DailyLimitDrop} {
+		t.Run(mode, func(t *testing.T) {
+			b, fake := newTestBot(t)
+			b.SetDailyLimitMode(mode)
+			const userID = 42
+			if _, err := b.db.AddSubscriber(userID); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.filterEngine.SetMaxPerDay(userID, 2); err != nil {
+				t.Fatal(err)
+			}
+
+			// Yesterday's count doesn't use up today's limit
+			if _, err := b.db.IncrementDailySent(userID, "2000-01-01"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := b.db.IncrementDailySent(userID, "2000-01-01"); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, slug := range []string{"first", "second", "third"} {
+				course := addTestCourse(t, b.db, slug, nil)
+				b.NotifySubscribers(&course)
+			}
+
+			var courses, notes int
+			for _, text := range textsTo(fake.sent("sendMessage"), userID) {
+				if strings.Contains(text, "That's your 2 courses for today") {
+					notes++
+				} else {
+					courses++
+				}
+			}
+			if courses != 2 || notes != 1 {
+				t.Fatalf("sent %d courses and %d limit notes, want 2 and 1", courses, notes)
+			}
+
+			held, err := b.db.GetHeldNotifications(userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantHeld := 0
+			if mode == DailyLimitHold {
+				wantHeld = 1
+			}
+			if len(held) != wantHeld {
+				t.Fatalf("held %d notifications, want %d", len(held), wantHeld)
+			}
+
+			// Held courses wait while the limit lasts
+			fake.reset()
+			b.DeliverHeldNotifications()
+			if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 0 {
+				t.Errorf("delivered %d held courses over the limit", len(texts))
+			}
+
+			// Once the stored count belongs to an earlier day they go out
+			if _, err := b.db.IncrementDailySent(userID, "2000-01-02"); err != nil {
+				t.Fatal(err)
+			}
+			b.DeliverHeldNotifications()
+			if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != wantHeld {
+				t.Errorf("delivered %d held courses the next day, want %d", len(texts), wantHeld)
+			}
+		})
+	}
+}
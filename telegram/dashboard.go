@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dashboardStats are the figures shown in the pinned channel dashboard
+type dashboardStats struct {
+	totalCourses int
+	postedToday  int
+	subscribers  int
+	lastScan     time.Time
+}
+
+// UpdateDashboard refreshes the pinned stats message in the channel. The
+// stored message is edited in place; if it was deleted, a new one is posted
+// and pinned.
+func (b *Bot) UpdateDashboard() {
+	stats, err := b.dashboardStats()
+	if err != nil {
+		log.Printf("Failed to gather dashboard stats: %v", err)
+		return
+	}
+	text := formatDashboard(stats, time.Now(), b.location)
+
+	messageID, err := b.db.DashboardMessageID()
+	if err != nil {
+		log.Printf("Failed to load dashboard message ID: %v", err)
+		return
+	}
+
+	if messageID != 0 {
+		_, err := b.api.Send(tgbotapi.NewEditMessageText(b.channelID, messageID, text))
+		if !dashboardNeedsRepost(err) {
+			if err != nil && !strings.Contains(err.Error(), "message is not modified") {
+				log.Printf("Failed to update dashboard: %v", err)
+			}
+			return
+		}
+		log.Printf("Dashboard message %d is gone, posting a new one", messageID)
+	}
+
+	sent, err := b.api.Send(tgbotapi.NewMessage(b.channelID, text))
+	if err != nil {
+		log.Printf("Failed to post dashboard: %v", err)
+		return
+	}
+	if err := b.db.SetDashboardMessageID(sent.MessageID); err != nil {
+		log.Printf("Failed to save dashboard message ID: %v", err)
+	}
+
+	pin := tgbotapi.PinChatMessageConfig{
+		ChatID:              b.channelID,
+		MessageID:           sent.MessageID,
+		DisableNotification: true,
+	}
+	if _, err := b.api.Request(pin); err != nil {
+		log.Printf("Failed to pin dashboard: %v", err)
+	}
+}
+
+// dashboardNeedsRepost reports whether an edit failed because the dashboard
+// message no longer exists. Other failures, such as network errors, are
+// retried by editing on the next update rather than posting duplicates.
+func dashboardNeedsRepost(editErr error) bool {
+	if editErr == nil {
+		return false
+	}
+	msg := editErr.Error()
+	return strings.Contains(msg, "message to edit not found") ||
+		strings.Contains(msg, "MESSAGE_ID_INVALID")
+}
+
+func (b *Bot) dashboardStats() (dashboardStats, error) {
+	var stats dashboardStats
+	var err error
+
+	if stats.totalCourses, err = b.db.CountCourses(); err != nil {
+		return stats, err
+	}
+
+	year, month, day := time.Now().In(b.location).Date()
+	startOfDay := time.Date(year, month, day, 0, 0, 0, 0, b.location)
+	if stats.postedToday, err = b.db.CountCoursesSince(startOfDay); err != nil {
+		return stats, err
+	}
+
+	if stats.subscribers, err = b.db.CountSubscribers(); err != nil {
+		return stats, err
+	}
+
+	b.status.mu.Lock()
+	stats.lastScan = b.status.lastScan
+	b.status.mu.Unlock()
+
+	return stats, nil
+}
+
+func formatDashboard(stats dashboardStats, now time.Time, loc *time.Location) string {
+	lastScan := "no scan completed yet"
+	if !stats.lastScan.IsZero() {
+		lastScan = stats.lastScan.In(loc).Format("Jan 2, 15:04 MST")
+	}
+
+	return fmt.Sprintf(`📊 Bot Dashboard
+
+📚 Courses tracked: %d
+🆕 New today: %d
+👥 Subscribers: %d
+🔍 Last scan: %s
+
+Updated %s`, stats.totalCourses, stats.postedToday, stats.subscribers, lastScan,
+		now.In(loc).Format("Jan 2, 15:04 MST"))
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDashboardNeedsRepost(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("Bad Request: message to edit not found"), true},
+		{errors.New("Bad Request: MESSAGE_ID_INVALID"), true},
+		{errors.New("Bad Request: message is not modified"), false},
+		{errors.New("Too Many Requests: retry after 5"), false},
+		{errors.New("dial tcp: connection refused"), false},
+	}
+	for _, tt := range tests {
+		if got := dashboardNeedsRepost(tt.err); got != tt.want {
+			t.Errorf("dashboardNeedsRepost(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateDashboardEditsOrReposts(t *testing.T) {
+	b, fake := newTestBot(t)
+
+	// The first update posts and pins a new message
+	b.UpdateDashboard()
+	if posted, pinned := len(fake.sent("sendMessage")), len(fake.sent("pinChatMessage")); posted != 1 || pinned != 1 {
+		t.Fatalf("first update posted %d and pinned %d messages, want 1 each", posted, pinned)
+	}
+	firstID, err := b.db.DashboardMessageID()
+	if err != nil || firstID == 0 {
+		t.Fatalf("DashboardMessageID = %d, %v; want the posted message", firstID, err)
+	}
+
+	// Later updates edit it in place, even when nothing changed
+	for _, description := range []string{"", "Bad Request: message is not modified", "Internal Server Error"} {
+		fake.reset()
+		fake.failWith(func(call apiCall) (int, string) {
+			if call.Method == "editMessageText" && description != "" {
+				return 400, description
+			}
+			return 0, ""
+		})
+		b.UpdateDashboard()
+
+		edits := fake.sent("editMessageText")
+		if len(edits) != 1 || edits[0].Params.Get("message_id") != fmt.Sprint(firstID) {
+			t.Errorf("edit failing with %q: edited %v, want message %d once", description, edits, firstID)
+		}
+		if posted := len(fake.sent("sendMessage")); posted != 0 {
+			t.Errorf("edit failing with %q: posted %d new dashboards", description, posted)
+		}
+	}
+
+	// A deleted message is replaced by a new pinned one
+	fake.reset()
+	fake.failWith(func(call apiCall) (int, string) {
+		if call.Method == "editMessageText" {
+			return 400, "Bad Request: message to edit not found"
+		}
+		return 0, ""
+	})
+	b.UpdateDashboard()
+	if posted, pinned := len(fake.sent("sendMessage")), len(fake.sent("pinChatMessage")); posted != 1 || pinned != 1 {
+		t.Errorf("repost after deletion posted %d and pinned %d messages, want 1 each", posted, pinned)
+	}
+	if secondID, err := b.db.DashboardMessageID(); err != nil || secondID == firstID {
+		t.Errorf("DashboardMessageID = %d, %v; want the reposted message", secondID, err)
+	}
+}
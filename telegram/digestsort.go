@@ -0,0 +1,81 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// Orders a user can pick for the courses in their digests
+const (
+	DigestSortExpiry  = "expiry"  // Expiring soonest first (default)
+	DigestSortQuality = "quality" // Highest quality score first
+	DigestSortRating  = "rating"  // Highest rating first
+	DigestSortNewest  = "newest"  // Most recently found first
+)
+
+var digestSortModes = []string{DigestSortExpiry, DigestSortQuality, DigestSortRating, DigestSortNewest}
+
+// sortDigest orders digest courses in place by mode. Unknown or empty modes
+// use the default expiry order. Ties keep their existing order.
+func sortDigest(courses []database.Course, mode string) {
+	var less func(a, b database.Course) bool
+	switch mode {
+	case DigestSortQuality:
+		less = func(a, b database.Course) bool { return a.QualityScore > b.QualityScore }
+	case DigestSortRating:
+		less = func(a, b database.Course) bool { return a.Rating > b.Rating }
+	case DigestSortNewest:
+		less = func(a, b database.Course) bool { return a.PostedAt.After(b.PostedAt) }
+	default:
+		// Courses without an expiry go last
+		less = func(a, b database.Course) bool {
+			if a.ExpiresAt.IsZero() || b.ExpiresAt.IsZero() {
+				return !a.ExpiresAt.IsZero() && b.ExpiresAt.IsZero()
+			}
+			return a.ExpiresAt.Before(b.ExpiresAt)
+		}
+	}
+
+	sort.SliceStable(courses, func(i, j int) bool { return less(courses[i], courses[j]) })
+}
+
+func validDigestSort(mode string) bool {
+	for _, m := range digestSortModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) handleDigestSortCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+	mode := strings.ToLower(strings.TrimSpace(args))
+
+	if mode == "" {
+		current := DigestSortExpiry
+		if userFilter, err := b.filterEngine.GetUserFilter(userID); err == nil && userFilter.DigestSort != "" {
+			current = userFilter.DigestSort
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("📋 Digests are sorted by %s.\n\nUsage: /digestsort %s",
+			current, strings.Join(digestSortModes, "|")))
+		return
+	}
+
+	if !validDigestSort(mode) {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unknown sort order. Choose one of: %s", strings.Join(digestSortModes, ", ")))
+		return
+	}
+
+	if err := b.filterEngine.SetDigestSort(userID, mode); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to set digest sort: %v", err)
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Digests will be sorted by %s.", mode))
+}
@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+func TestSortDigest(t *testing.T) {
+	now := time.Now()
+	courses := []database.Course{
+		{Title: "A", ExpiresAt: now.Add(3 * time.Hour), QualityScore: 50, Rating: 4.0, PostedAt: now.Add(-72 * time.Hour)},
+		{Title: "B", ExpiresAt: now.Add(1 * time.Hour), QualityScore: 90, Rating: 3.5, PostedAt: now.Add(-24 * time.Hour)},
+		{Title: "C", QualityScore: 70, Rating: 4.8, PostedAt: now.Add(-48 * time.Hour)},
+		{Title: "D", ExpiresAt: now.Add(2 * time.Hour), QualityScore: 60, Rating: 4.5, PostedAt: now},
+	}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{DigestSortExpiry, "BDAC"},
+		{DigestSortQuality, "BCDA"},
+		{DigestSortRating, "CDAB"},
+		{DigestSortNewest, "DBCA"},
+		{"", "BDAC"},
+		{"unknown", "BDAC"},
+	}
+	for _, tt := range tests {
+		sorted := append([]database.Course(nil), courses...)
+		sortDigest(sorted, tt.mode)
+
+		var got strings.Builder
+		for _, course := range sorted {
+			got.WriteString(course.Title)
+		}
+		if got.String() != tt.want {
+			t.Errorf("sortDigest(%q) = %s, want %s", tt.mode, got.String(), tt.want)
+		}
+	}
+}
+
+func TestDigestSortCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	reply := func(text string) string {
+		fake.reset()
+		b.handleMessage(testMessage(userID, text))
+		return strings.Join(textsTo(fake.sent("sendMessage"), userID), "")
+	}
+
+	if text := reply("/digestsort"); !strings.Contains(text, "sorted by expiry") {
+		t.Errorf("/digestsort without a preference replied %q, want the default", text)
+	}
+	if text := reply("/digestsort sideways"); !strings.Contains(text, "Unknown sort order") {
+		t.Errorf("/digestsort sideways replied %q, want a refusal", text)
+	}
+	if text := reply("/digestsort Rating"); !strings.Contains(text, "sorted by rating") {
+		t.Errorf("/digestsort Rating replied %q, want a confirmation", text)
+	}
+	if userFilter, err := b.filterEngine.GetUserFilter(userID); err != nil || userFilter.DigestSort != DigestSortRating {
+		t.Errorf("stored digest sort = %+v, %v; want %q", userFilter, err, DigestSortRating)
+	}
+}
+
+func TestWishlistDigestUsesSortPreference(t *testing.T) {
+	b, fake := newTestBot(t)
+	b.SetRemindAllLeadTime(48 * time.Hour)
+	const userID = 42
+	if err := b.filterEngine.SetRemindAll(userID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.filterEngine.SetDigestSort(userID, DigestSortQuality); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for _, course := range []struct {
+		slug    string
+		expires time.Duration
+		quality float64
+	}{
+		{"sooner", time.Hour, 40},
+		{"better", 2 * time.Hour, 90},
+	} {
+		course := course
+		stored := addTestCourse(t, b.db, course.slug, func(c *database.Course) {
+			c.ExpiresAt = now.Add(course.expires)
+			c.QualityScore = course.quality
+		})
+		if err := b.db.AddToWishlist(userID, stored.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b.SendWishlistExpiryDigests()
+
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 {
+		t.Fatalf("sent %d digests, want 1", len(texts))
+	}
+	better, sooner := strings.Index(texts[0], "Course better"), strings.Index(texts[0], "Course sooner")
+	if better < 0 || sooner < 0 || better > sooner {
+		t.Errorf("digest sorted by quality lists the higher-quality course second:\n%s", texts[0])
+	}
+}
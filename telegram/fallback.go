@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// send delivers a message or edit, resending it as plain text if Telegram
+// rejects its markup. Escaping should prevent that, but scraped titles and
+// descriptions are unpredictable, and a dropped course is worse than one
+// showing stray formatting characters.
+func (b *Bot) send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	sent, err := b.api.Send(c)
+	if err == nil || !isMarkupError(err) {
+		return sent, err
+	}
+
+	plain, ok := withoutParseMode(c)
+	if !ok {
+		return sent, err
+	}
+	log.Printf("Telegram rejected message markup (%v), resending as plain text", err)
+	return b.api.Send(plain)
+}
+
+// isMarkupError reports whether Telegram refused a message because its
+// Markdown or HTML could not be parsed
+func isMarkupError(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 {
+		return false
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message), "can't parse entities")
+}
+
+// withoutParseMode returns a copy of a message or edit with its parse mode
+// cleared, or false for other requests or ones already sent as plain text
+func withoutParseMode(c tgbotapi.Chattable) (tgbotapi.Chattable, bool) {
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
+		if msg.ParseMode == "" {
+			return nil, false
+		}
+		msg.ParseMode = ""
+		return msg, true
+	case tgbotapi.EditMessageTextConfig:
+		if msg.ParseMode == "" {
+			return nil, false
+		}
+		msg.ParseMode = ""
+		return msg, true
+	}
+	return nil, false
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// rejectMarkup fails every call that asks Telegram to parse its text
+func rejectMarkup(call apiCall) (int, string) {
+	if call.Params.Get("parse_mode") != "" {
+		return 400, "Bad Request: can't parse entities: Can't find end of the entity starting at byte offset 12"
+	}
+	return 0, ""
+}
+
+func TestPostCourseResendsPlainTextOnMarkupError(t *testing.T) {
+	b, fake := newTestBot(t)
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	fake.failWith(rejectMarkup)
+	if err := b.PostCourse(&course); err != nil {
+		t.Fatalf("PostCourse failed despite the plain-text fallback: %v", err)
+	}
+
+	calls := fake.sent("sendMessage")
+	if len(calls) != 2 {
+		t.Fatalf("made %d sendMessage calls, want the rejected one and a resend", len(calls))
+	}
+	if calls[0].Params.Get("parse_mode") == "" {
+		t.Error("first attempt was already plain text")
+	}
+	resend := calls[1]
+	if resend.Params.Get("parse_mode") != "" {
+		t.Errorf("resend kept parse mode %q", resend.Params.Get("parse_mode"))
+	}
+	if resend.Params.Get("text") != calls[0].Params.Get("text") || !strings.Contains(resend.Params.Get("text"), course.Title) {
+		t.Errorf("resend text = %q, want the original message", resend.Params.Get("text"))
+	}
+}
+
+func TestSendFallsBackOnlyForMarkupErrors(t *testing.T) {
+	b, fake := newTestBot(t)
+
+	tests := []struct {
+		name        string
+		msg         tgbotapi.Chattable
+		code        int
+		description string
+		failPlain   bool // Plain-text requests fail too
+		wantCalls   int
+		wantErr     bool
+	}{
+		{"markup error on a message", markdownMessage("*broken"), 400, "Bad Request: can't parse entities: unclosed", false, 2, false},
+		{"markup error on an edit", markdownEdit("*broken"), 400, "Bad Request: can't parse entities: unclosed", false, 2, false},
+		{"other bad request", markdownMessage("*fine*"), 400, "Bad Request: chat not found", false, 1, true},
+		{"server error", markdownMessage("*fine*"), 500, "Internal Server Error", false, 1, true},
+		{"plain text rejected", tgbotapi.NewMessage(42, "plain"), 400, "Bad Request: can't parse entities: unclosed", true, 1, true},
+	}
+	for _, tt := range tests {
+		fake.reset()
+		fake.failWith(func(call apiCall) (int, string) {
+			if call.Params.Get("parse_mode") == "" && !tt.failPlain {
+				return 0, ""
+			}
+			return tt.code, tt.description
+		})
+
+		_, err := b.send(tt.msg)
+		calls := len(fake.sent("sendMessage")) + len(fake.sent("editMessageText"))
+		if calls != tt.wantCalls || (err != nil) != tt.wantErr {
+			t.Errorf("%s: made %d calls with error %v, want %d calls and error %v", tt.name, calls, err, tt.wantCalls, tt.wantErr)
+		}
+	}
+}
+
+func markdownMessage(text string) tgbotapi.MessageConfig {
+	msg := tgbotapi.NewMessage(42, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	return msg
+}
+
+func markdownEdit(text string) tgbotapi.EditMessageTextConfig {
+	edit := tgbotapi.NewEditMessageText(42, 7, text)
+	edit.ParseMode = tgbotapi.ModeMarkdown
+	return edit
+}
@@ -0,0 +1,326 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/filters"
+	"udemy-course-notifier/security"
+)
+
+// Filter wizard steps, in order. Categories and rating are picked with
+// buttons; keywords and exclusions are typed, or skipped.
+const (
+	wizardCategories = "categories"
+	wizardRating     = "rating"
+	wizardKeywords   = "keywords"
+	wizardExcluded   = "excluded"
+	wizardDone       = "done"
+)
+
+// maxWizardCategories caps the category buttons offered by /filterwizard
+const maxWizardCategories = 20
+
+// filterWizard is one user's in-progress /filterwizard setup. Nothing is
+// saved until the last step.
+type filterWizard struct {
+	step       string
+	messageID  int      // The wizard message whose buttons are current
+	categories []string // Category choices, referenced by index in callback data
+	selected   map[int]bool
+	minRating  float64
+	keywords   []string // Any one must match
+	required   []string // Typed with a "+" prefix; all must match
+	excluded   []string
+}
+
+func newFilterWizard(categories []string) *filterWizard {
+	w := &filterWizard{
+		step:       wizardCategories,
+		categories: categories,
+		selected:   make(map[int]bool),
+	}
+	if len(categories) == 0 {
+		w.step = wizardRating // Nothing stored yet to choose from
+	}
+	return w
+}
+
+// advance moves to the step after the current one
+func (w *filterWizard) advance() {
+	switch w.step {
+	case wizardCategories:
+		w.step = wizardRating
+	case wizardRating:
+		w.step = wizardKeywords
+	case wizardKeywords:
+		w.step = wizardExcluded
+	default:
+		w.step = wizardDone
+	}
+}
+
+// awaitsText reports whether the current step is answered by typing
+func (w *filterWizard) awaitsText() bool {
+	return w.step == wizardKeywords || w.step == wizardExcluded
+}
+
+// toggleCategory selects or deselects the category at index i
+func (w *filterWizard) toggleCategory(i int) bool {
+	if w.step != wizardCategories || i < 0 || i >= len(w.categories) {
+		return false
+	}
+	w.selected[i] = !w.selected[i]
+	return true
+}
+
+// setRating records a rating option and moves on
+func (w *filterWizard) setRating(option string) bool {
+	rating, err := strconv.ParseFloat(option, 64)
+	if w.step != wizardRating || err != nil || rating < 0 || rating > 5 {
+		return false
+	}
+	w.minRating = rating
+	w.advance()
+	return true
+}
+
+// setText records the answer to a typed step, a comma-separated list, and
+// moves on
+func (w *filterWizard) setText(text string) bool {
+	switch w.step {
+	case wizardKeywords:
+		w.keywords, w.required = filters.SplitKeywords(text)
+	case wizardExcluded:
+		w.excluded = filters.SplitList(text)
+	default:
+		return false
+	}
+	w.advance()
+	return true
+}
+
+// filter assembles the chosen preferences. The language and caption
+// language of the user's current filter, which the wizard doesn't ask
+// about, are kept.
+func (w *filterWizard) filter(userID int64, current *filters.UserFilter) *filters.UserFilter {
+	userFilter := &filters.UserFilter{
+		UserID:           userID,
+		Keywords:         w.keywords,
+		RequiredKeywords: w.required,
+		ExcludedKeywords: w.excluded,
+		MinRating:        w.minRating,
+		Language:         "en",
+	}
+	for i, category := range w.categories {
+		if w.selected[i] {
+			userFilter.Categories = append(userFilter.Categories, category)
+		}
+	}
+	if current != nil {
+		userFilter.Language = current.Language
+		userFilter.CaptionLanguage = current.CaptionLanguage
+	}
+	return userFilter
+}
+
+func (b *Bot) handleFilterWizardCommand(message *tgbotapi.Message) {
+	userID := message.From.ID
+
+	categories, err := b.db.DistinctCategories(maxWizardCategories)
+	if err != nil {
+		log.Printf("Failed to get categories: %v", err)
+	}
+
+	w := newFilterWizard(categories)
+	b.wizards[userID] = w
+	delete(b.awaitingInput, userID)
+	b.sendWizardStep(message.Chat.ID, userID, w)
+}
+
+// handleFilterWizardCallback applies a wizard button press. Data is
+// "fw:cat:<index>", "fw:next", "fw:rating:<value>", "fw:skip" or "fw:cancel".
+func (b *Bot) handleFilterWizardCallback(callback *tgbotapi.CallbackQuery, args []string) string {
+	userID := callback.From.ID
+	w, ok := b.wizards[userID]
+	if !ok || callback.Message == nil || callback.Message.MessageID != w.messageID {
+		return "This setup has ended; use /filterwizard to start again"
+	}
+	chatID := callback.Message.Chat.ID
+
+	switch args[0] {
+	case "cancel":
+		b.endFilterWizard(userID)
+		b.send(tgbotapi.NewEditMessageText(chatID, w.messageID, "✖️ Filter setup cancelled. Your preferences are unchanged."))
+		return "Cancelled"
+	case "cat":
+		if len(args) < 2 {
+			return ""
+		}
+		i, err := strconv.Atoi(args[1])
+		if err != nil || !w.toggleCategory(i) {
+			return ""
+		}
+	case "next":
+		if w.step != wizardCategories {
+			return ""
+		}
+		w.advance()
+	case "rating":
+		if len(args) < 2 || !w.setRating(args[1]) {
+			return ""
+		}
+	case "skip":
+		if !w.setText("") {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	if w.step == wizardDone {
+		b.send(tgbotapi.NewEditMessageText(chatID, w.messageID, "✅ Filter setup complete."))
+		b.finishFilterWizard(chatID, userID, w)
+		return ""
+	}
+
+	text, keyboard := wizardStepMessage(w)
+	b.send(tgbotapi.NewEditMessageTextAndMarkup(chatID, w.messageID, text, keyboard))
+	b.syncWizardInput(userID, w)
+	return ""
+}
+
+// handleWizardInput takes typed keywords or exclusions for the user's wizard
+func (b *Bot) handleWizardInput(message *tgbotapi.Message) {
+	userID := message.From.ID
+	w, ok := b.wizards[userID]
+	if !ok {
+		return
+	}
+
+	text := strings.TrimSpace(message.Text)
+	if strings.EqualFold(text, "/cancel") || strings.EqualFold(text, "cancel") {
+		b.endFilterWizard(userID)
+		b.sendMessage(message.Chat.ID, "✖️ Filter setup cancelled. Your preferences are unchanged.")
+		return
+	}
+
+	if utf8.RuneCountInString(text) > security.MaxFilterStringLength {
+		b.sendMessage(message.Chat.ID, "❌ That's too long. Please send a shorter list, or tap Skip.")
+		b.syncWizardInput(userID, w)
+		return
+	}
+
+	w.setText(security.SanitizeString(text))
+	if w.step == wizardDone {
+		b.finishFilterWizard(message.Chat.ID, userID, w)
+		return
+	}
+	b.sendWizardStep(message.Chat.ID, userID, w)
+}
+
+// sendWizardStep sends the prompt for the wizard's current step as a new
+// message, which then carries the wizard's buttons
+func (b *Bot) sendWizardStep(chatID, userID int64, w *filterWizard) {
+	text, keyboard := wizardStepMessage(w)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	sent, err := b.send(msg)
+	if err != nil {
+		log.Printf("Failed to send filter wizard step: %v", err)
+		b.endFilterWizard(userID)
+		return
+	}
+	w.messageID = sent.MessageID
+	b.syncWizardInput(userID, w)
+}
+
+// syncWizardInput routes the user's next message to the wizard while a step
+// is waiting for typed input
+func (b *Bot) syncWizardInput(userID int64, w *filterWizard) {
+	if w.awaitsText() {
+		b.awaitingInput[userID] = "wizard"
+	} else {
+		delete(b.awaitingInput, userID)
+	}
+}
+
+// finishFilterWizard saves the assembled filter and confirms it
+func (b *Bot) finishFilterWizard(chatID, userID int64, w *filterWizard) {
+	b.endFilterWizard(userID)
+
+	current, _ := b.filterEngine.GetUserFilter(userID)
+	userFilter := w.filter(userID, current)
+	if err := b.filterEngine.SaveUserFilter(userFilter); err != nil {
+		b.sendMessage(chatID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save user filter: %v", err)
+		return
+	}
+
+	b.sendFilterSaved(chatID, userFilter)
+}
+
+func (b *Bot) endFilterWizard(userID int64) {
+	delete(b.wizards, userID)
+	if b.awaitingInput[userID] == "wizard" {
+		delete(b.awaitingInput, userID)
+	}
+}
+
+// wizardStepMessage renders the prompt and buttons for the current step
+func wizardStepMessage(w *filterWizard) (string, tgbotapi.InlineKeyboardMarkup) {
+	cancel := tgbotapi.NewInlineKeyboardButtonData("✖️ Cancel", "fw:cancel")
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var text string
+
+	switch w.step {
+	case wizardCategories:
+		text = "🧙 Filter setup (1/4)\n\n📂 Tap the categories you want, then Next. Choose none to get every category."
+		var row []tgbotapi.InlineKeyboardButton
+		for i, category := range w.categories {
+			label := category
+			if w.selected[i] {
+				label = "✅ " + label
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("fw:cat:%d", i)))
+			if len(row) == 2 {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Next ▶️", "fw:next"), cancel))
+	case wizardRating:
+		text = "🧙 Filter setup (2/4)\n\n⭐ Pick a minimum course rating:"
+		var row []tgbotapi.InlineKeyboardButton
+		for _, option := range ratingOptions {
+			label := "⭐ " + option
+			if option == "0" {
+				label = "Any"
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "fw:rating:"+option))
+			if len(row) == 4 {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(cancel))
+	case wizardKeywords:
+		text = "🧙 Filter setup (3/4)\n\n🔍 Type topics you want, comma-separated (e.g. python, web). Any one is enough; prefix with + to require it (e.g. +python). Or tap Skip."
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Skip ⏭", "fw:skip"), cancel))
+	case wizardExcluded:
+		text = "🧙 Filter setup (4/4)\n\n❌ Type topics to avoid, comma-separated (e.g. crypto, trading), or tap Skip."
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Skip ⏭", "fw:skip"), cancel))
+	}
+
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
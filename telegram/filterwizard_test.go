@@ -0,0 +1,168 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/filters"
+)
+
+func TestFilterWizardSteps(t *testing.T) {
+	w := newFilterWizard([]string{"Development", "Business", "Design"})
+	if w.step != wizardCategories || w.awaitsText() {
+		t.Fatalf("new wizard at step %q, want %q", w.step, wizardCategories)
+	}
+
+	if w.setRating("4.0") {
+		t.Error("setRating accepted a rating during the categories step")
+	}
+	if !w.toggleCategory(0) || !w.toggleCategory(2) || !w.toggleCategory(2) || !w.toggleCategory(1) {
+		t.Fatal("toggleCategory rejected a valid index")
+	}
+	if w.toggleCategory(3) || w.toggleCategory(-1) {
+		t.Error("toggleCategory accepted an out-of-range index")
+	}
+
+	w.advance()
+	if w.step != wizardRating {
+		t.Fatalf("after categories at step %q, want %q", w.step, wizardRating)
+	}
+	if w.toggleCategory(0) {
+		t.Error("toggleCategory accepted a category after the categories step")
+	}
+	for _, bad := range []string{"abc", "-1", "5.5"} {
+		if w.setRating(bad) {
+			t.Errorf("setRating(%q) accepted an invalid rating", bad)
+		}
+	}
+	if !w.setRating("4.5") || w.step != wizardKeywords || !w.awaitsText() {
+		t.Fatalf("after rating at step %q, want %q awaiting text", w.step, wizardKeywords)
+	}
+
+	if !w.setText("python | java, +go") || w.step != wizardExcluded {
+		t.Fatalf("after keywords at step %q, want %q", w.step, wizardExcluded)
+	}
+	if !w.setText("") || w.step != wizardDone || w.awaitsText() {
+		t.Fatalf("after skipping exclusions at step %q, want %q", w.step, wizardDone)
+	}
+	if w.setText("late") {
+		t.Error("setText accepted text after the wizard finished")
+	}
+
+	got := w.filter(42, &filters.UserFilter{Language: "es", CaptionLanguage: "fr"})
+	want := &filters.UserFilter{
+		UserID:           42,
+		Categories:       []string{"Development", "Business"},
+		Keywords:         []string{"python | java"},
+		RequiredKeywords: []string{"go"},
+		MinRating:        4.5,
+		Language:         "es",
+		CaptionLanguage:  "fr",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filter() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFilterWizardWithoutCategories(t *testing.T) {
+	w := newFilterWizard(nil)
+	if w.step != wizardRating {
+		t.Fatalf("wizard without categories starts at %q, want %q", w.step, wizardRating)
+	}
+
+	w.setRating("0")
+	w.setText("")
+	w.setText("crypto, , trading")
+
+	got := w.filter(7, nil)
+	if got.Language != "en" || got.Categories != nil || got.Keywords != nil {
+		t.Errorf("filter() = %+v, want defaults with no categories or keywords", got)
+	}
+	if want := []string{"crypto", "trading"}; !reflect.DeepEqual(got.ExcludedKeywords, want) {
+		t.Errorf("ExcludedKeywords = %q, want %q", got.ExcludedKeywords, want)
+	}
+}
+
+// tapWizard taps a button on the user's current wizard message
+func tapWizard(b *Bot, userID int64, data string) {
+	w := b.wizards[userID]
+	b.handleCallbackQuery(&tgbotapi.CallbackQuery{
+		ID:      data,
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{MessageID: w.messageID, Chat: &tgbotapi.Chat{ID: userID}},
+		Data:    data,
+	})
+}
+
+func TestFilterWizardSavesFilter(t *testing.T) {
+	b, _ := newTestBot(t)
+	addTestCourse(t, b.db, "go-basics", nil)
+	const userID = 42
+
+	b.handleMessage(testMessage(userID, "/filterwizard"))
+	if b.wizards[userID] == nil {
+		t.Fatal("/filterwizard did not start a wizard")
+	}
+	tapWizard(b, userID, "fw:cat:0")
+	tapWizard(b, userID, "fw:next")
+	tapWizard(b, userID, "fw:rating:4.0")
+	if b.awaitingInput[userID] != "wizard" {
+		t.Fatalf("keywords step not waiting for typed input, awaiting %q", b.awaitingInput[userID])
+	}
+	b.handleMessage(testMessage(userID, "golang"))
+	tapWizard(b, userID, "fw:skip")
+
+	if _, ok := b.wizards[userID]; ok {
+		t.Error("wizard still active after the last step")
+	}
+	if _, ok := b.awaitingInput[userID]; ok {
+		t.Error("still awaiting input after the wizard finished")
+	}
+	saved, err := b.filterEngine.GetUserFilter(userID)
+	if err != nil || saved == nil {
+		t.Fatalf("GetUserFilter = %v, %v", saved, err)
+	}
+	if !reflect.DeepEqual(saved.Categories, []string{"Development"}) || saved.MinRating != 4.0 || !reflect.DeepEqual(saved.Keywords, []string{"golang"}) {
+		t.Errorf("saved filter = %+v, want Development, 4.0 and golang", saved)
+	}
+}
+
+func TestFilterWizardCancel(t *testing.T) {
+	b, fake := newTestBot(t)
+	addTestCourse(t, b.db, "go-basics", nil)
+	const userID = 42
+
+	// Cancelling by button during the category step
+	b.handleMessage(testMessage(userID, "/filterwizard"))
+	tapWizard(b, userID, "fw:cat:0")
+	fake.reset()
+	tapWizard(b, userID, "fw:cancel")
+	if _, ok := b.wizards[userID]; ok {
+		t.Error("wizard still active after tapping cancel")
+	}
+	if answers := fake.sent("answerCallbackQuery"); len(answers) != 1 || answers[0].Params.Get("text") != "Cancelled" {
+		t.Errorf("cancel tap answered with %v", answers)
+	}
+
+	// Cancelling by typing while the wizard waits for keywords
+	b.handleMessage(testMessage(userID, "/filterwizard"))
+	tapWizard(b, userID, "fw:next")
+	tapWizard(b, userID, "fw:rating:3.5")
+	fake.reset()
+	b.handleMessage(testMessage(userID, "/cancel"))
+	if _, ok := b.wizards[userID]; ok {
+		t.Error("wizard still active after typing /cancel")
+	}
+	if _, ok := b.awaitingInput[userID]; ok {
+		t.Error("still awaiting input after typing /cancel")
+	}
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 || !strings.Contains(texts[0], "cancelled") {
+		t.Errorf("typed cancel replied %q", texts)
+	}
+
+	if saved, _ := b.filterEngine.GetUserFilter(userID); saved != nil && (saved.MinRating != 0 || saved.Categories != nil) {
+		t.Errorf("cancelled wizard saved a filter: %+v", saved)
+	}
+}
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// formatter renders message markup for one Telegram parse mode. Course
+// titles and descriptions come from scraped pages, so everything that is not
+// markup goes through escape to keep it from breaking the message.
+type formatter struct {
+	mode string
+}
+
+// SetParseMode selects the parse mode for every formatted message:
+// Markdown (default), MarkdownV2 or HTML
+func (b *Bot) SetParseMode(mode string) {
+	b.format = formatter{mode: mode}
+}
+
+// escape makes text safe to embed in a message in the current mode
+func (f formatter) escape(text string) string {
+	return tgbotapi.EscapeText(f.mode, text)
+}
+
+// bold renders text in bold, escaping it for the current mode
+func (f formatter) bold(text string) string {
+	switch f.mode {
+	case tgbotapi.ModeHTML:
+		return "<b>" + f.escape(text) + "</b>"
+	case tgbotapi.ModeMarkdownV2:
+		return "*" + f.escape(text) + "*"
+	default:
+		// Legacy Markdown has no escaping inside an entity
+		return "*" + strings.ReplaceAll(text, "*", "") + "*"
+	}
+}
+
+// price renders a discounted price with the original price next to it,
+// escaping both for the current mode. The original is struck through where
+// the mode supports it; legacy Markdown has no strikethrough, so it follows
+// the price in italics instead.
+func (f formatter) price(price, original string) string {
+	switch f.mode {
+	case tgbotapi.ModeHTML:
+		return "<s>" + f.escape(original) + "</s> " + f.escape(price)
+	case tgbotapi.ModeMarkdownV2:
+		return "~" + f.escape(original) + "~ " + f.escape(price)
+	default:
+		// Legacy Markdown has no escaping inside an entity
+		return f.escape(price) + " _(was " + strings.ReplaceAll(original, "_", "") + ")_"
+	}
+}
+
+// italic renders text in italics, escaping it for the current mode
+func (f formatter) italic(text string) string {
+	switch f.mode {
+	case tgbotapi.ModeHTML:
+		return "<i>" + f.escape(text) + "</i>"
+	case tgbotapi.ModeMarkdownV2:
+		return "_" + f.escape(text) + "_"
+	default:
+		// Legacy Markdown has no escaping inside an entity
+		return "_" + strings.ReplaceAll(text, "_", "") + "_"
+	}
+}
+
+// codeEscaper escapes the only characters special inside MarkdownV2 code
+var codeEscaper = strings.NewReplacer("\\", "\\\\", "`", "\\`")
+
+// code renders text in monospace, escaping it for the current mode
+func (f formatter) code(text string) string {
+	switch f.mode {
+	case tgbotapi.ModeHTML:
+		return "<code>" + f.escape(text) + "</code>"
+	case tgbotapi.ModeMarkdownV2:
+		return "`" + codeEscaper.Replace(text) + "`"
+	default:
+		// Legacy Markdown has no escaping inside an entity
+		return "`" + strings.ReplaceAll(text, "`", "") + "`"
+	}
+}
+
+// pre renders text as a monospace block, such as a table, escaping it for
+// the current mode
+func (f formatter) pre(text string) string {
+	switch f.mode {
+	case tgbotapi.ModeHTML:
+		return "<pre>" + f.escape(text) + "</pre>"
+	case tgbotapi.ModeMarkdownV2:
+		return "```\n" + codeEscaper.Replace(text) + "```"
+	default:
+		return "```\n" + strings.ReplaceAll(text, "`", "") + "```"
+	}
+}
@@ -0,0 +1,207 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/filters"
+)
+
+const specialTitle = "C++ & <STL> *Pro* [v2_1] (2024)!"
+
+func TestFormatterEscapesSpecialCharacters(t *testing.T) {
+	tests := []struct {
+		mode string
+		bold string
+	}{
+		{tgbotapi.ModeHTML, "<b>C++ &amp; &lt;STL&gt; *Pro* [v2_1] (2024)!</b>"},
+		{tgbotapi.ModeMarkdownV2, `*C\+\+ & <STL\> \*Pro\* \[v2\_1\] \(2024\)\!*`},
+		{tgbotapi.ModeMarkdown, "*C++ & <STL> Pro [v2_1] (2024)!*"},
+	}
+	for _, tt := range tests {
+		if got := (formatter{mode: tt.mode}).bold(specialTitle); got != tt.bold {
+			t.Errorf("%s: bold = %q, want %q", tt.mode, got, tt.bold)
+		}
+	}
+}
+
+func TestPostCourseInEachParseMode(t *testing.T) {
+	for _, mode := range []string{tgbotapi.ModeHTML, tgbotapi.ModeMarkdownV2, tgbotapi.ModeMarkdown} {
+		t.Run(mode, func(t *testing.T) {
+			b, fake := newTestBot(t)
+			b.SetParseMode(mode)
+			course := addTestCourse(t, b.db, "cpp-pro", func(c *database.Course) { c.Title = specialTitle })
+
+			if err := b.PostCourse(&course); err != nil {
+				t.Fatal(err)
+			}
+			calls := fake.sent("sendMessage")
+			if len(calls) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(calls))
+			}
+			if got := calls[0].Params.Get("parse_mode"); got != mode {
+				t.Errorf("parse_mode = %q, want %q", got, mode)
+			}
+			if text := calls[0].Params.Get("text"); !strings.Contains(text, (formatter{mode: mode}).bold(specialTitle)) {
+				t.Errorf("post lacks the escaped title:\n%s", text)
+			}
+		})
+	}
+}
+
+func TestFormatterPrice(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{tgbotapi.ModeHTML, "<s>$89.99</s> Free 100%"},
+		{tgbotapi.ModeMarkdownV2, `~$89\.99~ Free 100%`},
+		{tgbotapi.ModeMarkdown, "Free 100% _(was $89.99)_"},
+	}
+	for _, tt := range tests {
+		if got := (formatter{mode: tt.mode}).price("Free 100%", "$89.99"); got != tt.want {
+			t.Errorf("%s: price = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCourseMessageOriginalPrice(t *testing.T) {
+	for _, mode := range []string{tgbotapi.ModeHTML, tgbotapi.ModeMarkdownV2, tgbotapi.ModeMarkdown} {
+		b, _ := newTestBot(t)
+		b.SetParseMode(mode)
+		f := formatter{mode: mode}
+
+		course := &database.Course{Title: "Go Basics", Price: "Free", Discount: "100%", OriginalPrice: "$89.99"}
+		if text := b.formatCourseMessage(course, time.UTC); !strings.Contains(text, f.price("Free 100%", "$89.99")) {
+			t.Errorf("%s: message lacks the was/now price:\n%s", mode, text)
+		}
+
+		// Without a different original price only the current one is shown
+		for _, original := range []string{"", "Free"} {
+			course.OriginalPrice = original
+			text := b.formatCourseMessage(course, time.UTC)
+			if strings.Contains(text, "89") || strings.Contains(text, "was") || !strings.Contains(text, f.escape("Free 100%")) {
+				t.Errorf("%s: message with original price %q:\n%s", mode, original, text)
+			}
+		}
+	}
+}
+
+func TestFormatCourseMessageCertificate(t *testing.T) {
+	b, _ := newTestBot(t)
+	yes, no := true, false
+
+	tests := []struct {
+		certificate *bool
+		want        bool
+	}{
+		{&yes, true},
+		{&no, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		course := &database.Course{Title: "Docker", Price: "Free", Certificate: tt.certificate}
+		if got := strings.Contains(b.formatCourseMessage(course, time.UTC), "📜 Certificate"); got != tt.want {
+			t.Errorf("certificate %v: message shows the tag = %v, want %v", tt.certificate, got, tt.want)
+		}
+	}
+}
+
+func TestFormatterItalicAndCode(t *testing.T) {
+	tests := []struct {
+		mode   string
+		italic string
+		code   string
+		pre    string
+	}{
+		{tgbotapi.ModeHTML, "<i>a_b &amp; c</i>", "<code>a_b &amp; `c`</code>", "<pre>a_b &lt;c&gt;\n</pre>"},
+		{tgbotapi.ModeMarkdownV2, `_a\_b & c_`, "`a_b & \\`c\\``", "```\na_b <c>\n```"},
+		{tgbotapi.ModeMarkdown, "_ab & c_", "`a_b & c`", "```\na_b <c>\n```"},
+	}
+	for _, tt := range tests {
+		f := formatter{mode: tt.mode}
+		if got := f.italic("a_b & c"); got != tt.italic {
+			t.Errorf("%s: italic = %q, want %q", tt.mode, got, tt.italic)
+		}
+		if got := f.code("a_b & `c`"); got != tt.code {
+			t.Errorf("%s: code = %q, want %q", tt.mode, got, tt.code)
+		}
+		if got := f.pre("a_b <c>\n"); got != tt.pre {
+			t.Errorf("%s: pre = %q, want %q", tt.mode, got, tt.pre)
+		}
+	}
+}
+
+// TestMessagesUseParseMode sends each message that shows scraped or typed
+// text and checks it goes out in the configured mode with that text escaped
+func TestMessagesUseParseMode(t *testing.T) {
+	const adminID, userID = 1, 42
+	for _, mode := range []string{tgbotapi.ModeHTML, tgbotapi.ModeMarkdownV2, tgbotapi.ModeMarkdown} {
+		t.Run(mode, func(t *testing.T) {
+			b, fake := newTestBot(t)
+			b.SetParseMode(mode)
+			b.SetAdminIDs([]int64{adminID})
+			f := formatter{mode: mode}
+
+			special := addTestCourse(t, b.db, "cpp-pro", func(c *database.Course) {
+				c.Title = specialTitle
+				c.ExpiresAt = time.Now().Add(48 * time.Hour)
+			})
+			other := addTestCourse(t, b.db, "go-basics", nil)
+			for _, id := range []int{special.ID, other.ID} {
+				if err := b.db.AddToWishlist(userID, id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := b.db.AddReminder(userID, special.ID, time.Now().Add(-time.Minute)); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.filterEngine.SaveUserFilter(&filters.UserFilter{UserID: userID}); err != nil {
+				t.Fatal(err)
+			}
+
+			sends := []struct {
+				name   string
+				chatID int64
+				send   func()
+				want   string
+			}{
+				{"compare", userID, func() { b.handleMessage(testMessage(userID, "/compare "+formatIDs(special.ID, other.ID))) }, f.escape(specialTitle)},
+				{"reminder", userID, b.SendDueReminders, f.bold(specialTitle)},
+				{"trends", adminID, func() { b.handleMessage(testMessage(adminID, "/trends")) }, f.bold("Category Trends")},
+				{"filter saved", userID, func() {
+					b.sendFilterSaved(userID, &filters.UserFilter{UserID: userID, Keywords: []string{"c_sharp", "[net*"}})
+				}, f.escape("[c_sharp [net*]")},
+				{"export", userID, func() { b.handleMessage(testMessage(userID, "/exportfilter")) }, f.bold("Your filter code")},
+				// Last, since the prompt takes the user's next message as the filter
+				{"filter prompt", userID, func() { b.handleMessage(testMessage(userID, "/filter")) }, f.bold("Course Filter Settings")},
+			}
+			for _, s := range sends {
+				fake.reset()
+				s.send()
+				var call *apiCall
+				for _, c := range fake.sent("sendMessage") {
+					if c.Params.Get("chat_id") == fmt.Sprint(s.chatID) {
+						c := c
+						call = &c
+						break
+					}
+				}
+				if call == nil {
+					t.Errorf("%s: nothing sent", s.name)
+					continue
+				}
+				if got := call.Params.Get("parse_mode"); got != mode {
+					t.Errorf("%s: parse_mode = %q, want %q", s.name, got, mode)
+				}
+				if text := call.Params.Get("text"); !strings.Contains(text, s.want) {
+					t.Errorf("%s: text lacks %q:\n%s", s.name, s.want, text)
+				}
+			}
+		})
+	}
+}
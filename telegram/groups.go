@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Group chats subscribe like users: the group's chat ID takes the place of
+// a user ID in user_preferences, so matching courses are delivered to the
+// group under its own filter. Only the group's admins may change it.
+
+// isGroupChat reports whether a message came from a group or supergroup
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// addressedToOtherBot reports whether a command names another bot, as in
+// /start@OtherBot, which groups with several bots use to pick one
+func addressedToOtherBot(message *tgbotapi.Message, botName string) bool {
+	_, target, found := strings.Cut(message.CommandWithAt(), "@")
+	return found && !strings.EqualFold(target, botName)
+}
+
+// isGroupAdmin reports whether a user is an administrator or the creator of
+// a group, as Telegram reports it now
+func (b *Bot) isGroupAdmin(chatID, userID int64) (bool, error) {
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+	if err != nil {
+		return false, err
+	}
+	return member.IsAdministrator() || member.IsCreator(), nil
+}
+
+// requireGroupAdmin replies and returns false unless the sender administers
+// the group the message came from
+func (b *Bot) requireGroupAdmin(message *tgbotapi.Message) bool {
+	isAdmin, err := b.isGroupAdmin(message.Chat.ID, message.From.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Couldn't check your admin status. Please try again.")
+		log.Printf("Failed to get chat member %d in %d: %v", message.From.ID, message.Chat.ID, err)
+		return false
+	}
+	if !isAdmin {
+		b.sendMessage(message.Chat.ID, "⛔ Only group admins can change this group's course notifications.")
+		return false
+	}
+	return true
+}
+
+// handleGroupStartCommand subscribes the group to matching courses
+func (b *Bot) handleGroupStartCommand(message *tgbotapi.Message) {
+	if !b.requireGroupAdmin(message) {
+		return
+	}
+
+	added, err := b.db.AddSubscriber(message.Chat.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to subscribe this group. Please try again.")
+		log.Printf("Failed to subscribe group %d: %v", message.Chat.ID, err)
+		return
+	}
+
+	if !added {
+		b.sendMessage(message.Chat.ID, "✅ This group is already subscribed. Admins can change its filter with /filter <preferences>, or unsubscribe with /stop.")
+		return
+	}
+	b.sendMessage(message.Chat.ID, `✅ This group will now receive new free Udemy courses.
+
+Group admins can narrow them down with /filter, e.g.
+/filter Development | 4.0 | python, web | crypto
+
+Use /stop to unsubscribe the group.`)
+}
+
+// handleGroupFilterCommand sets the group's filter. Groups take the filter
+// inline, since waiting for the next message would catch other members' chat.
+func (b *Bot) handleGroupFilterCommand(message *tgbotapi.Message, args string) {
+	if !b.requireGroupAdmin(message) {
+		return
+	}
+
+	if args == "" {
+		b.sendMessage(message.Chat.ID, `Usage: /filter Categories | MinRating | Keywords | ExcludedKeywords | Captions
+Example: /filter Development, Business | 4.0 | programming, web | crypto, trading | es`)
+		return
+	}
+
+	b.processFilterInput(message.Chat.ID, message.Chat.ID, args)
+}
+
+// handleStopCommand unsubscribes a group, or in a private chat the user,
+// whose preferences are kept for when they save a filter again
+func (b *Bot) handleStopCommand(message *tgbotapi.Message) {
+	if !isGroupChat(message.Chat) {
+		b.handleUserStopCommand(message)
+		return
+	}
+	if !b.requireGroupAdmin(message) {
+		return
+	}
+
+	removed, err := b.db.RemoveSubscriber(message.Chat.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to unsubscribe this group. Please try again.")
+		log.Printf("Failed to unsubscribe group %d: %v", message.Chat.ID, err)
+		return
+	}
+	if !removed {
+		b.sendMessage(message.Chat.ID, "This group isn't subscribed. Use /start to subscribe it.")
+		return
+	}
+	b.sendMessage(message.Chat.ID, "👋 This group won't receive courses anymore. Use /start to subscribe again.")
+}
+
+// handleUserStopCommand stops direct messages to the sender
+func (b *Bot) handleUserStopCommand(message *tgbotapi.Message) {
+	stopped, err := b.db.Unsubscribe(message.From.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to unsubscribe you. Please try again.")
+		log.Printf("Failed to unsubscribe user %d: %v", message.From.ID, err)
+		return
+	}
+	if !stopped {
+		b.sendMessage(message.Chat.ID, "You aren't subscribed. Use /filter to start receiving courses.")
+		return
+	}
+	b.sendMessage(message.Chat.ID, "👋 You won't receive courses anymore. Your preferences are kept; save a filter with /filter to subscribe again.")
+}
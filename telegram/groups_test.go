@@ -0,0 +1,157 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+const testGroupID = -1005550001111
+
+// groupMessage builds a message sent to the test group by userID
+func groupMessage(userID int64, text string) *tgbotapi.Message {
+	message := testMessage(userID, text)
+	message.Chat = &tgbotapi.Chat{ID: testGroupID, Type: "supergroup", Title: "Learners"}
+	return message
+}
+
+func TestGroupChatType(t *testing.T) {
+	tests := []struct {
+		chatType string
+		want     bool
+	}{
+		{"group", true},
+		{"supergroup", true},
+		{"private", false},
+		{"channel", false},
+	}
+	for _, tt := range tests {
+		if got := isGroupChat(&tgbotapi.Chat{Type: tt.chatType}); got != tt.want {
+			t.Errorf("isGroupChat(%q) = %v, want %v", tt.chatType, got, tt.want)
+		}
+	}
+	if isGroupChat(nil) {
+		t.Error("isGroupChat(nil) = true")
+	}
+}
+
+func TestGroupCommandsNeedAdmin(t *testing.T) {
+	const admin, member = 100, 200
+	b, fake := newTestBot(t)
+	fake.statuses = map[int64]string{admin: "administrator"}
+
+	b.handleMessage(groupMessage(member, "/start"))
+	if texts := textsTo(fake.sent("sendMessage"), testGroupID); len(texts) != 1 || !strings.Contains(texts[0], "Only group admins") {
+		t.Errorf("non-admin /start replied %q", texts)
+	}
+	if subscribed, _ := b.db.GetSubscriberIDs(); len(subscribed) != 0 {
+		t.Fatalf("non-admin subscribed the group: %v", subscribed)
+	}
+
+	b.handleMessage(groupMessage(admin, "/start"))
+	b.handleMessage(groupMessage(admin, "/start"))
+	texts := textsTo(fake.sent("sendMessage"), testGroupID)
+	if len(texts) != 3 || !strings.Contains(texts[1], "will now receive") || !strings.Contains(texts[2], "already subscribed") {
+		t.Errorf("admin /start twice replied %q", texts[1:])
+	}
+	if subscribed, _ := b.db.GetSubscriberIDs(); len(subscribed) != 1 || subscribed[0] != testGroupID {
+		t.Fatalf("subscribers = %v, want the group", subscribed)
+	}
+
+	fake.reset()
+	b.handleMessage(groupMessage(member, "/filter Design"))
+	b.handleMessage(groupMessage(admin, "/filter Development | 4.0"))
+	saved, err := b.filterEngine.GetUserFilter(testGroupID)
+	if err != nil || saved == nil {
+		t.Fatalf("GetUserFilter(group) = %v, %v", saved, err)
+	}
+	if len(saved.Categories) != 1 || saved.Categories[0] != "Development" || saved.MinRating != 4.0 {
+		t.Errorf("group filter = %+v, want the admin's Development | 4.0", saved)
+	}
+	if userFilter, _ := b.filterEngine.GetUserFilter(member); userFilter != nil {
+		t.Errorf("group /filter saved a filter for the member: %+v", userFilter)
+	}
+
+	fake.reset()
+	b.handleMessage(groupMessage(member, "/stop"))
+	b.handleMessage(groupMessage(admin, "/stop"))
+	if subscribed, _ := b.db.GetSubscriberIDs(); len(subscribed) != 0 {
+		t.Errorf("subscribers after admin /stop = %v, want none", subscribed)
+	}
+	if texts := textsTo(fake.sent("sendMessage"), testGroupID); len(texts) != 2 || !strings.Contains(texts[0], "Only group admins") {
+		t.Errorf("/stop replied %q", texts)
+	}
+}
+
+func TestGroupAdminCheckFailure(t *testing.T) {
+	const creator = 100
+	b, fake := newTestBot(t)
+	fake.statuses = map[int64]string{creator: "creator"}
+	fake.failWith(func(call apiCall) (int, string) {
+		if call.Method == "getChatMember" {
+			return 400, "Bad Request: user not found"
+		}
+		return 0, ""
+	})
+
+	b.handleMessage(groupMessage(creator, "/start"))
+	if texts := textsTo(fake.sent("sendMessage"), testGroupID); len(texts) != 1 || !strings.Contains(texts[0], "Couldn't check") {
+		t.Errorf("/start with a failing admin check replied %q", texts)
+	}
+	if subscribed, _ := b.db.GetSubscriberIDs(); len(subscribed) != 0 {
+		t.Errorf("group subscribed without a successful admin check: %v", subscribed)
+	}
+
+	fake.failWith(nil)
+	b.handleMessage(groupMessage(creator, "/start"))
+	if subscribed, _ := b.db.GetSubscriberIDs(); len(subscribed) != 1 {
+		t.Errorf("the group's creator could not subscribe it: %v", subscribed)
+	}
+}
+
+func TestGroupIgnoresCommandsForOtherBots(t *testing.T) {
+	const admin = 100
+	b, fake := newTestBot(t)
+	fake.statuses = map[int64]string{admin: "administrator"}
+
+	b.handleMessage(groupMessage(admin, "/start@OtherBot"))
+	if calls := len(fake.sent("getChatMember")) + len(fake.sent("sendMessage")); calls != 0 {
+		t.Errorf("command for another bot made %d calls", calls)
+	}
+
+	b.handleMessage(groupMessage(admin, "/start@test_bot"))
+	if subscribed, _ := b.db.GetSubscriberIDs(); len(subscribed) != 1 {
+		t.Errorf("command addressed to this bot did not subscribe the group: %v", subscribed)
+	}
+}
+
+func TestNotifySubscribersDeliversToGroups(t *testing.T) {
+	const admin = 100
+	b, fake := newTestBot(t)
+	fake.statuses = map[int64]string{admin: "administrator"}
+	b.handleMessage(groupMessage(admin, "/start"))
+	b.handleMessage(groupMessage(admin, "/filter Development"))
+
+	match := addTestCourse(t, b.db, "go-basics", nil)
+	other := addTestCourse(t, b.db, "watercolor", func(c *database.Course) { c.Category = "Design" })
+
+	fake.reset()
+	b.NotifySubscribers(&match)
+	b.NotifySubscribers(&other)
+	texts := textsTo(fake.sent("sendMessage"), testGroupID)
+	if len(texts) != 1 || !strings.Contains(texts[0], match.Title) {
+		t.Errorf("group received %q, want only %q", texts, match.Title)
+	}
+
+	// A group that removed the bot is unsubscribed
+	fake.failWith(func(call apiCall) (int, string) {
+		return 403, "Forbidden: bot was kicked from the supergroup chat"
+	})
+	next := addTestCourse(t, b.db, "go-advanced", nil)
+	b.NotifySubscribers(&next)
+	if subscribed, _ := b.db.GetSubscriberIDs(); len(subscribed) != 0 {
+		t.Errorf("subscribers after the bot was kicked = %v, want none", subscribed)
+	}
+}
@@ -0,0 +1,40 @@
+package telegram
+
+import (
+	"fmt"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+// SetHurryWindow makes channel posts for coupons expiring sooner than window
+// open with a hurry banner. 0 turns the banner off.
+func (b *Bot) SetHurryWindow(window time.Duration) {
+	b.hurryWindow = window
+}
+
+// hurryBanner returns the line put above a channel post whose coupon is
+// about to expire, or "" when there's still time
+func (b *Bot) hurryBanner(course *database.Course) string {
+	if !database.ExpiresWithin(course, b.hurryWindow, time.Now()) {
+		return ""
+	}
+	left := time.Until(course.ExpiresAt).Round(time.Minute)
+	if left < time.Minute {
+		return b.format.bold("🔥 HURRY: this coupon is expiring now!") + "\n\n"
+	}
+	return b.format.bold(fmt.Sprintf("🔥 HURRY: this coupon expires in %s!", formatLeft(left))) + "\n\n"
+}
+
+// formatLeft renders a short remaining time such as "45m" or "2h 10m"
+func formatLeft(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
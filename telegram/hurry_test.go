@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+func TestPostCourseHurryBanner(t *testing.T) {
+	b, fake := newTestBot(t)
+	b.SetHurryWindow(time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		want      string // "" when no banner is expected
+	}{
+		{"expiring within the window", 45 * time.Minute, "HURRY: this coupon expires in 45m"},
+		{"expiring any moment", 20 * time.Second, "HURRY: this coupon is expiring now"},
+		{"plenty of time left", 3 * time.Hour, ""},
+	}
+	for _, tt := range tests {
+		course := addTestCourse(t, b.db, strings.ReplaceAll(tt.name, " ", "-"), func(c *database.Course) {
+			c.URL += "?couponCode=FREE"
+			c.ExpiresAt = time.Now().Add(tt.expiresIn)
+		})
+
+		fake.reset()
+		if err := b.PostCourse(&course); err != nil {
+			t.Fatal(err)
+		}
+		posts := textsTo(fake.sent("sendMessage"), testChannelID)
+		if len(posts) != 1 {
+			t.Fatalf("%s: posted %d messages, want 1", tt.name, len(posts))
+		}
+		if hasBanner := strings.Contains(posts[0], "HURRY"); hasBanner != (tt.want != "") || !strings.Contains(posts[0], tt.want) {
+			t.Errorf("%s: post = %q, want banner %q", tt.name, posts[0], tt.want)
+		}
+	}
+}
+
+func TestFormatLeft(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Minute, "45m"},
+		{2 * time.Hour, "2h"},
+		{2*time.Hour + 10*time.Minute, "2h 10m"},
+	}
+	for _, tt := range tests {
+		if got := formatLeft(tt.d); got != tt.want {
+			t.Errorf("formatLeft(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
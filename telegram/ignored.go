@@ -0,0 +1,190 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleResetIgnoredCommand asks the user to confirm clearing their
+// "Not Interested" list
+func (b *Bot) handleResetIgnoredCommand(message *tgbotapi.Message) {
+	count, err := b.getIgnoredCount(message.From.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load your ignored courses.")
+		log.Printf("Failed to count ignored courses: %v", err)
+		return
+	}
+	if count == 0 {
+		b.sendMessage(message.Chat.ID, "You haven't marked any courses as Not Interested.")
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(
+		"🔄 Reset %d courses you marked Not Interested? They can be sent to you again if they match your filter.", count))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Reset", "resetignored:yes"),
+			tgbotapi.NewInlineKeyboardButtonData("Cancel", "resetignored:no"),
+		),
+	)
+	b.api.Send(msg)
+}
+
+// handleResetIgnoredCallback clears the user's ignored courses once confirmed
+// and replaces the prompt with the outcome
+func (b *Bot) handleResetIgnoredCallback(callback *tgbotapi.CallbackQuery, choice string) string {
+	if callback.Message == nil {
+		return ""
+	}
+
+	text := "Reset cancelled. Your ignored courses are unchanged."
+	answer := "Cancelled"
+	if choice == "yes" {
+		restored, err := b.db.ClearIgnored(callback.From.ID)
+		if err != nil {
+			log.Printf("Failed to clear ignored courses: %v", err)
+			return "❌ Failed to reset, please try again"
+		}
+		text = fmt.Sprintf("✅ Restored %d courses. They can be sent to you again.", restored)
+		answer = "Reset"
+	}
+
+	b.api.Send(tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, text))
+	return answer
+}
+
+// handleIgnoredCommand lists the courses the user marked "Not Interested",
+// with a button to restore each one
+func (b *Bot) handleIgnoredCommand(message *tgbotapi.Message) {
+	text, keyboard, err := b.ignoredPage(message.From.ID, 0)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load your ignored courses.")
+		log.Printf("Failed to get ignored courses: %v", err)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ParseMode = b.format.mode
+	msg.DisableWebPagePreview = true
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	b.send(msg)
+}
+
+// handleIgnoredPageCallback swaps the /ignored message for the page at
+// offset. Data is "ignored:<offset>".
+func (b *Bot) handleIgnoredPageCallback(callback *tgbotapi.CallbackQuery, offsetStr string) string {
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 || callback.Message == nil {
+		return ""
+	}
+
+	b.showIgnoredPage(callback, offset)
+	return ""
+}
+
+// handleUnignoreCallback restores one ignored course and redraws the page it
+// was on. Data is "unignore:<course ID>:<offset>".
+func (b *Bot) handleUnignoreCallback(callback *tgbotapi.CallbackQuery, parts []string) string {
+	if len(parts) < 3 || callback.Message == nil {
+		return ""
+	}
+	courseID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return ""
+	}
+	offset, err := strconv.Atoi(parts[2])
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	if _, err := b.db.UnignoreCourse(callback.From.ID, courseID); err != nil {
+		log.Printf("Failed to unignore course: %v", err)
+		return "❌ Failed to restore, please try again"
+	}
+
+	b.showIgnoredPage(callback, offset)
+	return "↩️ Restored; it can be sent to you again"
+}
+
+// showIgnoredPage edits a callback's message to show the page at offset,
+// stepping back a page if that one is now empty
+func (b *Bot) showIgnoredPage(callback *tgbotapi.CallbackQuery, offset int) {
+	if offset > 0 {
+		if rest, err := b.db.GetIgnoredCourses(callback.From.ID, 1, offset); err == nil && len(rest) == 0 {
+			offset -= b.pageSize(callback.From.ID)
+			if offset < 0 {
+				offset = 0
+			}
+		}
+	}
+
+	text, keyboard, err := b.ignoredPage(callback.From.ID, offset)
+	if err != nil {
+		log.Printf("Failed to get ignored courses: %v", err)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, text)
+	edit.ParseMode = b.format.mode
+	edit.DisableWebPagePreview = true
+	edit.ReplyMarkup = keyboard
+	b.send(edit)
+}
+
+// ignoredPage renders one page of the user's ignored courses with an
+// Un-ignore button per course and Prev/Next buttons
+func (b *Bot) ignoredPage(userID int64, offset int) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	size := b.pageSize(userID)
+
+	// Fetch one extra course to learn whether a next page exists
+	courses, err := b.db.GetIgnoredCourses(userID, size+1, offset)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hasNext := len(courses) > size
+	if hasNext {
+		courses = courses[:size]
+	}
+
+	header := "🙈 " + b.format.bold("Not Interested") + "\n\n"
+	if len(courses) == 0 {
+		return header + b.format.escape("You haven't marked any courses as Not Interested."), nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, course := range courses {
+		sb.WriteString(b.format.escape(fmt.Sprintf("%d. ", offset+i+1)) + b.format.bold(course.Title) +
+			b.format.escape(fmt.Sprintf(" (#%d)\n", course.ID)))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("↩️ Un-ignore #%d", course.ID),
+				fmt.Sprintf("unignore:%d:%d", course.ID, offset)),
+		))
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if offset > 0 {
+		prev := offset - size
+		if prev < 0 {
+			prev = 0
+		}
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("◀️ Prev", fmt.Sprintf("ignored:%d", prev)))
+	}
+	if hasNext {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("Next ▶️", fmt.Sprintf("ignored:%d", offset+size)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return sb.String(), &keyboard, nil
+}
@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+func TestResetIgnored(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID, otherID = 42, 7
+	first := addTestCourse(t, b.db, "first", nil)
+	second := addTestCourse(t, b.db, "second", nil)
+	for _, ignore := range []struct {
+		userID   int64
+		courseID int
+	}{{userID, first.ID}, {userID, second.ID}, {otherID, first.ID}} {
+		if err := b.db.IgnoreCourse(ignore.userID, ignore.courseID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b.handleResetIgnoredCommand(testMessage(userID, "/resetignored"))
+	prompts := fake.sent("sendMessage")
+	if len(prompts) != 1 || !strings.Contains(prompts[0].Params.Get("text"), "Reset 2 courses") ||
+		!strings.Contains(prompts[0].Params.Get("reply_markup"), "resetignored:yes") {
+		t.Fatalf("prompt = %v, want a confirmation for 2 courses", prompts)
+	}
+
+	answer := func(choice string) string {
+		fake.reset()
+		b.handleCallbackQuery(&tgbotapi.CallbackQuery{
+			ID:      "reset",
+			From:    &tgbotapi.User{ID: userID},
+			Message: &tgbotapi.Message{MessageID: 9, Chat: &tgbotapi.Chat{ID: userID}},
+			Data:    "resetignored:" + choice,
+		})
+		edits := fake.sent("editMessageText")
+		if len(edits) != 1 {
+			t.Fatalf("%s: edited %d messages, want the prompt", choice, len(edits))
+		}
+		return edits[0].Params.Get("text")
+	}
+	ignored := func(userID int64, courseID int) bool {
+		ignored, err := b.db.IsIgnored(userID, courseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ignored
+	}
+
+	if text := answer("no"); !strings.Contains(text, "cancelled") || !ignored(userID, first.ID) {
+		t.Errorf("cancel = %q, want the courses kept ignored", text)
+	}
+
+	if text := answer("yes"); !strings.Contains(text, "Restored 2 courses") {
+		t.Errorf("confirm = %q, want 2 restored", text)
+	}
+	if ignored(userID, first.ID) || ignored(userID, second.ID) {
+		t.Error("courses still ignored after the reset")
+	}
+	if !ignored(otherID, first.ID) {
+		t.Error("reset cleared another user's ignored course")
+	}
+
+	fake.reset()
+	b.handleResetIgnoredCommand(testMessage(userID, "/resetignored"))
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 || !strings.Contains(texts[0], "haven't marked any") {
+		t.Errorf("second /resetignored = %q, want nothing to reset", texts)
+	}
+}
+
+func TestIgnoredListingAndUnignore(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	if err := b.filterEngine.SetPageSize(userID, 2); err != nil {
+		t.Fatal(err)
+	}
+	var courses []database.Course
+	for _, slug := range []string{"first", "second", "third"} {
+		course := addTestCourse(t, b.db, slug, nil)
+		if err := b.db.IgnoreCourse(userID, course.ID); err != nil {
+			t.Fatal(err)
+		}
+		courses = append(courses, course)
+	}
+
+	b.handleMessage(testMessage(userID, "/ignored"))
+	sent := fake.sent("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want the listing", len(sent))
+	}
+	text, markup := sent[0].Params.Get("text"), sent[0].Params.Get("reply_markup")
+	if !strings.Contains(text, "Course third") || !strings.Contains(text, "Course second") || strings.Contains(text, "Course first") {
+		t.Errorf("first page lists the wrong courses:\n%s", text)
+	}
+	if !strings.Contains(markup, fmt.Sprintf("unignore:%d:0", courses[2].ID)) || !strings.Contains(markup, "ignored:2") {
+		t.Errorf("first page buttons = %s, want Un-ignore buttons and Next", markup)
+	}
+
+	// Un-ignoring the only course on the last page steps back a page
+	fake.reset()
+	b.handleCallbackQuery(&tgbotapi.CallbackQuery{
+		ID:      "unignore",
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{MessageID: 9, Chat: &tgbotapi.Chat{ID: userID}},
+		Data:    fmt.Sprintf("unignore:%d:2", courses[0].ID),
+	})
+	if ignored, err := b.db.IsIgnored(userID, courses[0].ID); err != nil || ignored {
+		t.Errorf("IsIgnored after un-ignoring = %v, %v; want false", ignored, err)
+	}
+	answers := fake.sent("answerCallbackQuery")
+	if len(answers) != 1 || !strings.Contains(answers[0].Params.Get("text"), "Restored") {
+		t.Errorf("callback answers = %v, want a restored notice", answers)
+	}
+	edits := fake.sent("editMessageText")
+	if len(edits) != 1 {
+		t.Fatalf("edited %d messages, want the listing redrawn", len(edits))
+	}
+	if text := edits[0].Params.Get("text"); !strings.Contains(text, "Course third") || strings.Contains(text, "Course first") {
+		t.Errorf("redrawn listing:\n%s\nwant the first page without the restored course", text)
+	}
+	if markup := edits[0].Params.Get("reply_markup"); strings.Contains(markup, `"ignored:`) {
+		t.Errorf("redrawn buttons = %s, want no Prev or Next on the only page", markup)
+	}
+}
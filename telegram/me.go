@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/filters"
+)
+
+// handleMeCommand shows every preference the user has set in one message,
+// so they can see why courses are or aren't reaching them
+func (b *Bot) handleMeCommand(message *tgbotapi.Message) {
+	userID := message.From.ID
+
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		userFilter = nil
+	} else if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load your preferences. Please try again.")
+		log.Printf("Failed to get user filter: %v", err)
+		return
+	}
+
+	counts, err := b.db.GetUserItemCounts(userID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load your preferences. Please try again.")
+		log.Printf("Failed to get user item counts: %v", err)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, formatMe(userFilter, counts, b.inQuietHours(userID), b.dailyLimitReached(userID)))
+}
+
+// formatMe renders a user's preferences; userFilter is nil for users who
+// have never set any
+func formatMe(userFilter *filters.UserFilter, counts *database.UserItemCounts, quietNow, limitReached bool) string {
+	var sb strings.Builder
+	sb.WriteString("👤 Your Preferences\n\n")
+
+	switch {
+	case userFilter == nil || !userFilter.Subscribed:
+		sb.WriteString("🔕 Notifications: off - save a filter with /filter to start receiving courses\n")
+	case quietNow:
+		sb.WriteString("🔔 Notifications: paused for quiet hours\n")
+	case limitReached:
+		sb.WriteString("🔔 Notifications: paused until tomorrow (daily limit reached)\n")
+	default:
+		sb.WriteString("🔔 Notifications: on\n")
+	}
+
+	if userFilter != nil {
+		sb.WriteString("\n")
+
+		fmt.Fprintf(&sb, "📂 Categories: %s\n", listOrAny(userFilter.Categories))
+		fmt.Fprintf(&sb, "🔍 Keywords (any): %s\n", listOrAny(userFilter.Keywords))
+		fmt.Fprintf(&sb, "➕ Required (all): %s\n", listOrNone(userFilter.RequiredKeywords))
+		fmt.Fprintf(&sb, "❌ Excluded: %s\n", listOrNone(userFilter.ExcludedKeywords))
+		fmt.Fprintf(&sb, "📈 Min rating: %s\n", formatMinRating(userFilter.MinRating))
+		fmt.Fprintf(&sb, "🌐 Language: %s\n", valueOr(userFilter.Language, "any"))
+		fmt.Fprintf(&sb, "💬 Captions: %s\n", valueOr(userFilter.CaptionLanguage, "any"))
+
+		maxPrice := "none"
+		if userFilter.MaxPrice > 0 {
+			maxPrice = fmt.Sprintf("%.2f %s", userFilter.MaxPrice, userFilter.Currency)
+		}
+		fmt.Fprintf(&sb, "💰 Max price: %s\n", maxPrice)
+
+		fmt.Fprintf(&sb, "🕐 Timezone: %s\n", valueOr(userFilter.Timezone, "default"))
+
+		quiet := "off"
+		if userFilter.QuietStart != "" {
+			quiet = userFilter.QuietStart + "-" + userFilter.QuietEnd
+		}
+		fmt.Fprintf(&sb, "🌙 Quiet hours: %s\n", quiet)
+
+		maxPerDay := "no limit"
+		if userFilter.MaxPerDay > 0 {
+			maxPerDay = fmt.Sprintf("%d", userFilter.MaxPerDay)
+		}
+		fmt.Fprintf(&sb, "📮 Max per day: %s\n", maxPerDay)
+		fmt.Fprintf(&sb, "⏰ Wishlist reminders: %s\n", onOff(userFilter.RemindAll))
+		fmt.Fprintf(&sb, "📋 Digest order: %s\n", valueOr(userFilter.DigestSort, DigestSortExpiry))
+		fmt.Fprintf(&sb, "🗂 Expired courses in /browse: %s\n", onOff(userFilter.ShowExpired))
+		fmt.Fprintf(&sb, "📜 Certificate required: %s\n", onOff(userFilter.RequireCertificate))
+		fmt.Fprintf(&sb, "📄 Page size: %d\n", clampPageSize(userFilter.PageSize))
+	}
+
+	fmt.Fprintf(&sb, "\n💾 Wishlist: %d\n", counts.Wishlist)
+	fmt.Fprintf(&sb, "🙈 Not interested: %d\n", counts.Ignored)
+	fmt.Fprintf(&sb, "⏰ Pending reminders: %d\n", counts.Reminders)
+	fmt.Fprintf(&sb, "📥 Held for quiet hours or the daily limit: %d\n", counts.Held)
+	fmt.Fprintf(&sb, "📬 Unread: %d", counts.Unread)
+
+	return sb.String()
+}
+
+func listOrAny(values []string) string {
+	if len(values) == 0 {
+		return "any"
+	}
+	return strings.Join(values, ", ")
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
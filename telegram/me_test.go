@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/filters"
+)
+
+func TestMeCommandShowsMixedPreferences(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	err := b.filterEngine.SaveUserFilter(&filters.UserFilter{
+		UserID:           userID,
+		Categories:       []string{"Development", "Design"},
+		Keywords:         []string{"golang"},
+		RequiredKeywords: []string{"docker"},
+		ExcludedKeywords: []string{"beginner"},
+		MinRating:        4.2,
+		Language:         "es",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, set := range []func() error{
+		func() error { return b.filterEngine.SetMaxPrice(userID, 9.99, "eur") },
+		func() error { return b.filterEngine.SetTimezone(userID, "Europe/Madrid") },
+		func() error { return b.filterEngine.SetMaxPerDay(userID, 5) },
+		func() error { return b.filterEngine.SetRemindAll(userID, true) },
+	} {
+		if err := set(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved := addTestCourse(t, b.db, "saved", nil)
+	ignored := addTestCourse(t, b.db, "ignored", nil)
+	if err := b.db.AddToWishlist(userID, saved.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.db.AddReminder(userID, saved.ID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.db.IgnoreCourse(userID, ignored.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	b.handleMessage(testMessage(userID, "/me"))
+
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 {
+		t.Fatalf("sent %d replies, want 1", len(texts))
+	}
+	for _, want := range []string{
+		"Notifications: on",
+		"Categories: Development, Design",
+		"Keywords (any): golang",
+		"Required (all): docker",
+		"Excluded: beginner",
+		"Language: es",
+		"Max price: 9.99 EUR",
+		"Timezone: Europe/Madrid",
+		"Quiet hours: off",
+		"Max per day: 5",
+		"Wishlist reminders: on",
+		"Wishlist: 1",
+		"Not interested: 1",
+		"Pending reminders: 1",
+	} {
+		if !strings.Contains(texts[0], want) {
+			t.Errorf("/me reply lacks %q:\n%s", want, texts[0])
+		}
+	}
+}
+
+func TestMeCommandWithoutPreferences(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	b.handleMessage(testMessage(userID, "/me"))
+
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 || !strings.Contains(texts[0], "Notifications: off") || strings.Contains(texts[0], "Categories:") {
+		t.Errorf("/me for a new user replied %q, want notifications off and no filter details", texts)
+	}
+}
+
+func TestFormatMeNotificationState(t *testing.T) {
+	userFilter := &filters.UserFilter{Subscribed: true, QuietStart: "23:00", QuietEnd: "07:00"}
+	counts := &database.UserItemCounts{}
+
+	tests := []struct {
+		quietNow, limitReached bool
+		want                   string
+	}{
+		{false, false, "Notifications: on"},
+		{true, false, "paused for quiet hours"},
+		{false, true, "daily limit reached"},
+	}
+	for _, tt := range tests {
+		text := formatMe(userFilter, counts, tt.quietNow, tt.limitReached)
+		if !strings.Contains(text, tt.want) || !strings.Contains(text, "Quiet hours: 23:00-07:00") {
+			t.Errorf("formatMe(quiet %v, limit %v) lacks %q or the quiet hours:\n%s", tt.quietNow, tt.limitReached, tt.want, text)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// NotifySubscribers sends a course by direct message to every user whose
+// filter matches it. Each user receives a given course at most once.
+func (b *Bot) NotifySubscribers(course *database.Course) {
+	userIDs, err := b.db.GetSubscriberIDs()
+	if err != nil {
+		log.Printf("Failed to get subscribers: %v", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		delivered, err := b.db.WasDelivered(userID, course.ID)
+		if err != nil {
+			log.Printf("Failed to check delivery for user %d: %v", userID, err)
+			continue
+		}
+		if delivered {
+			continue
+		}
+
+		matches, err := b.filterEngine.ShouldNotifyCourse(course, userID)
+		if err != nil {
+			log.Printf("Failed to apply filter for user %d: %v", userID, err)
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		if b.inQuietHours(userID) {
+			if b.quietMode == QuietHoursDrop {
+				continue
+			}
+			if err := b.db.HoldNotification(userID, course.ID); err != nil {
+				log.Printf("Failed to hold notification for user %d: %v", userID, err)
+			}
+			continue
+		}
+
+		if b.dailyLimitReached(userID) {
+			if b.dailyLimitMode == DailyLimitDrop {
+				continue
+			}
+			if err := b.db.HoldNotification(userID, course.ID); err != nil {
+				log.Printf("Failed to hold notification for user %d: %v", userID, err)
+			}
+			continue
+		}
+
+		if err := b.sendCourseToUser(userID, course); err != nil {
+			log.Printf("Failed to notify user %d: %v", userID, err)
+			// Group chat IDs are negative; a group that removed the bot is dropped
+			if userID < 0 && isChannelGoneError(err) {
+				if _, err := b.db.RemoveSubscriber(userID); err != nil {
+					log.Printf("Failed to unsubscribe group %d: %v", userID, err)
+				} else {
+					log.Printf("Unsubscribed group %d: the bot can no longer post there", userID)
+				}
+			}
+		}
+	}
+}
+
+// sendCourseToUser sends a course by direct message and records the delivery
+func (b *Bot) sendCourseToUser(userID int64, course *database.Course) error {
+	msg := tgbotapi.NewMessage(userID, b.formatCourseMessage(course, b.userLocation(userID)))
+	msg.ParseMode = b.format.mode
+	msg.ReplyMarkup = b.courseKeyboard(course)
+	msg.DisableWebPagePreview = true
+	b.dms.wait()
+	if _, err := b.send(msg); err != nil {
+		return err
+	}
+
+	if err := b.db.MarkDelivered(userID, course.ID); err != nil {
+		log.Printf("Failed to mark course delivered: %v", err)
+	}
+	b.recordDailySend(userID)
+	return nil
+}
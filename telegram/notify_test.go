@@ -0,0 +1,25 @@
+package telegram
+
+import "testing"
+
+func TestNotifySubscribersDeliversOnce(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	if _, err := b.db.AddSubscriber(userID); err != nil {
+		t.Fatal(err)
+	}
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	b.NotifySubscribers(&course)
+	b.NotifySubscribers(&course)
+
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 {
+		t.Errorf("user received %d messages for one course, want 1", len(texts))
+	}
+
+	other := addTestCourse(t, b.db, "rust-basics", nil)
+	b.NotifySubscribers(&other)
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 2 {
+		t.Errorf("user received %d messages for two courses, want 2", len(texts))
+	}
+}
@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultDMInterval keeps direct messages under Telegram's limit of about
+// 30 messages a second per bot
+const defaultDMInterval = 50 * time.Millisecond
+
+// dmPacer spaces out direct messages sent in bulk, such as a new course
+// going to every subscriber. It is shared by all goroutines that send them.
+type dmPacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // Earliest time the next message may go out
+}
+
+// wait blocks until the caller's turn to send, reserving the following slot
+// for the next caller
+func (p *dmPacer) wait() {
+	p.mu.Lock()
+	now := time.Now()
+	slot := p.next
+	if slot.Before(now) {
+		slot = now
+	}
+	p.next = slot.Add(p.interval)
+	p.mu.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
+
+// SetDMInterval sets the minimum gap between direct messages sent to
+// subscribers
+func (b *Bot) SetDMInterval(interval time.Duration) {
+	b.dms.mu.Lock()
+	b.dms.interval = interval
+	b.dms.mu.Unlock()
+}
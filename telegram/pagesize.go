@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// defaultPageSize is how many items paginated lists show unless a user
+	// picks another size with /pagesize
+	defaultPageSize = 5
+	// maxPageSize keeps pages within Telegram's message length and
+	// inline keyboard limits
+	maxPageSize = 10
+)
+
+const pageSizeUsage = "Usage: /pagesize <count>, e.g. /pagesize 8\nUse /pagesize default to go back to the default."
+
+// pageSize returns how many items the user's paginated lists (/wishlist,
+// /ignored, /browse) show at once
+func (b *Bot) pageSize(userID int64) int {
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	if err != nil {
+		return defaultPageSize
+	}
+	return clampPageSize(userFilter.PageSize)
+}
+
+// clampPageSize maps a stored page size to one that can be shown, with 0
+// meaning the default
+func clampPageSize(size int) int {
+	if size <= 0 {
+		return defaultPageSize
+	}
+	if size > maxPageSize {
+		return maxPageSize
+	}
+	return size
+}
+
+func (b *Bot) handlePageSizeCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+	args = strings.TrimSpace(args)
+
+	if args == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("📄 Lists show %d items per page.\n\n%s", b.pageSize(userID), pageSizeUsage))
+		return
+	}
+
+	size := 0
+	if !strings.EqualFold(args, "default") {
+		n, err := strconv.Atoi(args)
+		if err != nil || n < 1 || n > maxPageSize {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ The page size must be a whole number from 1 to %d.\n%s", maxPageSize, pageSizeUsage))
+			return
+		}
+		size = n
+	}
+
+	if err := b.filterEngine.SetPageSize(userID, size); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save page size: %v", err)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("📄 /wishlist, /ignored and /browse will show %d items per page.", clampPageSize(size)))
+}
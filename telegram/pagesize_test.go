@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestClampPageSize(t *testing.T) {
+	tests := []struct{ size, want int }{
+		{0, defaultPageSize},
+		{-3, defaultPageSize},
+		{1, 1},
+		{maxPageSize, maxPageSize},
+		{maxPageSize + 5, maxPageSize},
+	}
+	for _, tt := range tests {
+		if got := clampPageSize(tt.size); got != tt.want {
+			t.Errorf("clampPageSize(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestPageSizeCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	tests := []struct {
+		args     string
+		wantText string
+		wantSize int
+	}{
+		{"", "5 items per page", defaultPageSize},
+		{"0", "from 1 to 10", defaultPageSize},
+		{"11", "from 1 to 10", defaultPageSize},
+		{"many", "from 1 to 10", defaultPageSize},
+		{"3", "show 3 items per page", 3},
+		{"", "3 items per page", 3},
+		{"default", "show 5 items per page", defaultPageSize},
+	}
+	for _, tt := range tests {
+		fake.reset()
+		b.handleMessage(testMessage(userID, strings.TrimSpace("/pagesize "+tt.args)))
+		if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 || !strings.Contains(texts[0], tt.wantText) {
+			t.Errorf("/pagesize %q replied %q, want %q", tt.args, texts, tt.wantText)
+		}
+		if got := b.pageSize(userID); got != tt.wantSize {
+			t.Errorf("after /pagesize %q the page size is %d, want %d", tt.args, got, tt.wantSize)
+		}
+	}
+}
+
+func TestPaginationHonorsPageSize(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	if err := b.filterEngine.SetPageSize(userID, 3); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		course := addTestCourse(t, b.db, fmt.Sprintf("course-%d", i), nil)
+		if err := b.db.AddToWishlist(userID, course.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b.handleMessage(testMessage(userID, "/wishlist"))
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 4 || !strings.Contains(texts[3], "and 4 more") {
+		t.Errorf("/wishlist sent %d messages %q, want 3 courses and a note of 4 more", len(texts), texts)
+	}
+
+	fake.reset()
+	b.handleMessage(testMessage(userID, "/browse Development"))
+	sent := fake.sent("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("/browse sent %d messages, want 1", len(sent))
+	}
+	if listed := strings.Count(sent[0].Params.Get("text"), "(#"); listed != 3 {
+		t.Errorf("/browse listed %d courses, want 3:\n%s", listed, sent[0].Params.Get("text"))
+	}
+	if markup := sent[0].Params.Get("reply_markup"); !strings.Contains(markup, "browse:3:Development") {
+		t.Errorf("/browse buttons = %s, want Next to offset 3", markup)
+	}
+}
@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+const (
+	popularWindowDays = 7
+	popularLimit      = 10
+)
+
+func (b *Bot) handlePopularCommand(message *tgbotapi.Message) {
+	includeExpired := b.showsExpired(message.From.ID)
+	courses, err := b.db.GetPopularCourses(popularWindowDays, popularLimit, includeExpired)
+	if err == nil && len(courses) == 0 {
+		// Nobody has reacted yet, so fall back to the best of the week
+		var top []database.Course
+		top, err = b.db.GetTopCourses("", popularWindowDays, popularLimit, 0, includeExpired)
+		for _, course := range top {
+			courses = append(courses, database.PopularCourse{Course: course})
+		}
+	}
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load popular courses.")
+		log.Printf("Failed to get popular courses: %v", err)
+		return
+	}
+
+	if len(courses) == 0 {
+		b.sendMessage(message.Chat.ID, "🔥 No courses this week yet. Check back after the next scan!")
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, b.formatPopularCourses(courses))
+	msg.ParseMode = b.format.mode
+	msg.DisableWebPagePreview = true
+	b.send(msg)
+}
+
+// formatPopularCourses lists courses with their feedback. Before anyone has
+// saved or dismissed a course, the list is ordered by quality score and says so.
+func (b *Bot) formatPopularCourses(courses []database.PopularCourse) string {
+	engaged := false
+	for _, course := range courses {
+		if course.Saves > 0 || course.Dismissals > 0 {
+			engaged = true
+			break
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🔥 " + b.format.bold("Popular This Week") + "\n\n")
+	if !engaged {
+		sb.WriteString(b.format.escape("No feedback yet, so these are the highest-quality courses.\n\n"))
+	}
+
+	for i, course := range courses {
+		sb.WriteString(b.format.escape(fmt.Sprintf("%d. ", i+1)) + b.format.bold(course.Title) +
+			b.format.escape(fmt.Sprintf(" (#%d)\n", course.ID)))
+		if engaged {
+			sb.WriteString(b.format.escape(fmt.Sprintf("   ⭐ %d saves | ❌ %d not interested\n", course.Saves, course.Dismissals)))
+		} else {
+			sb.WriteString(b.format.escape(fmt.Sprintf("   Quality Score: %.0f/100\n", course.QualityScore)))
+		}
+		sb.WriteString(b.format.escape(fmt.Sprintf("   🔗 %s\n", b.courseLink(course.URL))))
+	}
+
+	return sb.String()
+}
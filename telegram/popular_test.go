@@ -0,0 +1,32 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestPopularFallsBackToQuality(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	addTestCourse(t, b.db, "go-basics", func(c *database.Course) { c.QualityScore = 90 })
+	rust := addTestCourse(t, b.db, "rust-basics", func(c *database.Course) { c.QualityScore = 60 })
+
+	b.handlePopularCommand(testMessage(userID, "/popular"))
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 || !strings.Contains(texts[0], "highest-quality") ||
+		strings.Index(texts[0], "go-basics") > strings.Index(texts[0], "rust-basics") {
+		t.Fatalf("cold start /popular = %q, want courses by quality", texts)
+	}
+
+	if err := b.db.AddFeedback(userID, rust.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	fake.reset()
+	b.handlePopularCommand(testMessage(userID, "/popular"))
+	texts = textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 || !strings.Contains(texts[0], "1 saves") || strings.Contains(texts[0], "go-basics") {
+		t.Errorf("/popular with feedback = %q, want only the saved course", texts)
+	}
+}
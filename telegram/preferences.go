@@ -0,0 +1,194 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/currency"
+	"udemy-course-notifier/filters"
+)
+
+// SetPriceFilterOptions configures currency conversion used by /maxprice filters
+func (b *Bot) SetPriceFilterOptions(rates map[string]float64, unparseablePasses bool) {
+	b.filterEngine.SetPriceOptions(currency.NewConverter(rates), unparseablePasses)
+}
+
+func (b *Bot) handleMaxPriceCommand(message *tgbotapi.Message, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 2 {
+		b.sendMessage(message.Chat.ID, "Usage: /maxprice <amount> [currency]\nExample: /maxprice 15 USD\nUse /maxprice off to remove the ceiling.")
+		return
+	}
+
+	if strings.EqualFold(fields[0], "off") {
+		if err := b.filterEngine.SetMaxPrice(message.From.ID, 0, ""); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to clear max price: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, "✅ Price ceiling removed.")
+		return
+	}
+
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(fields[0], ",", "."), 64)
+	if err != nil || amount <= 0 || amount > 100000 {
+		b.sendMessage(message.Chat.ID, "❌ Invalid amount. Please send a positive number, e.g. /maxprice 15 USD")
+		return
+	}
+
+	currencyCode := "USD"
+	if len(fields) == 2 {
+		currencyCode = strings.ToUpper(fields[1])
+	}
+	if !b.filterEngine.SupportsCurrency(currencyCode) {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unsupported currency: %s", currencyCode))
+		return
+	}
+
+	if err := b.filterEngine.SetMaxPrice(message.From.ID, amount, currencyCode); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save max price: %v", err)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Price ceiling set to %.2f %s.\nFree courses are always shown.", amount, currencyCode))
+}
+
+func (b *Bot) handleExportFilterCommand(message *tgbotapi.Message) {
+	userFilter, err := b.filterEngine.GetUserFilter(message.From.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "You haven't set any filters yet. Use /filter to create one.")
+		return
+	}
+
+	code, err := filters.EncodeFilter(userFilter)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to export your filter. Please try again.")
+		log.Printf("Failed to export filter: %v", err)
+		return
+	}
+
+	f := b.format
+	text := "📤 " + f.bold("Your filter code") + f.escape("\n\nShare it with friends; they can apply it with:\n\n") +
+		f.code("/importfilter "+code)
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ParseMode = f.mode
+	b.send(msg)
+}
+
+func (b *Bot) handleImportFilterCommand(message *tgbotapi.Message, args string) {
+	if strings.TrimSpace(args) == "" {
+		b.sendMessage(message.Chat.ID, "Usage: /importfilter <code>\nGet a code from /exportfilter.")
+		return
+	}
+
+	userFilter, err := filters.DecodeFilter(message.From.ID, args)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ That filter code is not valid.")
+		log.Printf("Rejected filter code from user %d: %v", message.From.ID, err)
+		return
+	}
+
+	if userFilter.MaxPrice > 0 && !b.filterEngine.SupportsCurrency(userFilter.Currency) {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unsupported currency in filter code: %s", userFilter.Currency))
+		return
+	}
+
+	if err := b.filterEngine.SaveUserFilter(userFilter); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save imported filter: %v", err)
+		return
+	}
+
+	if err := b.filterEngine.SetMaxPrice(message.From.ID, userFilter.MaxPrice, userFilter.Currency); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save imported max price: %v", err)
+		return
+	}
+
+	if err := b.filterEngine.SetRequireCertificate(message.From.ID, userFilter.RequireCertificate); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save imported certificate preference: %v", err)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Filter imported!\n%s", b.getFilterStatus(message.From.ID)))
+}
+
+// ratingOptions are the minimum ratings offered by /setrating
+var ratingOptions = []string{"0", "3.0", "3.5", "4.0", "4.3", "4.5", "4.7"}
+
+func (b *Bot) handleSetRatingCommand(message *tgbotapi.Message) {
+	current := 0.0
+	if userFilter, err := b.filterEngine.GetUserFilter(message.From.ID); err == nil {
+		current = userFilter.MinRating
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("⭐ Minimum rating: %s\n\nChoose a new minimum:", formatMinRating(current)))
+	msg.ReplyMarkup = ratingKeyboard(current)
+	b.api.Send(msg)
+}
+
+// ratingKeyboard lays out the rating options, marking the current one
+func ratingKeyboard(current float64) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, option := range ratingOptions {
+		value, _ := strconv.ParseFloat(option, 64)
+		label := "⭐ " + option
+		if option == "0" {
+			label = "Any"
+		}
+		if value == current {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "setrating:"+option))
+		if len(row) == 4 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleSetRatingCallback applies a rating picked from the /setrating keyboard
+func (b *Bot) handleSetRatingCallback(callback *tgbotapi.CallbackQuery, option string) string {
+	valid := false
+	for _, allowed := range ratingOptions {
+		if option == allowed {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return "❌ Unknown rating"
+	}
+	rating, _ := strconv.ParseFloat(option, 64)
+
+	if err := b.filterEngine.SetMinRating(callback.From.ID, rating); err != nil {
+		log.Printf("Failed to save min rating: %v", err)
+		return "❌ Failed to save your preferences"
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		callback.Message.Chat.ID,
+		callback.Message.MessageID,
+		fmt.Sprintf("✅ Minimum rating set to %s.", formatMinRating(rating)),
+		ratingKeyboard(rating),
+	)
+	b.api.Send(edit)
+	return "Saved"
+}
+
+func formatMinRating(rating float64) string {
+	if rating <= 0 {
+		return "any"
+	}
+	return fmt.Sprintf("%.1f ⭐", rating)
+}
@@ -0,0 +1,101 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestRatingKeyboard(t *testing.T) {
+	keyboard := ratingKeyboard(4.3)
+
+	var labels, data []string
+	for i, row := range keyboard.InlineKeyboard {
+		if len(row) > 4 {
+			t.Errorf("row %d has %d buttons, want at most 4", i, len(row))
+		}
+		for _, button := range row {
+			labels = append(labels, button.Text)
+			data = append(data, *button.CallbackData)
+		}
+	}
+
+	if got := strings.Join(data, " "); got != "setrating:0 setrating:3.0 setrating:3.5 setrating:4.0 setrating:4.3 setrating:4.5 setrating:4.7" {
+		t.Errorf("callback data = %s", got)
+	}
+	if labels[0] != "Any" || labels[4] != "✅ ⭐ 4.3" || labels[5] != "⭐ 4.5" {
+		t.Errorf("labels = %q, want Any first and 4.3 marked current", labels)
+	}
+}
+
+func TestSetRatingCallback(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	if err := b.filterEngine.SetTimezone(userID, "Europe/Madrid"); err != nil {
+		t.Fatal(err)
+	}
+
+	tap := func(data string) string {
+		fake.reset()
+		b.handleCallbackQuery(&tgbotapi.CallbackQuery{
+			ID:      "tap",
+			From:    &tgbotapi.User{ID: userID},
+			Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: userID}},
+			Data:    data,
+		})
+		answers := fake.sent("answerCallbackQuery")
+		if len(answers) != 1 {
+			t.Fatalf("%s: answered %d times, want once", data, len(answers))
+		}
+		return answers[0].Params.Get("text")
+	}
+
+	if answer := tap("setrating:4.3"); answer != "Saved" {
+		t.Errorf("answer = %q, want Saved", answer)
+	}
+	edits := fake.sent("editMessageText")
+	if len(edits) != 1 || !strings.Contains(edits[0].Params.Get("text"), "4.3") || edits[0].Params.Get("message_id") != "7" {
+		t.Errorf("edits = %v, want the picker confirming 4.3", edits)
+	}
+
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userFilter.MinRating != 4.3 || userFilter.Timezone != "Europe/Madrid" {
+		t.Errorf("filter = %+v, want rating 4.3 and the timezone untouched", userFilter)
+	}
+
+	// Only offered values are accepted
+	if answer := tap("setrating:4.9"); !strings.Contains(answer, "Unknown rating") {
+		t.Errorf("answer = %q, want Unknown rating", answer)
+	}
+	if userFilter, _ := b.filterEngine.GetUserFilter(userID); userFilter.MinRating != 4.3 {
+		t.Errorf("rating = %v after an unknown option, want 4.3", userFilter.MinRating)
+	}
+
+	if answer := tap("setrating:0"); answer != "Saved" {
+		t.Errorf("answer = %q, want Saved", answer)
+	}
+	if userFilter, _ := b.filterEngine.GetUserFilter(userID); userFilter.MinRating != 0 {
+		t.Errorf("rating = %v, want any", userFilter.MinRating)
+	}
+}
+
+func TestFilterConfirmationListsAnyAndAllKeywords(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	b.processFilterInput(userID, userID, "Development | 4.0 | python, web, +data | crypto")
+
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 {
+		t.Fatalf("sent %d replies, want 1", len(texts))
+	}
+	for _, want := range []string{"Keywords (any): [python web]", "Required (all): [data]"} {
+		if !strings.Contains(texts[0], b.format.escape(want)) {
+			t.Errorf("confirmation lacks %q:\n%s", want, texts[0])
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package telegram
+
+import "testing"
+
+func TestPreviewModeRoutesPosts(t *testing.T) {
+	b, fake := newTestBot(t)
+	fake.usernames = map[string]int64{"@courses_staging": -1009876543210}
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	if err := b.SetPreviewChannel("@missing_channel"); err == nil {
+		t.Error("SetPreviewChannel accepted a channel the bot can't see")
+	}
+	if err := b.SetPreviewChannel("@courses_staging"); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.reset()
+	if err := b.PostCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	sent := fake.sent("sendMessage")
+	if texts := textsTo(sent, -1009876543210); len(texts) != 1 {
+		t.Errorf("sent %d posts to the preview channel, want 1", len(texts))
+	}
+	if texts := textsTo(sent, testChannelID); len(texts) != 0 {
+		t.Errorf("sent %d posts to the production channel while previewing", len(texts))
+	}
+}
@@ -0,0 +1,177 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// Quiet hours modes: hold notifications until the window ends, or drop them
+const (
+	QuietHoursHold = "hold"
+	QuietHoursDrop = "drop"
+)
+
+// maxHeldAttempts is how many times delivering a held notification may fail
+// before it is dropped, e.g. because the user blocked the bot
+const maxHeldAttempts = 5
+
+const quietUsage = "Usage: /quiet <start> <end>, e.g. /quiet 23:00 07:00\nUse /quiet off to turn quiet hours off."
+
+// SetQuietHoursMode sets what happens to notifications during a user's quiet
+// hours: QuietHoursHold (default) or QuietHoursDrop
+func (b *Bot) SetQuietHoursMode(mode string) {
+	b.quietMode = mode
+}
+
+// quietHoursActive reports whether now falls in the window from start to end,
+// given as HH:MM. A window whose end is earlier than its start runs past
+// midnight. Empty, invalid or zero-length windows are never active.
+func quietHoursActive(start, end string, now time.Time) bool {
+	startAt, err := time.Parse("15:04", start)
+	if err != nil {
+		return false
+	}
+	endAt, err := time.Parse("15:04", end)
+	if err != nil {
+		return false
+	}
+
+	from := startAt.Hour()*60 + startAt.Minute()
+	to := endAt.Hour()*60 + endAt.Minute()
+	current := now.Hour()*60 + now.Minute()
+
+	if from == to {
+		return false
+	}
+	if from < to {
+		return current >= from && current < to
+	}
+	return current >= from || current < to
+}
+
+// inQuietHours reports whether the user's quiet hours are active right now
+func (b *Bot) inQuietHours(userID int64) bool {
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	if err != nil || userFilter.QuietStart == "" {
+		return false
+	}
+	return quietHoursActive(userFilter.QuietStart, userFilter.QuietEnd, time.Now().In(b.userLocation(userID)))
+}
+
+// DeliverHeldNotifications sends notifications held during quiet hours or
+// over a daily limit to users whose quiet hours have ended and who are under
+// their limit again. Courses that expired meanwhile are dropped, as are
+// notifications that failed to send maxHeldAttempts times.
+func (b *Bot) DeliverHeldNotifications() {
+	userIDs, err := b.db.GetHeldUserIDs()
+	if err != nil {
+		log.Printf("Failed to get held notifications: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, userID := range userIDs {
+		if b.inQuietHours(userID) || b.dailyLimitReached(userID) {
+			continue
+		}
+
+		held, err := b.db.GetHeldNotifications(userID)
+		if err != nil {
+			log.Printf("Failed to get held notifications for user %d: %v", userID, err)
+			continue
+		}
+
+		for _, h := range held {
+			// Sending can reach the limit partway through
+			if b.dailyLimitReached(userID) {
+				break
+			}
+			b.deliverHeld(h, now)
+		}
+	}
+}
+
+// deliverHeld sends one held notification and removes it, unless sending
+// failed and may be retried
+func (b *Bot) deliverHeld(h database.HeldNotification, now time.Time) {
+	course := h.Course
+	if !database.IsExpired(course.ExpiresAt, now, b.db.ExpiryGrace()) {
+		if err := b.sendCourseToUser(h.UserID, &course); err != nil {
+			if h.Attempts+1 < maxHeldAttempts {
+				log.Printf("Failed to deliver held notification to user %d: %v", h.UserID, err)
+				if err := b.db.RecordHeldFailure(h.UserID, course.ID); err != nil {
+					log.Printf("Failed to record held notification failure: %v", err)
+				}
+				return
+			}
+			log.Printf("Dropping held notification for user %d after %d failed attempts: %v", h.UserID, maxHeldAttempts, err)
+		}
+	}
+
+	if err := b.db.DeleteHeldNotification(h.UserID, course.ID); err != nil {
+		log.Printf("Failed to delete held notification: %v", err)
+	}
+}
+
+func (b *Bot) handleQuietCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+	fields := strings.Fields(args)
+
+	if len(fields) == 0 {
+		userFilter, err := b.filterEngine.GetUserFilter(userID)
+		if err != nil || userFilter.QuietStart == "" {
+			b.sendMessage(message.Chat.ID, "🔔 Quiet hours are off.\n\n"+quietUsage)
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("🔕 Quiet hours: %s to %s (%s)\n\n%s",
+			userFilter.QuietStart, userFilter.QuietEnd, b.userLocation(userID), quietUsage))
+		return
+	}
+
+	if len(fields) == 1 && strings.EqualFold(fields[0], "off") {
+		if err := b.filterEngine.SetQuietHours(userID, "", ""); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to clear quiet hours: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, "🔔 Quiet hours turned off.")
+		return
+	}
+
+	if len(fields) != 2 {
+		b.sendMessage(message.Chat.ID, quietUsage)
+		return
+	}
+
+	var times [2]string
+	for i, field := range fields {
+		parsed, err := time.Parse("15:04", field)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid time: %s\n%s", field, quietUsage))
+			return
+		}
+		times[i] = parsed.Format("15:04")
+	}
+	if times[0] == times[1] {
+		b.sendMessage(message.Chat.ID, "❌ Start and end times must differ.")
+		return
+	}
+
+	if err := b.filterEngine.SetQuietHours(userID, times[0], times[1]); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save quiet hours: %v", err)
+		return
+	}
+
+	outcome := "Courses found meanwhile will be sent when they end."
+	if b.quietMode == QuietHoursDrop {
+		outcome = "Courses found meanwhile won't be sent to you."
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🔕 Quiet hours set: %s to %s (%s). %s",
+		times[0], times[1], b.userLocation(userID), outcome))
+}
@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursActive(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, err := time.Parse("15:04", clock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return time.Date(2024, 7, 1, parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		start, end string
+		now        string
+		want       bool
+	}{
+		// Same-day window
+		{"09:00", "17:00", "08:59", false},
+		{"09:00", "17:00", "09:00", true},
+		{"09:00", "17:00", "16:59", true},
+		{"09:00", "17:00", "17:00", false},
+		// Window past midnight
+		{"23:00", "07:00", "22:59", false},
+		{"23:00", "07:00", "23:00", true},
+		{"23:00", "07:00", "00:00", true},
+		{"23:00", "07:00", "06:59", true},
+		{"23:00", "07:00", "07:00", false},
+		{"23:00", "07:00", "12:00", false},
+		// Never active
+		{"07:00", "07:00", "07:00", false},
+		{"", "", "12:00", false},
+		{"25:00", "07:00", "01:00", false},
+	}
+	for _, tt := range tests {
+		if got := quietHoursActive(tt.start, tt.end, at(tt.now)); got != tt.want {
+			t.Errorf("quietHoursActive(%q, %q) at %s = %v, want %v", tt.start, tt.end, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestNotifySubscribersDuringQuietHours(t *testing.T) {
+	for _, mode := range []string{QuietHoursHold, QuietHoursDrop} {
+		t.Run(mode, func(t *testing.T) {
+			b, fake := newTestBot(t)
+			b.SetQuietHoursMode(mode)
+			const userID = 42
+			if _, err := b.db.AddSubscriber(userID); err != nil {
+				t.Fatal(err)
+			}
+
+			// A window around the current time in the user's zone
+			now := time.Now().In(b.userLocation(userID))
+			start, end := now.Add(-time.Hour).Format("15:04"), now.Add(time.Hour).Format("15:04")
+			if err := b.filterEngine.SetQuietHours(userID, start, end); err != nil {
+				t.Fatal(err)
+			}
+
+			course := addTestCourse(t, b.db, "go-basics", nil)
+			b.NotifySubscribers(&course)
+			if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 0 {
+				t.Fatalf("user was messaged during quiet hours: %q", texts)
+			}
+
+			held, err := b.db.GetHeldNotifications(userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantHeld := 0
+			if mode == QuietHoursHold {
+				wantHeld = 1
+			}
+			if len(held) != wantHeld {
+				t.Fatalf("held %d notifications, want %d", len(held), wantHeld)
+			}
+
+			if err := b.filterEngine.SetQuietHours(userID, "", ""); err != nil {
+				t.Fatal(err)
+			}
+			b.DeliverHeldNotifications()
+			if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != wantHeld {
+				t.Errorf("delivered %d messages after quiet hours, want %d", len(texts), wantHeld)
+			}
+			if held, _ := b.db.GetHeldNotifications(userID); len(held) != 0 {
+				t.Errorf("%d notifications still held after delivery", len(held))
+			}
+		})
+	}
+}
+
+func TestHeldNotificationsSkipStoppedChats(t *testing.T) {
+	const userID, groupID, lapsedID = 42, -1005550001111, 43
+	b, fake := newTestBot(t)
+	course := addTestCourse(t, b.db, "go-basics", nil)
+	for _, chatID := range []int64{userID, groupID, lapsedID} {
+		if _, err := b.db.AddSubscriber(chatID); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.db.HoldNotification(chatID, course.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The user runs /stop and the group is dropped, as when the bot leaves it
+	b.handleMessage(testMessage(userID, "/stop"))
+	if _, err := b.db.RemoveSubscriber(groupID); err != nil {
+		t.Fatal(err)
+	}
+	for _, chatID := range []int64{userID, groupID} {
+		if held, _ := b.db.GetHeldNotifications(chatID); len(held) != 0 {
+			t.Errorf("chat %d keeps %d held notifications after stopping", chatID, len(held))
+		}
+	}
+
+	// Rows held for someone no longer subscribed are not delivered either
+	if _, err := b.db.Unsubscribe(lapsedID); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.db.HoldNotification(lapsedID, course.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.reset()
+	b.DeliverHeldNotifications()
+	if sent := fake.sent("sendMessage"); len(sent) != 0 {
+		t.Errorf("delivered %d held notifications to stopped chats", len(sent))
+	}
+}
@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+)
+
+// maxTrackedUsers bounds the limiter's memory; past it, idle users are forgotten
+const maxTrackedUsers = 10000
+
+// commandLimiter is a per-user token bucket: each user may send burst
+// commands at once, refilled at ratePerMinute
+type commandLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // Tokens added per second
+	burst   float64
+	buckets map[int64]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	warned bool // The user was told to wait since their last allowed command
+}
+
+// newCommandLimiter returns a limiter, or nil (no limit) when ratePerMinute
+// is not positive
+func newCommandLimiter(ratePerMinute, burst int) *commandLimiter {
+	if ratePerMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &commandLimiter{
+		rate:    float64(ratePerMinute) / 60,
+		burst:   float64(burst),
+		buckets: make(map[int64]*tokenBucket),
+	}
+}
+
+// allow takes a token from the user's bucket, reporting false when it is
+// empty. warn is true for the first refused command after an allowed one, so
+// a user hammering the bot is told to wait once rather than every time.
+func (l *commandLimiter) allow(userID int64, now time.Time) (allowed, warn bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, exists := l.buckets[userID]
+	if !exists {
+		if len(l.buckets) >= maxTrackedUsers {
+			l.forgetIdle(now)
+		}
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[userID] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * l.rate
+	if bucket.tokens > l.burst {
+		bucket.tokens = l.burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		warn = !bucket.warned
+		bucket.warned = true
+		return false, warn
+	}
+	bucket.tokens--
+	bucket.warned = false
+	return true, false
+}
+
+// forgetIdle drops buckets that have refilled completely, since a new bucket
+// for those users would be identical
+func (l *commandLimiter) forgetIdle(now time.Time) {
+	for userID, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, userID)
+		}
+	}
+}
+
+// SetCommandRateLimit limits each user to ratePerMinute commands, allowing
+// bursts of up to burst. Admins are exempt. A rate of 0 disables the limit.
+func (b *Bot) SetCommandRateLimit(ratePerMinute, burst int) {
+	b.commandLimiter = newCommandLimiter(ratePerMinute, burst)
+}
+
+// commandAllowed reports whether the user may run a command now, and if not,
+// whether to tell them so
+func (b *Bot) commandAllowed(userID int64) (allowed, warn bool) {
+	if b.commandLimiter == nil || b.isAdmin(userID) {
+		return true, false
+	}
+	return b.commandLimiter.allow(userID, time.Now())
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandLimiter(t *testing.T) {
+	limiter := newCommandLimiter(60, 2) // One token a second
+	now := time.Unix(1700000000, 0)
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := limiter.allow(1, now); !allowed {
+			t.Fatalf("command %d within the burst was refused", i+1)
+		}
+	}
+
+	allowed, warn := limiter.allow(1, now)
+	if allowed || !warn {
+		t.Errorf("first command past the burst: allowed=%v warn=%v, want refused with a warning", allowed, warn)
+	}
+	allowed, warn = limiter.allow(1, now.Add(100*time.Millisecond))
+	if allowed || warn {
+		t.Errorf("second refused command: allowed=%v warn=%v, want refused silently", allowed, warn)
+	}
+
+	if allowed, _ := limiter.allow(2, now); !allowed {
+		t.Error("another user's command was refused")
+	}
+
+	now = now.Add(time.Second)
+	if allowed, _ := limiter.allow(1, now); !allowed {
+		t.Fatal("command after a refill was refused")
+	}
+	if allowed, warn := limiter.allow(1, now); allowed || !warn {
+		t.Errorf("refusal after an allowed command: allowed=%v warn=%v, want a new warning", allowed, warn)
+	}
+}
+
+func TestNewCommandLimiterDisabled(t *testing.T) {
+	if newCommandLimiter(0, 5) != nil {
+		t.Error("a rate of 0 should disable the limiter")
+	}
+}
+
+func TestCommandRateLimitExemptsAdmins(t *testing.T) {
+	b, fake := newTestBot(t)
+	const adminID, userID = 1, 42
+	b.SetAdminIDs([]int64{adminID})
+	b.SetCommandRateLimit(1, 1)
+
+	throttled := func(from int64, commands int) int {
+		fake.reset()
+		for i := 0; i < commands; i++ {
+			b.handleMessage(testMessage(from, "/help"))
+		}
+		waits := 0
+		for _, text := range textsTo(fake.sent("sendMessage"), from) {
+			if strings.Contains(text, "Please wait") {
+				waits++
+			}
+		}
+		return waits
+	}
+
+	if waits := throttled(userID, 3); waits != 1 {
+		t.Errorf("user past the burst was told to wait %d times, want 1", waits)
+	}
+	if waits := throttled(adminID, 5); waits != 0 {
+		t.Errorf("admin was told to wait %d times, want admins exempt", waits)
+	}
+	if replies := len(textsTo(fake.sent("sendMessage"), adminID)); replies != 5 {
+		t.Errorf("admin got %d replies to 5 commands, want 5", replies)
+	}
+}
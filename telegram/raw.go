@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// Field length caps for /raw, keeping the message under Telegram's limit
+const (
+	rawFieldLimit       = 300
+	rawDescriptionLimit = 1500
+)
+
+// handleRawCommand shows every stored field of a course, for diagnosing
+// what the scraper extracted
+func (b *Bot) handleRawCommand(message *tgbotapi.Message, args string) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	courseID, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil || courseID <= 0 {
+		b.sendMessage(message.Chat.ID, "Usage: /raw <course ID>")
+		return
+	}
+
+	course, err := b.db.GetCourse(courseID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Course #%d not found.", courseID))
+		log.Printf("Failed to get course %d: %v", courseID, err)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, formatRawCourse(course))
+	msg.DisableWebPagePreview = true
+	b.api.Send(msg)
+}
+
+// formatRawCourse lists a course's stored fields as plain text, quoting
+// strings so empty and whitespace-only values are visible
+func formatRawCourse(course *database.Course) string {
+	field := func(value string, limit int) string {
+		return strconv.Quote(ellipsize(value, limit))
+	}
+	timestamp := func(t time.Time) string {
+		if t.IsZero() {
+			return "(none)"
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	altScore := "(none)"
+	if course.QualityScoreAlt != nil {
+		altScore = fmt.Sprintf("%.2f", *course.QualityScoreAlt)
+	}
+
+	certificate := "(unknown)"
+	if course.Certificate != nil {
+		certificate = fmt.Sprintf("%t", *course.Certificate)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔧 Course #%d\n\n", course.ID))
+	sb.WriteString("url: " + field(course.URL, rawFieldLimit) + "\n")
+	sb.WriteString("title: " + field(course.Title, rawFieldLimit) + "\n")
+	sb.WriteString("category: " + field(course.Category, rawFieldLimit) + "\n")
+	sb.WriteString(fmt.Sprintf("rating: %.2f\n", course.Rating))
+	sb.WriteString("price: " + field(course.Price, rawFieldLimit) + "\n")
+	sb.WriteString("original_price: " + field(course.OriginalPrice, rawFieldLimit) + "\n")
+	sb.WriteString("discount: " + field(course.Discount, rawFieldLimit) + "\n")
+	sb.WriteString(fmt.Sprintf("student_count: %d\n", course.StudentCount))
+	sb.WriteString(fmt.Sprintf("quality_score: %.2f\n", course.QualityScore))
+	sb.WriteString("quality_score_alt: " + altScore + "\n")
+	sb.WriteString("expires_at: " + timestamp(course.ExpiresAt) + "\n")
+	sb.WriteString("posted_at: " + timestamp(course.PostedAt) + "\n")
+	sb.WriteString("language: " + field(course.Language, rawFieldLimit) + "\n")
+	sb.WriteString("caption_languages: " + field(strings.Join(course.CaptionLanguages, ","), rawFieldLimit) + "\n")
+	sb.WriteString("certificate: " + certificate + "\n")
+	sb.WriteString("source_url: " + field(course.SourceURL, rawFieldLimit) + "\n")
+	sb.WriteString("description: " + field(course.Description, rawDescriptionLimit))
+	return sb.String()
+}
@@ -0,0 +1,114 @@
+package telegram
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"udemy-course-notifier/database"
+)
+
+func TestFormatRawCourseListsEveryField(t *testing.T) {
+	alt := 61.5
+	certificate := true
+	course := &database.Course{
+		ID:               7,
+		URL:              "https://www.udemy.com/course/go-basics/",
+		Title:            "Go Basics",
+		Description:      strings.Repeat("d", rawDescriptionLimit+100),
+		Category:         "Development",
+		Rating:           4.6,
+		Price:            "Free",
+		OriginalPrice:    "$84.99",
+		Discount:         "100% off",
+		ExpiresAt:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		QualityScore:     72,
+		QualityScoreAlt:  &alt,
+		StudentCount:     1200,
+		Language:         "en",
+		CaptionLanguages: []string{"en", "es"},
+		Certificate:      &certificate,
+		SourceURL:        "https://courson.xyz/",
+	}
+	text := formatRawCourse(course)
+
+	// Every stored field is listed under its JSON name, so a field added to
+	// Course without a /raw line fails here
+	courseType := reflect.TypeOf(database.Course{})
+	for i := 0; i < courseType.NumField(); i++ {
+		name := strings.Split(courseType.Field(i).Tag.Get("json"), ",")[0]
+		if name == "id" {
+			continue
+		}
+		if !strings.Contains(text, "\n"+name+": ") {
+			t.Errorf("/raw output lacks the %s field:\n%s", name, text)
+		}
+	}
+
+	for _, want := range []string{
+		"🔧 Course #7",
+		`caption_languages: "en,es"`,
+		"quality_score_alt: 61.50",
+		"certificate: true",
+		`source_url: "https://courson.xyz/"`,
+		"expires_at: 2026-01-02T03:04:05Z",
+		"posted_at: (none)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("/raw output lacks %q:\n%s", want, text)
+		}
+	}
+
+	description := text[strings.Index(text, "description: "):]
+	if n := utf8.RuneCountInString(description); n > len("description: ")+rawDescriptionLimit+2 {
+		t.Errorf("description is %d runes long, want it capped near %d", n, rawDescriptionLimit)
+	}
+}
+
+func TestFormatRawCourseUnknownValues(t *testing.T) {
+	text := formatRawCourse(&database.Course{ID: 3})
+	for _, want := range []string{
+		`language: ""`,
+		"quality_score_alt: (none)",
+		"certificate: (unknown)",
+		"expires_at: (none)",
+		`source_url: ""`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("/raw output lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestRawCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const adminID, userID = 1, 42
+	b.SetAdminIDs([]int64{adminID})
+	course := addTestCourse(t, b.db, "go-basics", func(c *database.Course) {
+		c.SourceURL = "https://courson.xyz/"
+	})
+
+	tests := []struct {
+		userID int64
+		args   string
+		want   string
+	}{
+		{userID, fmt.Sprint(course.ID), "admin"},
+		{adminID, "", "Usage: /raw"},
+		{adminID, "abc", "Usage: /raw"},
+		{adminID, "999", "Course #999 not found"},
+		{adminID, fmt.Sprint(course.ID), `title: "Course go-basics"`},
+		{adminID, fmt.Sprint(course.ID), `source_url: "https://courson.xyz/"`},
+	}
+	for _, tt := range tests {
+		fake.reset()
+		b.handleRawCommand(testMessage(tt.userID, "/raw "+tt.args), tt.args)
+		texts := textsTo(fake.sent("sendMessage"), tt.userID)
+		if len(texts) != 1 || !strings.Contains(texts[0], tt.want) {
+			t.Errorf("/raw %q from %d replied %q, want a reply containing %q", tt.args, tt.userID, texts, tt.want)
+		}
+	}
+}
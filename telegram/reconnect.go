@@ -0,0 +1,30 @@
+package telegram
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = 5 * time.Minute
+)
+
+// reconnectDelay returns how long to wait before reconnection attempt n
+// (starting at 1). The delay doubles each attempt up to reconnectMaxDelay,
+// and is jittered to between half and all of that so restarted bots do not
+// reconnect in lockstep.
+func reconnectDelay(attempt int) time.Duration {
+	delay := reconnectMaxDelay
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt <= 20 { // Beyond this the shift would overflow the cap anyway
+		if d := reconnectBaseDelay << (attempt - 1); d < reconnectMaxDelay {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,39 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		ceiling time.Duration // Delay before jitter
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{9, 256 * time.Second},
+		{10, reconnectMaxDelay},
+		{64, reconnectMaxDelay},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			delay := reconnectDelay(tt.attempt)
+			if delay < tt.ceiling/2 || delay > tt.ceiling {
+				t.Fatalf("reconnectDelay(%d) = %s, want between %s and %s", tt.attempt, delay, tt.ceiling/2, tt.ceiling)
+			}
+		}
+	}
+}
+
+func TestReconnectDelayJitters(t *testing.T) {
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		seen[reconnectDelay(8)] = true
+	}
+	if len(seen) < 2 {
+		t.Error("reconnect delays are not jittered")
+	}
+}
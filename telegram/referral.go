@@ -0,0 +1,48 @@
+package telegram
+
+import (
+	"net/url"
+	"strings"
+)
+
+// SetReferralCode sets the Udemy affiliate referral code added to course
+// links the bot sends. An empty code leaves links unchanged.
+func (b *Bot) SetReferralCode(code string) {
+	b.referralCode = code
+}
+
+// courseLink returns the link to send for a stored course URL, carrying the
+// configured referral code
+func (b *Bot) courseLink(courseURL string) string {
+	return withReferralCode(courseURL, b.referralCode)
+}
+
+// withReferralCode adds referralCode to a udemy.com course URL that has none.
+// Coupon codes and other parameters are kept. Tracking links and other hosts
+// are returned unchanged, since their referral belongs to someone else.
+func withReferralCode(courseURL, code string) string {
+	if code == "" {
+		return courseURL
+	}
+
+	parsedURL, err := url.Parse(courseURL)
+	if err != nil {
+		return courseURL
+	}
+
+	host := strings.ToLower(parsedURL.Hostname())
+	if host != "udemy.com" && !strings.HasSuffix(host, ".udemy.com") {
+		return courseURL
+	}
+	if !strings.HasPrefix(parsedURL.Path, "/course/") {
+		return courseURL
+	}
+
+	query := parsedURL.Query()
+	if query.Get("referralCode") != "" {
+		return courseURL
+	}
+	query.Set("referralCode", code)
+	parsedURL.RawQuery = query.Encode()
+	return parsedURL.String()
+}
@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithReferralCode(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		code string
+		want string
+	}{
+		{"plain course", "https://www.udemy.com/course/go-basics/", "ABC123",
+			"https://www.udemy.com/course/go-basics/?referralCode=ABC123"},
+		{"coupon kept", "https://www.udemy.com/course/go-basics/?couponCode=FREE24", "ABC123",
+			"https://www.udemy.com/course/go-basics/?couponCode=FREE24&referralCode=ABC123"},
+		{"existing referral kept", "https://www.udemy.com/course/go-basics/?referralCode=THEIRS", "ABC123",
+			"https://www.udemy.com/course/go-basics/?referralCode=THEIRS"},
+		{"bare udemy.com", "https://udemy.com/course/go-basics/", "ABC123",
+			"https://udemy.com/course/go-basics/?referralCode=ABC123"},
+		{"tracking link", "https://click.linksynergy.com/deeplink?murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fgo-basics%2F", "ABC123",
+			"https://click.linksynergy.com/deeplink?murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fgo-basics%2F"},
+		{"lookalike host", "https://udemy.com.example/course/go-basics/", "ABC123",
+			"https://udemy.com.example/course/go-basics/"},
+		{"not a course page", "https://www.udemy.com/topic/go/", "ABC123",
+			"https://www.udemy.com/topic/go/"},
+		{"no code configured", "https://www.udemy.com/course/go-basics/", "",
+			"https://www.udemy.com/course/go-basics/"},
+	}
+	for _, tt := range tests {
+		if got := withReferralCode(tt.url, tt.code); got != tt.want {
+			t.Errorf("%s: withReferralCode() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPostCourseAddsReferralCode(t *testing.T) {
+	b, fake := newTestBot(t)
+	course := addTestCourse(t, b.db, "go-basics", nil)
+
+	if err := b.PostCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+	b.SetReferralCode("ABC123")
+	if err := b.PostCourse(&course); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := fake.sent("sendMessage")
+	if len(posts) != 2 {
+		t.Fatalf("sent %d posts, want 2", len(posts))
+	}
+	if strings.Contains(posts[0].Params.Get("text")+posts[0].Params.Get("reply_markup"), "referralCode") {
+		t.Error("post carries a referral code before one is configured")
+	}
+	if !strings.Contains(posts[1].Params.Get("text")+posts[1].Params.Get("reply_markup"), "referralCode=ABC123") {
+		t.Errorf("post lacks the referral code:\n%s\n%s", posts[1].Params.Get("text"), posts[1].Params.Get("reply_markup"))
+	}
+}
@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// SetRemindAllLeadTime sets how long before expiry a wishlist course is
+// included in the /remindall digest
+func (b *Bot) SetRemindAllLeadTime(lead time.Duration) {
+	b.remindAllLead = lead
+}
+
+// expiringSoon returns the courses that expire within lead of now and are
+// not yet past their expiry grace. Courses without an expiry date are skipped.
+func expiringSoon(courses []database.Course, now time.Time, lead, grace time.Duration) []database.Course {
+	var due []database.Course
+	for _, course := range courses {
+		if course.ExpiresAt.IsZero() || database.IsExpired(course.ExpiresAt, now, grace) {
+			continue
+		}
+		if course.ExpiresAt.Sub(now) <= lead {
+			due = append(due, course)
+		}
+	}
+	return due
+}
+
+// SendWishlistExpiryDigests sends each /remindall user a daily message listing
+// their wishlist courses that are about to expire. A course that enters the
+// lead window after the day's digest and would expire before the next one is
+// sent on its own right away. Each course is listed once per expiry date.
+func (b *Bot) SendWishlistExpiryDigests() {
+	users, err := b.db.GetRemindAllUsers()
+	if err != nil {
+		log.Printf("Failed to get remind-all users: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, user := range users {
+		loc := b.userLocation(user.UserID)
+		today := now.In(loc).Format("2006-01-02")
+
+		courses, err := b.db.GetUnremindedWishlist(user.UserID)
+		if err != nil {
+			log.Printf("Failed to get wishlist for user %d: %v", user.UserID, err)
+			continue
+		}
+		due := expiringSoon(courses, now, b.remindAllLead, b.db.ExpiryGrace())
+		if user.LastSentOn == today {
+			due = expiringBefore(due, nextLocalDay(now, loc))
+		}
+		if len(due) == 0 {
+			continue
+		}
+		sortDigest(due, user.DigestSort)
+
+		msg := tgbotapi.NewMessage(user.UserID, b.formatExpiryDigest(due, loc))
+		msg.ParseMode = b.format.mode
+		msg.DisableWebPagePreview = true
+		if _, err := b.send(msg); err != nil {
+			log.Printf("Failed to send expiry digest to user %d: %v", user.UserID, err)
+			continue
+		}
+
+		for _, course := range due {
+			if err := b.db.MarkWishlistReminded(user.UserID, course.ID); err != nil {
+				log.Printf("Failed to mark wishlist course reminded: %v", err)
+			}
+		}
+		if err := b.db.SetRemindAllSentOn(user.UserID, today); err != nil {
+			log.Printf("Failed to record expiry digest: %v", err)
+		}
+	}
+}
+
+// expiringBefore returns the courses that expire before cutoff
+func expiringBefore(courses []database.Course, cutoff time.Time) []database.Course {
+	var due []database.Course
+	for _, course := range courses {
+		if course.ExpiresAt.Before(cutoff) {
+			due = append(due, course)
+		}
+	}
+	return due
+}
+
+// nextLocalDay returns the start of the day after now in loc, when the next
+// daily digest goes out
+func nextLocalDay(now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+}
+
+func (b *Bot) formatExpiryDigest(courses []database.Course, loc *time.Location) string {
+	var sb strings.Builder
+	sb.WriteString("⏰ " + b.format.bold("Wishlist courses expiring soon") + "\n")
+	for _, course := range courses {
+		sb.WriteString("\n🎓 " + b.format.bold(course.Title) + "\n")
+		sb.WriteString(b.format.escape(fmt.Sprintf("⌛ Expires %s\n🔗 %s\n",
+			course.ExpiresAt.In(loc).Format("Jan 2, 15:04 MST"), b.courseLink(course.URL))))
+	}
+	return sb.String()
+}
+
+func (b *Bot) handleRemindAllCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on":
+		if err := b.filterEngine.SetRemindAll(userID, true); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to enable remind-all: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ I'll send you a daily message with wishlist courses expiring within %s.",
+			formatDuration(b.remindAllLead)))
+	case "off":
+		if err := b.filterEngine.SetRemindAll(userID, false); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to disable remind-all: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, "🔕 Wishlist expiry reminders turned off.")
+	default:
+		status := "off"
+		if userFilter, err := b.filterEngine.GetUserFilter(userID); err == nil && userFilter.RemindAll {
+			status = "on"
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("⏰ Wishlist expiry reminders are %s.\n\nUsage: /remindall on|off", status))
+	}
+}
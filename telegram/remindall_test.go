@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+func TestExpiringSoon(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	expiringIn := func(d time.Duration) database.Course {
+		return database.Course{Title: d.String(), ExpiresAt: now.Add(d)}
+	}
+	courses := []database.Course{
+		expiringIn(time.Hour),
+		expiringIn(24 * time.Hour),
+		expiringIn(25 * time.Hour),
+		expiringIn(-time.Hour),
+		{Title: "no expiry"},
+	}
+
+	titles := func(courses []database.Course) string {
+		var names []string
+		for _, course := range courses {
+			names = append(names, course.Title)
+		}
+		return strings.Join(names, ", ")
+	}
+
+	if got := titles(expiringSoon(courses, now, 24*time.Hour, 0)); got != "1h0m0s, 24h0m0s" {
+		t.Errorf("expiringSoon without grace = %s, want the courses within the next day", got)
+	}
+	if got := titles(expiringSoon(courses, now, 24*time.Hour, 2*time.Hour)); got != "1h0m0s, 24h0m0s, -1h0m0s" {
+		t.Errorf("expiringSoon with grace = %s, want the lapsed course too", got)
+	}
+}
+
+func TestNextLocalDay(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+	// 23:30 UTC is already the next day in Madrid
+	now := time.Date(2024, 7, 1, 23, 30, 0, 0, time.UTC)
+	if got, want := nextLocalDay(now, madrid), time.Date(2024, 7, 3, 0, 0, 0, 0, madrid); !got.Equal(want) {
+		t.Errorf("nextLocalDay in Madrid = %v, want %v", got, want)
+	}
+	if got, want := nextLocalDay(now, time.UTC), time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("nextLocalDay in UTC = %v, want %v", got, want)
+	}
+}
+
+func TestSendWishlistExpiryDigests(t *testing.T) {
+	b, fake := newTestBot(t)
+	b.SetRemindAllLeadTime(48 * time.Hour)
+	const userID = 42
+	if err := b.filterEngine.SetRemindAll(userID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	midnight := nextLocalDay(now, b.userLocation(userID))
+	wishlist := func(slug string, expiresAt time.Time) database.Course {
+		course := addTestCourse(t, b.db, slug, func(c *database.Course) { c.ExpiresAt = expiresAt })
+		if err := b.db.AddToWishlist(userID, course.ID); err != nil {
+			t.Fatal(err)
+		}
+		return course
+	}
+	digest := func() string {
+		fake.reset()
+		b.SendWishlistExpiryDigests()
+		texts := textsTo(fake.sent("sendMessage"), userID)
+		if len(texts) > 1 {
+			t.Fatalf("sent %d digests in one run", len(texts))
+		}
+		return strings.Join(texts, "")
+	}
+
+	soon := wishlist("soon", now.Add(time.Hour))
+	wishlist("distant", now.Add(72*time.Hour))
+	wishlist("lapsed", now.Add(-time.Hour))
+
+	text := digest()
+	if !strings.Contains(text, "Course soon") || strings.Contains(text, "Course distant") || strings.Contains(text, "Course lapsed") {
+		t.Fatalf("first digest lists the wrong courses:\n%s", text)
+	}
+	if text := digest(); text != "" {
+		t.Errorf("second run the same day sent another digest:\n%s", text)
+	}
+
+	// Today's digest is out: a course expiring before the next one is sent
+	// right away, one that can wait is held for tomorrow
+	wishlist("tonight", now.Add(midnight.Sub(now)/2))
+	wishlist("tomorrow", midnight.Add(time.Hour))
+	text = digest()
+	if !strings.Contains(text, "Course tonight") || strings.Contains(text, "Course tomorrow") || strings.Contains(text, "Course soon") {
+		t.Errorf("late digest lists the wrong courses:\n%s", text)
+	}
+
+	// A new day's digest includes the held course, and a coupon renewed with
+	// a later expiry is reminded again
+	if err := b.db.SetRemindAllSentOn(userID, "2000-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	soon.ExpiresAt = now.Add(2 * time.Hour)
+	if err := b.db.RefreshCourse(&soon); err != nil {
+		t.Fatal(err)
+	}
+	text = digest()
+	if !strings.Contains(text, "Course tomorrow") || !strings.Contains(text, "Course soon") || strings.Contains(text, "Course tonight") {
+		t.Errorf("next day's digest lists the wrong courses:\n%s", text)
+	}
+
+	if err := b.filterEngine.SetRemindAll(userID, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.db.SetRemindAllSentOn(userID, "2000-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	wishlist("later", now.Add(3*time.Hour))
+	if text := digest(); text != "" {
+		t.Errorf("sent a digest after /remindall off:\n%s", text)
+	}
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// reminderLeadTime is how long before a course expires a snoozed reminder is sent
+const reminderLeadTime = 3 * time.Hour
+
+// maxReminderAttempts is how many times sending a reminder may fail before
+// it is dropped, e.g. because the user blocked the bot
+const maxReminderAttempts = 5
+
+// snoozeCourse schedules a reminder for the user and returns the callback answer text
+func (b *Bot) snoozeCourse(userID int64, courseID int) string {
+	course, err := b.db.GetCourse(courseID)
+	if err != nil {
+		log.Printf("Failed to load course for reminder: %v", err)
+		return "❌ Course not found"
+	}
+
+	now := time.Now()
+	if database.IsExpired(course.ExpiresAt, now, b.db.ExpiryGrace()) {
+		return "⌛ This course has already expired"
+	}
+	if course.ExpiresAt.IsZero() {
+		return "🤷 This course's expiry date is unknown, so there's nothing to remind you of"
+	}
+
+	remindAt := course.ExpiresAt.Add(-reminderLeadTime)
+	if remindAt.Before(now) {
+		remindAt = now
+	}
+
+	if err := b.db.AddReminder(userID, courseID, remindAt); err != nil {
+		log.Printf("Failed to add reminder: %v", err)
+		return "❌ Failed to set reminder"
+	}
+
+	return fmt.Sprintf("⏰ I'll remind you %s", remindAt.In(b.userLocation(userID)).Format("Jan 2, 15:04 MST"))
+}
+
+// SendDueReminders delivers all reminders whose time has come as direct messages
+func (b *Bot) SendDueReminders() {
+	now := time.Now()
+
+	if err := b.db.DeleteExpiredReminders(now); err != nil {
+		log.Printf("Failed to delete expired reminders: %v", err)
+	}
+
+	reminders, err := b.db.GetDueReminders(now)
+	if err != nil {
+		log.Printf("Failed to get due reminders: %v", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		expiry := "expiring now"
+		if expiresIn := time.Until(reminder.Course.ExpiresAt).Round(time.Minute); expiresIn > 0 {
+			expiry = expiresIn.String()
+		}
+		text := "⏰ " + b.format.bold("Reminder") + "\n\n🎓 " + b.format.bold(reminder.Course.Title) +
+			b.format.escape(fmt.Sprintf("\n⌛ Expires in: %s\n🔗 %s", expiry, b.courseLink(reminder.Course.URL)))
+
+		msg := tgbotapi.NewMessage(reminder.UserID, text)
+		msg.ParseMode = b.format.mode
+		msg.DisableWebPagePreview = true
+		if _, err := b.send(msg); err != nil {
+			if reminder.Attempts+1 < maxReminderAttempts {
+				log.Printf("Failed to send reminder to user %d: %v", reminder.UserID, err)
+				if err := b.db.RecordReminderFailure(reminder.UserID, reminder.CourseID); err != nil {
+					log.Printf("Failed to record reminder failure: %v", err)
+				}
+				continue
+			}
+			log.Printf("Dropping reminder for user %d after %d failed attempts: %v", reminder.UserID, maxReminderAttempts, err)
+		}
+
+		if err := b.db.DeleteReminder(reminder.UserID, reminder.CourseID); err != nil {
+			log.Printf("Failed to delete reminder: %v", err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/scraper"
+)
+
+func TestRescoreAppliesSourceTrust(t *testing.T) {
+	const adminID = 1
+	b, fake := newTestBot(t)
+	b.SetAdminIDs([]int64{adminID})
+	scorer := scraper.NewQualityScorer(scraper.DefaultScoringWeights())
+	b.SetQualityScorer(scorer)
+	b.SetSourceTrust(map[string]float64{"https://curated.example/": 1.2, "https://spammy.example/": 0.5})
+
+	inSource := func(source string) func(*database.Course) {
+		return func(c *database.Course) {
+			c.Rating = 4.5
+			c.StudentCount = 800
+			c.SourceURL = source
+		}
+	}
+	curated := addTestCourse(t, b.db, "curated", inSource("https://curated.example/"))
+	spammy := addTestCourse(t, b.db, "spammy", inSource("https://spammy.example/"))
+	imported := addTestCourse(t, b.db, "imported", inSource(""))
+
+	b.handleMessage(testMessage(adminID, "/rescore"))
+	if texts := textsTo(fake.sent("sendMessage"), adminID); len(texts) != 1 || !strings.Contains(texts[0], "Rescore complete") {
+		t.Fatalf("/rescore replied %q", texts)
+	}
+
+	for _, tt := range []struct {
+		course database.Course
+		trust  float64
+	}{
+		{curated, 1.2},
+		{spammy, 0.5},
+		{imported, 1},
+	} {
+		want := scraper.TrustedScore(scorer.Score(4.5, 800, tt.course.Title, tt.course.Description), tt.trust)
+		got, err := b.db.GetCourse(tt.course.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.QualityScore != want {
+			t.Errorf("%s rescored to %v, want %v with trust %v", tt.course.URL, got.QualityScore, want, tt.trust)
+		}
+	}
+}
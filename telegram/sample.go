@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// sampleCourse is shown by /sample before any course has been stored
+func sampleCourse(now time.Time) database.Course {
+	return database.Course{
+		URL:          "https://www.udemy.com/course/sample-course/",
+		Title:        "Sample Course: Getting Started with Go",
+		Description:  "This is what a course notification looks like.",
+		Category:     "Development",
+		Rating:       4.6,
+		Price:        "Free",
+		Discount:     "100%",
+		ExpiresAt:    now.Add(48 * time.Hour),
+		PostedAt:     now,
+		QualityScore: 80,
+		StudentCount: 12000,
+	}
+}
+
+// handleSampleCommand sends the user a direct message formatted exactly like
+// a live notification, using the newest stored course. It confirms the bot
+// can message them, which fails until they have started a private chat.
+func (b *Bot) handleSampleCommand(message *tgbotapi.Message) {
+	userID := message.From.ID
+
+	course := sampleCourse(time.Now())
+	recent, err := b.db.GetRecentCourses(1)
+	if err != nil {
+		log.Printf("Failed to get recent course for sample: %v", err)
+	} else if len(recent) > 0 {
+		course = recent[0]
+	}
+
+	msg := tgbotapi.NewMessage(userID, b.formatCourseMessage(&course, b.userLocation(userID)))
+	msg.ParseMode = b.format.mode
+	msg.DisableWebPagePreview = true
+	// A made-up course has no stored row for the buttons to act on
+	if course.ID != 0 {
+		msg.ReplyMarkup = b.courseKeyboard(&course)
+	}
+
+	if _, err := b.send(msg); err != nil {
+		log.Printf("Failed to send sample to user %d: %v", userID, err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ I couldn't send you a direct message (%v).\n\n"+
+			"Open a private chat with @%s, press Start, and try /sample again.", err, b.api.Self.UserName))
+		return
+	}
+
+	if message.Chat.ID != userID {
+		b.sendMessage(message.Chat.ID, "✅ I sent you a sample notification in a direct message.")
+	}
+}
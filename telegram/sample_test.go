@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestSampleCommandWithoutCourses(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	b.handleMessage(testMessage(userID, "/sample"))
+
+	sent := fake.sent("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want only the sample", len(sent))
+	}
+	if text := sent[0].Params.Get("text"); !strings.Contains(text, "Sample Course") {
+		t.Errorf("sample lacks the made-up course:\n%s", text)
+	}
+	if markup := sent[0].Params.Get("reply_markup"); markup != "" {
+		t.Errorf("made-up sample has buttons %s, want none", markup)
+	}
+}
+
+func TestSampleCommandRendersLatestCourse(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	addTestCourse(t, b.db, "go-basics", nil)
+
+	b.handleMessage(testMessage(userID, "/sample"))
+
+	recent, err := b.db.GetRecentCourses(1)
+	if err != nil || len(recent) != 1 {
+		t.Fatalf("GetRecentCourses = %v, %v", recent, err)
+	}
+	want := b.formatCourseMessage(&recent[0], b.userLocation(userID))
+
+	sent := fake.sent("sendMessage")
+	if len(sent) != 1 || sent[0].Params.Get("text") != want {
+		t.Fatalf("sample = %q, want the live notification text %q", textsTo(sent, userID), want)
+	}
+	if markup := sent[0].Params.Get("reply_markup"); !strings.Contains(markup, fmt.Sprintf("wishlist:%d", recent[0].ID)) {
+		t.Errorf("sample buttons = %s, want the course's notification buttons", markup)
+	}
+}
+
+func TestSampleCommandFromGroup(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID, groupID = 42, -100555
+	groupMessage := func() *tgbotapi.Message {
+		message := testMessage(userID, "/sample")
+		message.Chat = &tgbotapi.Chat{ID: groupID, Type: "group"}
+		return message
+	}
+
+	b.handleMessage(groupMessage())
+	if replies := textsTo(fake.sent("sendMessage"), groupID); len(replies) != 1 || !strings.Contains(replies[0], "sent you a sample") {
+		t.Errorf("group replies = %q, want a confirmation", replies)
+	}
+
+	// Users who never started the bot can't be messaged first
+	fake.reset()
+	fake.failWith(func(call apiCall) (int, string) {
+		if call.Params.Get("chat_id") == fmt.Sprint(userID) {
+			return 403, "Forbidden: bot can't initiate conversation with a user"
+		}
+		return 0, ""
+	})
+	b.handleMessage(groupMessage())
+	replies := textsTo(fake.sent("sendMessage"), groupID)
+	if len(replies) != 1 || !strings.Contains(replies[0], "@test_bot") || !strings.Contains(replies[0], "Start") {
+		t.Errorf("group replies = %q, want instructions to start a chat with @test_bot", replies)
+	}
+}
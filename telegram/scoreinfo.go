@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/scraper"
+)
+
+// scoreInfo explains a course's quality score for a "Why this score?" tap.
+// The text is kept short enough for a callback alert (200 characters).
+func (b *Bot) scoreInfo(courseID int) string {
+	if b.scorer == nil {
+		return "Score details are not available."
+	}
+
+	course, err := b.db.GetCourse(courseID)
+	if err != nil {
+		log.Printf("Failed to load course for score info: %v", err)
+		return "❌ Course not found"
+	}
+
+	breakdown := b.scorer.ScoreBreakdown(course.Rating, course.StudentCount, course.Title, course.Description)
+	return formatScoreBreakdown(course, breakdown)
+}
+
+func formatScoreBreakdown(course *database.Course, breakdown scraper.ScoreBreakdown) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Quality score %.0f/100\n", course.QualityScore))
+	sb.WriteString(fmt.Sprintf("⭐ Rating: +%.0f\n", breakdown.Rating))
+	sb.WriteString(fmt.Sprintf("👥 Students: +%.0f\n", breakdown.Students))
+	sb.WriteString(fmt.Sprintf("🏷 Title: +%.0f / -%.0f\n", breakdown.TitleBonus, breakdown.TitlePenalty))
+	sb.WriteString(fmt.Sprintf("📝 Description: +%.0f\n", breakdown.Description))
+	sb.WriteString(fmt.Sprintf("📅 Recency: +%.0f", breakdown.Recency))
+
+	// Source weighting and weight changes since the course was scored are not
+	// reproduced here, so point out when the stored score differs
+	if math.Abs(breakdown.Total-course.QualityScore) >= 1 {
+		sb.WriteString(fmt.Sprintf("\n(Adjusted from %.0f)", breakdown.Total))
+	}
+	return sb.String()
+}
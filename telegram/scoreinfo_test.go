@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/scraper"
+)
+
+func TestFormatScoreBreakdown(t *testing.T) {
+	course := &database.Course{QualityScore: 70}
+	breakdown := scraper.ScoreBreakdown{Rating: 36, Students: 25, TitleBonus: 4, TitlePenalty: 3, Description: 5, Recency: 3, Total: 70}
+
+	text := formatScoreBreakdown(course, breakdown)
+	for _, want := range []string{"Quality score 70/100", "Rating: +36", "Students: +25", "Title: +4 / -3", "Description: +5", "Recency: +3"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("breakdown lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Adjusted") {
+		t.Errorf("breakdown matching the stored score mentions an adjustment:\n%s", text)
+	}
+	if n := utf8.RuneCountInString(text); n > 200 {
+		t.Errorf("breakdown is %d characters, too long for a callback alert", n)
+	}
+
+	// A score shifted by source trust no longer matches the components
+	course.QualityScore = 84
+	if text := formatScoreBreakdown(course, breakdown); !strings.Contains(text, "Adjusted from 70") {
+		t.Errorf("breakdown of an adjusted score:\n%s", text)
+	}
+}
+
+func TestScoreInfoCallbackShowsAlert(t *testing.T) {
+	b, fake := newTestBot(t)
+	b.SetQualityScorer(scraper.NewQualityScorer(scraper.DefaultScoringWeights()))
+	course := addTestCourse(t, b.db, "go-basics", func(c *database.Course) {
+		c.Rating = 4.5
+		c.StudentCount = 600
+		c.QualityScore = 61
+	})
+
+	b.handleCallbackQuery(&tgbotapi.CallbackQuery{
+		ID:      "score",
+		From:    &tgbotapi.User{ID: 42},
+		Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: testChannelID}},
+		Data:    fmt.Sprintf("score_info:%d", course.ID),
+	})
+
+	answers := fake.sent("answerCallbackQuery")
+	if len(answers) != 1 {
+		t.Fatalf("answered %d times, want once", len(answers))
+	}
+	if answers[0].Params.Get("show_alert") != "true" || !strings.Contains(answers[0].Params.Get("text"), "Quality score 61/100") {
+		t.Errorf("answer = %v, want the breakdown as an alert", answers[0].Params)
+	}
+}
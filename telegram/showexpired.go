@@ -0,0 +1,43 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// showsExpired reports whether the user wants expired courses in /browse
+// and /popular. Users without preferences get the default of hiding them.
+func (b *Bot) showsExpired(userID int64) bool {
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	return err == nil && userFilter.ShowExpired
+}
+
+func (b *Bot) handleShowExpiredCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on":
+		if err := b.filterEngine.SetShowExpired(userID, true); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to enable expired courses: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, "✅ /browse and /popular will include expired courses.")
+	case "off":
+		if err := b.filterEngine.SetShowExpired(userID, false); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to disable expired courses: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, "✅ /browse and /popular will hide expired courses.")
+	default:
+		status := "hidden"
+		if b.showsExpired(userID) {
+			status = "shown"
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("🗂 Expired courses are %s in /browse and /popular.\n\nUsage: /showexpired on|off", status))
+	}
+}
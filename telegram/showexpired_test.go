@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+func TestShowExpiredCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	addTestCourse(t, b.db, "current", nil)
+	addTestCourse(t, b.db, "lapsed", func(c *database.Course) { c.ExpiresAt = time.Now().Add(-time.Hour) })
+
+	// reply runs a command and returns the one message sent back
+	reply := func(text string) string {
+		fake.reset()
+		message := testMessage(userID, text)
+		switch command, args, _ := strings.Cut(text, " "); command {
+		case "/showexpired":
+			b.handleShowExpiredCommand(message, args)
+		case "/browse":
+			b.handleBrowseCommand(message, args)
+		case "/popular":
+			b.handlePopularCommand(message)
+		}
+		texts := textsTo(fake.sent("sendMessage"), userID)
+		if len(texts) != 1 {
+			t.Fatalf("%s: sent %d replies, want 1", text, len(texts))
+		}
+		return texts[0]
+	}
+
+	for _, step := range []struct {
+		setting     string
+		wantStatus  string
+		wantExpired bool
+	}{
+		{"", "hidden", false}, // Default
+		{"on", "shown", true},
+		{"off", "hidden", false},
+	} {
+		if step.setting != "" {
+			reply("/showexpired " + step.setting)
+		}
+		if status := reply("/showexpired"); !strings.Contains(status, "Expired courses are "+step.wantStatus) {
+			t.Errorf("after %q: status = %q, want %s", step.setting, status, step.wantStatus)
+		}
+
+		for _, command := range []string{"/browse Development", "/popular"} {
+			text := reply(command)
+			if !strings.Contains(text, "Course current") {
+				t.Errorf("after %q: %s lacks the current course:\n%s", step.setting, command, text)
+			}
+			if shown := strings.Contains(text, "Course lapsed"); shown != step.wantExpired {
+				t.Errorf("after %q: %s shows the expired course = %v, want %v", step.setting, command, shown, step.wantExpired)
+			}
+		}
+	}
+}
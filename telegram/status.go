@@ -0,0 +1,126 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// totalCoursesCacheTTL bounds how often /status counts the courses table
+const totalCoursesCacheTTL = 5 * time.Minute
+
+// botStatus tracks uptime and scan activity shown by /status
+type botStatus struct {
+	mu             sync.Mutex
+	startedAt      time.Time
+	lastScan       time.Time
+	lastFound      int
+	totalCourses   int
+	totalCountedAt time.Time
+}
+
+// SetStartTime records when the process started, for uptime reporting
+func (b *Bot) SetStartTime(startedAt time.Time) {
+	b.status.mu.Lock()
+	defer b.status.mu.Unlock()
+	b.status.startedAt = startedAt
+}
+
+// RecordScan records the completion of a scan and how many new courses it found
+func (b *Bot) RecordScan(found int) {
+	b.status.mu.Lock()
+	defer b.status.mu.Unlock()
+	b.status.lastScan = time.Now()
+	b.status.lastFound = found
+	// New courses were stored, so recount on the next /status
+	b.status.totalCountedAt = time.Time{}
+}
+
+// totalCourses returns the cached course count, refreshing it when stale
+func (b *Bot) totalCourses() int {
+	b.status.mu.Lock()
+	defer b.status.mu.Unlock()
+
+	if time.Since(b.status.totalCountedAt) > totalCoursesCacheTTL {
+		count, err := b.db.CountCourses()
+		if err != nil {
+			log.Printf("Failed to count courses: %v", err)
+		} else {
+			b.status.totalCourses = count
+			b.status.totalCountedAt = time.Now()
+		}
+	}
+	return b.status.totalCourses
+}
+
+func (b *Bot) handleStatusCommand(message *tgbotapi.Message) {
+	total := b.totalCourses()
+
+	b.status.mu.Lock()
+	text := formatStatus(time.Now(), b.status.startedAt, b.status.lastScan, b.status.lastFound, total)
+	b.status.mu.Unlock()
+
+	unread, err := b.db.UnreadCount(message.From.ID)
+	if err != nil {
+		log.Printf("Failed to count unread courses: %v", err)
+	} else if unread > 0 {
+		text += fmt.Sprintf("\n📬 Unread courses for you: %d (/markread to clear)", unread)
+	}
+
+	b.sendMessage(message.Chat.ID, text)
+}
+
+func (b *Bot) handleMarkReadCommand(message *tgbotapi.Message) {
+	cleared, err := b.db.MarkAllRead(message.From.ID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to mark courses as read. Please try again.")
+		log.Printf("Failed to mark courses read: %v", err)
+		return
+	}
+
+	if cleared == 0 {
+		b.sendMessage(message.Chat.ID, "📭 You're all caught up.")
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Marked %d courses as read.", cleared))
+}
+
+func formatStatus(now, startedAt, lastScan time.Time, lastFound, totalCourses int) string {
+	uptime := "unknown"
+	if !startedAt.IsZero() {
+		uptime = formatDuration(now.Sub(startedAt))
+	}
+
+	lastScanText := "no scan completed yet"
+	if !lastScan.IsZero() {
+		lastScanText = fmt.Sprintf("%s ago (%d new courses)", formatDuration(now.Sub(lastScan)), lastFound)
+	}
+
+	return fmt.Sprintf(`🤖 Bot Status
+
+✅ Online for %s
+🔍 Last scan: %s
+📚 Courses tracked: %d`, uptime, lastScanText, totalCourses)
+}
+
+// formatDuration renders a duration compactly, e.g. "2d 3h", "5h 12m" or "3m"
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
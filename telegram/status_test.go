@@ -0,0 +1,87 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "less than a minute"},
+		{3 * time.Minute, "3m"},
+		{5*time.Hour + 12*time.Minute, "5h 12m"},
+		{51*time.Hour + 40*time.Minute, "2d 3h"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatStatus(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	text := formatStatus(now, now.Add(-26*time.Hour), now.Add(-7*time.Minute), 4, 1250)
+	for _, want := range []string{"Online for 1d 2h", "Last scan: 7m ago (4 new courses)", "Courses tracked: 1250"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("status lacks %q:\n%s", want, text)
+		}
+	}
+
+	text = formatStatus(now, time.Time{}, time.Time{}, 0, 0)
+	for _, want := range []string{"Online for unknown", "Last scan: no scan completed yet"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("status before the first scan lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestStatusCommandCountsNewCourses(t *testing.T) {
+	b, fake := newTestBot(t)
+	b.SetStartTime(time.Now().Add(-time.Hour))
+	addTestCourse(t, b.db, "go-basics", nil)
+
+	b.handleStatusCommand(testMessage(42, "/status"))
+	addTestCourse(t, b.db, "rust-basics", nil)
+	b.RecordScan(1)
+	b.handleStatusCommand(testMessage(42, "/status"))
+
+	texts := textsTo(fake.sent("sendMessage"), 42)
+	if len(texts) != 2 || !strings.Contains(texts[0], "Courses tracked: 1") || !strings.Contains(texts[1], "Courses tracked: 2") {
+		t.Errorf("status texts = %q, want the count refreshed after a scan", texts)
+	}
+}
+
+func TestStatusShowsUnreadCount(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	for _, slug := range []string{"go-basics", "rust-basics"} {
+		course := addTestCourse(t, b.db, slug, nil)
+		if err := b.db.MarkDelivered(userID, course.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b.handleStatusCommand(testMessage(userID, "/status"))
+	b.handleMarkReadCommand(testMessage(userID, "/markread"))
+	b.handleStatusCommand(testMessage(userID, "/status"))
+
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 3 {
+		t.Fatalf("got %d replies, want 3", len(texts))
+	}
+	if !strings.Contains(texts[0], "Unread courses for you: 2") {
+		t.Errorf("status lacks the unread count:\n%s", texts[0])
+	}
+	if !strings.Contains(texts[1], "Marked 2 courses as read") {
+		t.Errorf("/markread replied %q", texts[1])
+	}
+	if strings.Contains(texts[2], "Unread") {
+		t.Errorf("status after /markread still shows unread courses:\n%s", texts[2])
+	}
+}
@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const timezoneHelp = "Use an IANA zone name such as Europe/Madrid or America/New_York. The full list is at https://en.wikipedia.org/wiki/List_of_tz_database_time_zones"
+
+// SetLocation sets the timezone used for times in channel posts and for
+// users who have not chosen their own
+func (b *Bot) SetLocation(loc *time.Location) {
+	b.location = loc
+}
+
+// userLocation returns the user's chosen timezone, falling back to the bot's
+func (b *Bot) userLocation(userID int64) *time.Location {
+	userFilter, err := b.filterEngine.GetUserFilter(userID)
+	if err != nil || userFilter.Timezone == "" {
+		return b.location
+	}
+
+	loc, err := loadUserLocation(userFilter.Timezone)
+	if err != nil {
+		return b.location
+	}
+	return loc
+}
+
+// loadUserLocation resolves a zone name typed by a user. "Local" is refused
+// because it names the server's zone, not a real place.
+func loadUserLocation(name string) (*time.Location, error) {
+	if name == "" || strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+func (b *Bot) handleTimezoneCommand(message *tgbotapi.Message, args string) {
+	name := strings.TrimSpace(args)
+	if name == "" {
+		current := b.userLocation(message.From.ID)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("🕒 Your timezone: %s\n\nUsage: /timezone <zone>\n%s", current, timezoneHelp))
+		return
+	}
+
+	if strings.EqualFold(name, "off") {
+		if err := b.filterEngine.SetTimezone(message.From.ID, ""); err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+			log.Printf("Failed to clear timezone: %v", err)
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Timezone reset to the default (%s).", b.location))
+		return
+	}
+
+	loc, err := loadUserLocation(name)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unknown timezone: %s\n%s", name, timezoneHelp))
+		return
+	}
+
+	if err := b.filterEngine.SetTimezone(message.From.ID, loc.String()); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to save your preferences. Please try again.")
+		log.Printf("Failed to save timezone: %v", err)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Timezone set to %s. It's %s there now.", loc, time.Now().In(loc).Format("15:04")))
+}
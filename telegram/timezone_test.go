@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+func TestTimezoneCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	for _, name := range []string{"Mars/Olympus_Mons", "Local", "europe madrid"} {
+		fake.reset()
+		b.handleTimezoneCommand(testMessage(userID, "/timezone "+name), name)
+		texts := textsTo(fake.sent("sendMessage"), userID)
+		if len(texts) != 1 || !strings.Contains(texts[0], "Unknown timezone") || !strings.Contains(texts[0], "IANA") {
+			t.Errorf("/timezone %s replied %q, want a rejection with how to find zones", name, texts)
+		}
+	}
+	if loc := b.userLocation(userID); loc != time.UTC {
+		t.Fatalf("rejected zones changed the user's timezone to %s", loc)
+	}
+
+	b.handleTimezoneCommand(testMessage(userID, "/timezone Europe/Madrid"), "Europe/Madrid")
+	madrid := b.userLocation(userID)
+	if madrid.String() != "Europe/Madrid" {
+		t.Fatalf("user timezone = %s, want Europe/Madrid", madrid)
+	}
+
+	// Direct messages use the user's zone; the channel keeps the bot's
+	course := addTestCourse(t, b.db, "go-basics", func(c *database.Course) {
+		c.ExpiresAt = time.Date(time.Now().Year()+1, 7, 1, 10, 0, 0, 0, time.UTC)
+	})
+	fake.reset()
+	if err := b.sendCourseToUser(userID, &course); err != nil {
+		t.Fatal(err)
+	}
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 || !strings.Contains(texts[0], "Jul 1, 12:00 CEST") {
+		t.Errorf("direct message = %q, want the expiry in Madrid time", texts)
+	}
+	if text := b.formatCourseMessage(&course, b.location); !strings.Contains(text, "Jul 1, 10:00 UTC") {
+		t.Errorf("channel text lacks the expiry in UTC:\n%s", text)
+	}
+
+	b.handleTimezoneCommand(testMessage(userID, "/timezone off"), "off")
+	if loc := b.userLocation(userID); loc != time.UTC {
+		t.Errorf("after /timezone off the user's zone is %s, want the default", loc)
+	}
+}
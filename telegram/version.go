@@ -0,0 +1,25 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/version"
+)
+
+// SetVersionAdminOnly restricts /version to administrators
+func (b *Bot) SetVersionAdminOnly(adminOnly bool) {
+	b.versionAdminOnly = adminOnly
+}
+
+func (b *Bot) handleVersionCommand(message *tgbotapi.Message) {
+	if b.versionAdminOnly && !b.requireAdmin(message) {
+		return
+	}
+	b.sendMessage(message.Chat.ID, formatVersion(version.Get()))
+}
+
+func formatVersion(info version.Info) string {
+	return fmt.Sprintf("📦 Version: %s\n🔖 Commit: %s\n📅 Built: %s\n🐹 Go: %s",
+		info.Version, info.Commit, info.BuildDate, info.GoVersion)
+}
@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"udemy-course-notifier/version"
+)
+
+// injectVersion sets the build variables as -ldflags -X would, restoring
+// them when the test ends
+func injectVersion(t *testing.T, v, commit, date string) {
+	t.Helper()
+	oldVersion, oldCommit, oldDate := version.Version, version.Commit, version.BuildDate
+	version.Version, version.Commit, version.BuildDate = v, commit, date
+	t.Cleanup(func() {
+		version.Version, version.Commit, version.BuildDate = oldVersion, oldCommit, oldDate
+	})
+}
+
+func TestVersionCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	injectVersion(t, "1.4.0", "abc1234", "2026-10-01T12:00:00Z")
+	const userID = 42
+
+	b.handleMessage(testMessage(userID, "/version"))
+
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 {
+		t.Fatalf("sent %d replies, want 1", len(texts))
+	}
+	for _, want := range []string{"1.4.0", "abc1234", "2026-10-01T12:00:00Z", runtime.Version()} {
+		if !strings.Contains(texts[0], want) {
+			t.Errorf("/version reply lacks %q:\n%s", want, texts[0])
+		}
+	}
+}
+
+func TestVersionCommandAdminOnly(t *testing.T) {
+	b, fake := newTestBot(t)
+	injectVersion(t, "1.4.0", "abc1234", "2026-10-01T12:00:00Z")
+	const adminID, userID = 1, 42
+	b.SetAdminIDs([]int64{adminID})
+	b.SetVersionAdminOnly(true)
+
+	b.handleMessage(testMessage(userID, "/version"))
+	b.handleMessage(testMessage(adminID, "/version"))
+
+	calls := fake.sent("sendMessage")
+	if texts := textsTo(calls, userID); len(texts) != 1 || strings.Contains(texts[0], "1.4.0") {
+		t.Errorf("non-admin got %q, want a refusal without build details", texts)
+	}
+	if texts := textsTo(calls, adminID); len(texts) != 1 || !strings.Contains(texts[0], "1.4.0") {
+		t.Errorf("admin got %q, want the build details", texts)
+	}
+}
@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+const (
+	welcomeDefaultCourses = 5   // Courses sent by /welcome without a count
+	welcomeMaxCourses     = 10  // Most courses /welcome sends at once
+	welcomeScanLimit      = 200 // Recent courses searched for matches
+)
+
+// welcomeCourses picks up to limit courses, newest first, that have not
+// expired and that matches accepts
+func welcomeCourses(courses []database.Course, now time.Time, grace time.Duration, limit int, matches func(*database.Course) bool) []database.Course {
+	var picked []database.Course
+	for i := range courses {
+		if len(picked) >= limit {
+			break
+		}
+		if database.IsExpired(courses[i].ExpiresAt, now, grace) || !matches(&courses[i]) {
+			continue
+		}
+		picked = append(picked, courses[i])
+	}
+	return picked
+}
+
+// handleWelcomeCommand sends a new subscriber the most recent stored courses
+// that match their filter, so they don't wait for the next scan to see any.
+// Courses already sent to them are skipped.
+func (b *Bot) handleWelcomeCommand(message *tgbotapi.Message, args string) {
+	userID := message.From.ID
+
+	limit := welcomeDefaultCourses
+	if args = strings.TrimSpace(args); args != "" {
+		n, err := strconv.Atoi(args)
+		if err != nil || n < 1 {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("Usage: /welcome [count]\nSends up to %d recent courses matching your filter.", welcomeMaxCourses))
+			return
+		}
+		limit = n
+	}
+	if limit > welcomeMaxCourses {
+		limit = welcomeMaxCourses
+	}
+
+	recent, err := b.db.GetRecentCourses(welcomeScanLimit)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Failed to load recent courses. Please try again.")
+		log.Printf("Failed to get recent courses for welcome: %v", err)
+		return
+	}
+
+	courses := welcomeCourses(recent, time.Now(), b.db.ExpiryGrace(), limit, func(course *database.Course) bool {
+		delivered, err := b.db.WasDelivered(userID, course.ID)
+		if err != nil {
+			log.Printf("Failed to check delivery for user %d: %v", userID, err)
+			return false
+		}
+		if delivered {
+			return false
+		}
+		matches, err := b.filterEngine.ShouldNotifyCourse(course, userID)
+		if err != nil {
+			log.Printf("Failed to apply filter for user %d: %v", userID, err)
+			return false
+		}
+		return matches
+	})
+
+	if len(courses) == 0 {
+		b.sendMessage(message.Chat.ID, "📭 No recent courses match your filter yet. New ones will be sent as they're found.")
+		return
+	}
+
+	sent := 0
+	for i := range courses {
+		if b.dailyLimitReached(userID) {
+			break
+		}
+		if err := b.sendCourseToUser(userID, &courses[i]); err != nil {
+			log.Printf("Failed to send welcome course to user %d: %v", userID, err)
+			if sent == 0 {
+				b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ I couldn't send you a direct message. Open a private chat with @%s, press Start, and try /welcome again.",
+					b.api.Self.UserName))
+				return
+			}
+			break
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		b.sendMessage(message.Chat.ID, "📮 You've reached today's limit set with /maxperday. Try /welcome again tomorrow.")
+		return
+	}
+
+	if message.Chat.ID != userID {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ I sent you %d recent courses in a direct message.", sent))
+	}
+}
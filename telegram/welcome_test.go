@@ -0,0 +1,89 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+	"udemy-course-notifier/filters"
+)
+
+func TestWelcomeCourses(t *testing.T) {
+	now := time.Now()
+	courses := []database.Course{
+		{ID: 1, ExpiresAt: now.Add(time.Hour)},
+		{ID: 2, ExpiresAt: now.Add(-time.Hour)},
+		{ID: 3},
+		{ID: 4, ExpiresAt: now.Add(time.Hour)},
+		{ID: 5, ExpiresAt: now.Add(time.Hour)},
+	}
+	matchesAllBut4 := func(course *database.Course) bool { return course.ID != 4 }
+
+	tests := []struct {
+		limit int
+		want  []int
+	}{
+		{10, []int{1, 3, 5}},
+		{2, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, course := range welcomeCourses(courses, now, 0, tt.limit, matchesAllBut4) {
+			got = append(got, course.ID)
+		}
+		if formatIDs(got...) != formatIDs(tt.want...) {
+			t.Errorf("welcomeCourses(limit %d) = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestWelcomeCommandRespectsFilter(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+	err := b.filterEngine.SaveUserFilter(&filters.UserFilter{UserID: userID, Categories: []string{"Development"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(24 * time.Hour)
+	addTestCourse(t, b.db, "go-basics", func(c *database.Course) { c.ExpiresAt = later })
+	addTestCourse(t, b.db, "no-expiry", nil)
+	addTestCourse(t, b.db, "lapsed", func(c *database.Course) { c.ExpiresAt = time.Now().Add(-time.Hour) })
+	addTestCourse(t, b.db, "logo-design", func(c *database.Course) {
+		c.Category = "Design"
+		c.ExpiresAt = later
+	})
+
+	b.handleMessage(testMessage(userID, "/welcome"))
+
+	text := strings.Join(textsTo(fake.sent("sendMessage"), userID), "\n")
+	for _, want := range []string{"Course go-basics", "Course no-expiry"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("welcome batch lacks %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"Course lapsed", "Course logo-design"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("welcome batch includes %q:\n%s", unwanted, text)
+		}
+	}
+
+	// Courses already sent aren't sent again
+	fake.reset()
+	b.handleMessage(testMessage(userID, "/welcome"))
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 || !strings.Contains(texts[0], "No recent courses") {
+		t.Errorf("second /welcome replied %q, want nothing new", texts)
+	}
+}
+
+func TestWelcomeCommandUsage(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	b.handleMessage(testMessage(userID, "/welcome none"))
+
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 || !strings.Contains(texts[0], "Usage: /welcome") {
+		t.Errorf("/welcome none replied %q, want the usage", texts)
+	}
+}
@@ -0,0 +1,22 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleWhoamiCommand replies with the IDs needed when setting up the bot,
+// e.g. admin_ids or a group's chat ID
+func (b *Bot) handleWhoamiCommand(message *tgbotapi.Message) {
+	b.sendMessage(message.Chat.ID, formatWhoami(message))
+}
+
+func formatWhoami(message *tgbotapi.Message) string {
+	text := fmt.Sprintf("🪪 Your user ID: %d\n💬 Chat ID: %d\n📁 Chat type: %s",
+		message.From.ID, message.Chat.ID, message.Chat.Type)
+	if message.Chat.Title != "" {
+		text += "\n🏷️ Chat title: " + message.Chat.Title
+	}
+	return text
+}
@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestWhoamiCommand(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	b.handleMessage(testMessage(userID, "/whoami"))
+	texts := textsTo(fake.sent("sendMessage"), userID)
+	if len(texts) != 1 {
+		t.Fatalf("sent %d replies, want 1", len(texts))
+	}
+	for _, want := range []string{"Your user ID: 42", "Chat ID: 42", "Chat type: private"} {
+		if !strings.Contains(texts[0], want) {
+			t.Errorf("private reply lacks %q:\n%s", want, texts[0])
+		}
+	}
+	if strings.Contains(texts[0], "Chat title") {
+		t.Errorf("private reply shows a chat title:\n%s", texts[0])
+	}
+
+	const groupID = -1005550001111
+	message := testMessage(userID, "/whoami@test_bot")
+	message.Chat = &tgbotapi.Chat{ID: groupID, Type: "supergroup", Title: "Go Learners"}
+	b.handleMessage(message)
+	texts = textsTo(fake.sent("sendMessage"), groupID)
+	if len(texts) != 1 {
+		t.Fatalf("sent %d replies to the group, want 1", len(texts))
+	}
+	for _, want := range []string{"Your user ID: 42", "Chat ID: -1005550001111", "Chat type: supergroup", "Chat title: Go Learners"} {
+		if !strings.Contains(texts[0], want) {
+			t.Errorf("group reply lacks %q:\n%s", want, texts[0])
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"udemy-course-notifier/database"
+)
+
+// maxWishlistAlertAttempts is how many times a price alert may fail to send
+// before it is dropped, e.g. because the user blocked the bot
+const maxWishlistAlertAttempts = 5
+
+// NotifyWishlistPriceDrops DMs users whose wishlisted courses were just
+// scraped at a better price than last seen (e.g. a coupon came back). Every
+// price seen is recorded, so a course that went paid and then free again
+// is caught.
+func (b *Bot) NotifyWishlistPriceDrops(courses []database.Course) {
+	if len(courses) == 0 {
+		return
+	}
+
+	watches, err := b.db.GetWishlistWatches()
+	if err != nil {
+		log.Printf("Failed to load wishlist for price checks: %v", err)
+		return
+	}
+
+	watchesByURL := make(map[string][]database.WishlistWatch)
+	for _, watch := range watches {
+		key := database.CanonicalURL(watch.CourseURL)
+		watchesByURL[key] = append(watchesByURL[key], watch)
+	}
+
+	for _, course := range courses {
+		for _, watch := range watchesByURL[database.CanonicalURL(course.URL)] {
+			if !database.PriceImproved(watch.LastKnownPrice, watch.LastKnownDiscount, course.Price, course.Discount) {
+				if course.Price != watch.LastKnownPrice || course.Discount != watch.LastKnownDiscount {
+					if err := b.db.UpdateWishlistPrice(watch.ID, course.Price, course.Discount); err != nil {
+						log.Printf("Failed to update wishlist price: %v", err)
+					}
+				}
+				continue
+			}
+
+			header := "🎉 " + b.format.bold("A course on your wishlist is now free!")
+			if !database.IsFreePrice(course.Price, course.Discount) {
+				header = "📉 " + b.format.bold("A course on your wishlist got cheaper!")
+			}
+			text := header + "\n\n🎓 " + b.format.bold(course.Title) +
+				b.format.escape(fmt.Sprintf("\n💰 Price: %s %s\n🔗 %s", course.Price, course.Discount, b.courseLink(course.URL)))
+
+			msg := tgbotapi.NewMessage(watch.UserID, text)
+			msg.ParseMode = b.format.mode
+			msg.DisableWebPagePreview = true
+			b.dms.wait()
+			if _, err := b.send(msg); err != nil {
+				// The old price is kept so the next scan tries again, until
+				// the attempts run out and the new price is recorded unsent
+				if watch.AlertAttempts+1 < maxWishlistAlertAttempts {
+					log.Printf("Failed to send wishlist alert to user %d: %v", watch.UserID, err)
+					if err := b.db.RecordWishlistAlertFailure(watch.ID); err != nil {
+						log.Printf("Failed to record wishlist alert failure: %v", err)
+					}
+					continue
+				}
+				log.Printf("Dropping wishlist alert for user %d after %d failed attempts: %v", watch.UserID, maxWishlistAlertAttempts, err)
+			}
+
+			if err := b.db.UpdateWishlistPrice(watch.ID, course.Price, course.Discount); err != nil {
+				log.Printf("Failed to update wishlist price: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	"udemy-course-notifier/database"
+)
+
+func TestNotifyWishlistPriceDrops(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	course := addTestCourse(t, b.db, "drops", func(c *database.Course) {
+		c.Price, c.Discount = "$19.99", "50% off"
+	})
+	if err := b.db.AddToWishlist(userID, course.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	scrape := func(price, discount string) []string {
+		fake.reset()
+		seen := course
+		seen.Price, seen.Discount = price, discount
+		b.NotifyWishlistPriceDrops([]database.Course{seen})
+		return textsTo(fake.sent("sendMessage"), userID)
+	}
+
+	if texts := scrape("$19.99", "50% off"); len(texts) != 0 {
+		t.Errorf("unchanged price sent %q", texts)
+	}
+	if texts := scrape("$9.99", "80% off"); len(texts) != 1 || !strings.Contains(texts[0], "cheaper") {
+		t.Errorf("bigger discount sent %q, want one cheaper alert", texts)
+	}
+	if texts := scrape("$9.99", "80% off"); len(texts) != 0 {
+		t.Errorf("repeated price sent %q, want nothing", texts)
+	}
+
+	// Going paid and then free again is caught because every price is recorded
+	if texts := scrape("$49.99", ""); len(texts) != 0 {
+		t.Errorf("price rise sent %q", texts)
+	}
+	if texts := scrape("Free", "100% off"); len(texts) != 1 || !strings.Contains(texts[0], "free") {
+		t.Errorf("free again sent %q, want one free alert", texts)
+	}
+}
+
+func TestNotifyWishlistPriceDropsRetriesFailedSend(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	course := addTestCourse(t, b.db, "retry", func(c *database.Course) {
+		c.Price, c.Discount = "$19.99", "50% off"
+	})
+	if err := b.db.AddToWishlist(userID, course.ID); err != nil {
+		t.Fatal(err)
+	}
+	free := course
+	free.Price, free.Discount = "Free", "100% off"
+
+	fake.failWith(func(call apiCall) (int, string) { return 403, "Forbidden: bot was blocked by the user" })
+	b.NotifyWishlistPriceDrops([]database.Course{free})
+
+	fake.failWith(nil)
+	fake.reset()
+	b.NotifyWishlistPriceDrops([]database.Course{free})
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 {
+		t.Errorf("alert after a failed send = %q, want it sent again", texts)
+	}
+}
+
+func TestNotifyWishlistPriceDropsGivesUp(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	course := addTestCourse(t, b.db, "blocked", func(c *database.Course) {
+		c.Price, c.Discount = "$19.99", "50% off"
+	})
+	if err := b.db.AddToWishlist(userID, course.ID); err != nil {
+		t.Fatal(err)
+	}
+	free := course
+	free.Price, free.Discount = "Free", "100% off"
+
+	fake.failWith(func(call apiCall) (int, string) { return 403, "Forbidden: bot was blocked by the user" })
+	for i := 0; i < maxWishlistAlertAttempts+2; i++ {
+		fake.reset()
+		b.NotifyWishlistPriceDrops([]database.Course{free})
+		want := 1
+		if i >= maxWishlistAlertAttempts {
+			want = 0 // Dropped, with the free price recorded
+		}
+		if attempts := len(textsTo(fake.sent("sendMessage"), userID)); attempts != want {
+			t.Errorf("scan %d tried %d sends, want %d", i+1, attempts, want)
+		}
+	}
+
+	// A later drop is a new alert with its own attempts
+	fake.failWith(nil)
+	fake.reset()
+	cheaper := course
+	cheaper.Price, cheaper.Discount = "$9.99", "80% off"
+	b.NotifyWishlistPriceDrops([]database.Course{{URL: course.URL, Title: course.Title, Price: "$49.99"}})
+	b.NotifyWishlistPriceDrops([]database.Course{cheaper})
+	if texts := textsTo(fake.sent("sendMessage"), userID); len(texts) != 1 || !strings.Contains(texts[0], "cheaper") {
+		t.Errorf("new price drop sent %q, want one alert", texts)
+	}
+}
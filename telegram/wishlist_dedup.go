@@ -0,0 +1,39 @@
+package telegram
+
+import (
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+// dedupeWishlist collapses wishlist courses that are the same course stored
+// under several IDs, as happened before cross-source dedup, so /wishlist lists
+// each course once. Courses are matched by canonical URL. The kept entry is
+// the best copy: one that has not expired, then the most recently posted, as
+// its coupon is the likeliest to still work. It takes the position of the
+// group's first entry.
+func dedupeWishlist(courses []database.Course, now time.Time, grace time.Duration) []database.Course {
+	better := func(a, b database.Course) bool {
+		aExpired := database.IsExpired(a.ExpiresAt, now, grace)
+		bExpired := database.IsExpired(b.ExpiresAt, now, grace)
+		if aExpired != bExpired {
+			return !aExpired
+		}
+		return a.PostedAt.After(b.PostedAt)
+	}
+
+	index := make(map[string]int)
+	var deduped []database.Course
+	for _, course := range courses {
+		key := database.CanonicalURL(course.URL)
+		if i, seen := index[key]; seen {
+			if better(course, deduped[i]) {
+				deduped[i] = course
+			}
+			continue
+		}
+		index[key] = len(deduped)
+		deduped = append(deduped, course)
+	}
+	return deduped
+}
@@ -0,0 +1,59 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"udemy-course-notifier/database"
+)
+
+func TestDedupeWishlist(t *testing.T) {
+	now := time.Now()
+	courses := []database.Course{
+		{ID: 1, URL: "https://www.udemy.com/course/go-basics/?couponCode=OLD", PostedAt: now.Add(-48 * time.Hour)},
+		{ID: 2, URL: "https://www.udemy.com/course/python/", PostedAt: now.Add(-24 * time.Hour)},
+		{ID: 3, URL: "https://udemy.com/course/go-basics?couponCode=NEW", PostedAt: now.Add(-time.Hour)},
+		{ID: 4, URL: "https://www.udemy.com/course/python/?couponCode=LAPSED", PostedAt: now, ExpiresAt: now.Add(-time.Hour)},
+	}
+
+	got := dedupeWishlist(courses, now, 0)
+
+	// The newer go-basics copy replaces the older one in its place; the
+	// expired python copy loses to the live one despite being newer
+	var ids []int
+	for _, course := range got {
+		ids = append(ids, course.ID)
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 2 {
+		t.Errorf("dedupeWishlist kept IDs %v, want [3 2]", ids)
+	}
+}
+
+func TestWishlistCommandCollapsesDuplicates(t *testing.T) {
+	b, fake := newTestBot(t)
+	const userID = 42
+
+	legacy := addTestCourse(t, b.db, "legacy", func(c *database.Course) {
+		c.URL = "https://www.udemy.com/course/go-basics/?couponCode=OLD"
+		c.Title = "Go Basics (old coupon)"
+		c.ExpiresAt = time.Now().Add(-time.Hour)
+	})
+	current := addTestCourse(t, b.db, "current", func(c *database.Course) {
+		c.URL = "https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fwww.udemy.com%2Fcourse%2Fgo-basics%2F%3FcouponCode%3DNEW"
+		c.Title = "Go Basics (new coupon)"
+		c.ExpiresAt = time.Now().Add(24 * time.Hour)
+	})
+	for _, course := range []database.Course{legacy, current} {
+		if err := b.db.AddToWishlist(userID, course.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b.handleMessage(testMessage(userID, "/wishlist"))
+
+	text := strings.Join(textsTo(fake.sent("sendMessage"), userID), "\n")
+	if !strings.Contains(text, "new coupon") || strings.Contains(text, "old coupon") {
+		t.Errorf("/wishlist should list only the live copy of the course:\n%s", text)
+	}
+}
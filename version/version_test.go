@@ -0,0 +1,28 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestGetReportsInjectedValues(t *testing.T) {
+	oldVersion, oldCommit, oldDate := Version, Commit, BuildDate
+	defer func() { Version, Commit, BuildDate = oldVersion, oldCommit, oldDate }()
+
+	Version, Commit, BuildDate = "1.4.0", "abc1234", "2026-10-01T12:00:00Z"
+	want := Info{Version: "1.4.0", Commit: "abc1234", BuildDate: "2026-10-01T12:00:00Z", GoVersion: runtime.Version()}
+	if got := Get(); got != want {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetWithoutInjectedValues(t *testing.T) {
+	oldCommit, oldDate := Commit, BuildDate
+	defer func() { Commit, BuildDate = oldCommit, oldDate }()
+
+	Commit, BuildDate = "", ""
+	got := Get()
+	if got.Commit == "" || got.BuildDate == "" {
+		t.Errorf("Get() = %+v, want empty details filled in", got)
+	}
+}